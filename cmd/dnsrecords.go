@@ -0,0 +1,163 @@
+/*
+Copyright © 2022 IAB Technology Laboratory, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/IABTechLab/adscert/internal/formats"
+	"github.com/IABTechLab/adscert/internal/keyring"
+	"github.com/IABTechLab/adscert/pkg/adscert/logger"
+	"github.com/spf13/cobra"
+)
+
+// dnsrecordsCmd represents the dnsrecords command
+var (
+	dnsrecordsParams = &dnsrecordsParameters{}
+
+	dnsrecordsCmd = &cobra.Command{
+		Use:   "dnsrecords",
+		Short: "Generates the ads.cert DNS TXT records to publish for the keys in a keyring.",
+		Long: `Reads the keyring and prints the _delivery._adscert.<callsign> keys record for
+each ads.cert Call Sign, listing every active or pending key so that new keys are
+published ahead of rotation and marking the primary key, and the _adscert.<invoking domain> policy record for
+each invoking domain.  Output is either BIND zone file lines or JSON.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			records, err := generateDNSRecords(dnsrecordsParams)
+			if err != nil {
+				logger.Fatalf("Failed to generate DNS records: %v", err)
+			}
+			if err := printDNSRecords(os.Stdout, records, dnsrecordsParams.format); err != nil {
+				logger.Fatalf("Failed to print DNS records: %v", err)
+			}
+		},
+	}
+)
+
+type dnsrecordsParameters struct {
+	keyringPath     string
+	callsigns       []string
+	invokingDomains []string
+	ttl             int
+	format          string
+}
+
+// dnsRecord is a TXT record to publish, with its value split into DNS
+// character-strings.
+type dnsRecord struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	TTL     int      `json:"ttl"`
+	Value   string   `json:"value"`
+	Strings []string `json:"strings"`
+}
+
+func init() {
+	rootCmd.AddCommand(dnsrecordsCmd)
+
+	dnsrecordsCmd.Flags().StringVar(&dnsrecordsParams.keyringPath, "keyring_path", keyring.DefaultKeyringFile, "path to the adscertkeyring.json file")
+	dnsrecordsCmd.Flags().StringArrayVar(&dnsrecordsParams.callsigns, "callsign", nil, "ads.cert Call Sign to generate keys records for; repeat for more (defaults to every Call Sign in the keyring)")
+	dnsrecordsCmd.Flags().StringArrayVar(&dnsrecordsParams.invokingDomains, "invoking_domain", nil, "invoking domain to generate a policy record for, as domain=callsign (or just domain when generating records for a single Call Sign); repeat for more")
+	dnsrecordsCmd.Flags().IntVar(&dnsrecordsParams.ttl, "ttl", 3600, "TTL in seconds for the generated records")
+	dnsrecordsCmd.Flags().StringVar(&dnsrecordsParams.format, "format", "bind", "output format, bind or json")
+}
+
+func generateDNSRecords(params *dnsrecordsParameters) ([]dnsRecord, error) {
+	configList, err := keyring.ReadKeyringFileFromPath(params.keyringPath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read keyring %s: %v", params.keyringPath, err)
+	}
+
+	callsigns := params.callsigns
+	if len(callsigns) == 0 {
+		callsigns = configList.GetCallSigns()
+	}
+
+	var records []dnsRecord
+	for _, callsign := range callsigns {
+		keys := &formats.AdsCertKeys{}
+		for _, publicKeyBase64 := range keyring.GetPublishablePublicKeys(configList, callsign) {
+			publicKeyBytes, err := formats.ParseBase64EncodedKey(publicKeyBase64, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid public key %s for %s: %v", publicKeyBase64, callsign, err)
+			}
+			keys.PublicKeys = append(keys.PublicKeys, formats.ParsedPublicKey{
+				PublicKeyBytes: publicKeyBytes,
+				KeyAlias:       formats.ExtractKeyAliasFromPublicKeyBase64(publicKeyBase64),
+			})
+		}
+		if primaryKey, ok := keyring.GetPrimaryPublicKey(configList, callsign); ok {
+			keys.PrimaryKeyAlias = formats.ExtractKeyAliasFromPublicKeyBase64(primaryKey)
+		}
+		value, err := formats.EncodeAdsCertKeysRecord(keys)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode keys record for %s: %w", callsign, err)
+		}
+		records = append(records, newTXTRecord("_delivery._adscert."+callsign, params.ttl, value))
+	}
+
+	for _, invokingDomain := range params.invokingDomains {
+		domain, callsign := invokingDomain, ""
+		if pair := strings.SplitN(invokingDomain, "=", 2); len(pair) == 2 {
+			domain, callsign = pair[0], pair[1]
+		} else if len(callsigns) == 1 {
+			callsign = callsigns[0]
+		} else {
+			return nil, fmt.Errorf("invoking domain %s must name its callsign as domain=callsign", invokingDomain)
+		}
+		value, err := formats.EncodeAdsCertPolicyRecord(&formats.AdsCertPolicy{CanonicalCallsignDomain: callsign})
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode policy record for %s: %w", domain, err)
+		}
+		records = append(records, newTXTRecord("_adscert."+domain, params.ttl, value))
+	}
+
+	return records, nil
+}
+
+func newTXTRecord(name string, ttl int, value string) dnsRecord {
+	return dnsRecord{
+		Name:    name,
+		Type:    "TXT",
+		TTL:     ttl,
+		Value:   value,
+		Strings: formats.SplitTXTRecordStrings(value),
+	}
+}
+
+func printDNSRecords(w io.Writer, records []dnsRecord, format string) error {
+	switch format {
+	case "bind":
+		for _, r := range records {
+			quoted := make([]string, len(r.Strings))
+			for i, s := range r.Strings {
+				quoted[i] = `"` + s + `"`
+			}
+			fmt.Fprintf(w, "%s.\t%d\tIN\t%s\t%s\n", r.Name, r.TTL, r.Type, strings.Join(quoted, " "))
+		}
+		return nil
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	default:
+		return fmt.Errorf("unknown output format %q, want bind or json", format)
+	}
+}
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/IABTechLab/adscert/internal/formats"
+	"github.com/IABTechLab/adscert/internal/keyring"
+)
+
+func testPublicKey(b byte) string {
+	return formats.EncodeKeyBase64(bytes.Repeat([]byte{b}, 32))
+}
+
+func writeTestKeyring(t *testing.T) string {
+	configList := &keyring.AdsCertCallSignConfigList{
+		Domains: []*keyring.AdsCertCallSignConfig{
+			{
+				Domain: "example.com",
+				Realms: []*keyring.AdsCertRealm{
+					{
+						Realm: "east",
+						Keys: []*keyring.AdsCertKeyConfig{
+							{KeyID: "archived", PublicKeyBase64: testPublicKey(4), Status: keyring.KeyStatusArchived},
+							{KeyID: "pending", PublicKeyBase64: testPublicKey(3), Status: keyring.KeyStatusCreated},
+							{KeyID: "primary", PublicKeyBase64: testPublicKey(1), Status: keyring.KeyStatusPrimary},
+						},
+					},
+					{
+						Realm: "west",
+						Keys: []*keyring.AdsCertKeyConfig{
+							{KeyID: "secondary", PublicKeyBase64: testPublicKey(2), Status: keyring.KeyStatusSecondary},
+							{KeyID: "shared", PublicKeyBase64: testPublicKey(1), Status: keyring.KeyStatusPrimary},
+						},
+					},
+				},
+			},
+			{
+				Domain: "example.net",
+				Realms: []*keyring.AdsCertRealm{
+					{
+						Realm: "default",
+						Keys: []*keyring.AdsCertKeyConfig{
+							{KeyID: "primary", PublicKeyBase64: testPublicKey(5), Status: keyring.KeyStatusPrimary},
+						},
+					},
+				},
+			},
+		},
+	}
+	path := filepath.Join(t.TempDir(), "adscertkeyring.json")
+	if err := keyring.WriteKeyringFileToPath(path, configList); err != nil {
+		t.Fatalf("WriteKeyringFileToPath(): %v", err)
+	}
+	return path
+}
+
+func TestGenerateDNSRecords(t *testing.T) {
+	keyringPath := writeTestKeyring(t)
+	keysValue := "v=adcrtd k=x25519 h=sha256 primary=AQEBAQ p=" + testPublicKey(1) + " p=" + testPublicKey(2) + " p=" + testPublicKey(3)
+
+	records, err := generateDNSRecords(&dnsrecordsParameters{
+		keyringPath:     keyringPath,
+		callsigns:       []string{"example.com"},
+		invokingDomains: []string{"invoking.com"},
+		ttl:             300,
+	})
+	if err != nil {
+		t.Fatalf("generateDNSRecords(): %v", err)
+	}
+	want := []dnsRecord{
+		{Name: "_delivery._adscert.example.com", Type: "TXT", TTL: 300, Value: keysValue, Strings: []string{keysValue}},
+		{Name: "_adscert.invoking.com", Type: "TXT", TTL: 300, Value: "v=adpf a=example.com", Strings: []string{"v=adpf a=example.com"}},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Fatalf("generateDNSRecords(): got %+v, want %+v", records, want)
+	}
+
+	parsed, err := formats.DecodeAdsCertKeysRecord(records[0].Value)
+	if err != nil {
+		t.Fatalf("DecodeAdsCertKeysRecord(): %v", err)
+	}
+	if primary, ok := parsed.GetPrimaryKey(); !ok || primary.KeyAlias != "AQEBAQ" {
+		t.Errorf("GetPrimaryKey(): got %+v, want alias AQEBAQ", primary)
+	}
+
+	var bind bytes.Buffer
+	if err := printDNSRecords(&bind, records, "bind"); err != nil {
+		t.Fatalf("printDNSRecords() bind: %v", err)
+	}
+	wantBind := "_delivery._adscert.example.com.\t300\tIN\tTXT\t\"" + keysValue + "\"\n" +
+		"_adscert.invoking.com.\t300\tIN\tTXT\t\"v=adpf a=example.com\"\n"
+	if bind.String() != wantBind {
+		t.Errorf("printDNSRecords() bind: got %q, want %q", bind.String(), wantBind)
+	}
+
+	var out bytes.Buffer
+	if err := printDNSRecords(&out, records, "json"); err != nil {
+		t.Fatalf("printDNSRecords() json: %v", err)
+	}
+	var got []dnsRecord
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("printDNSRecords() json: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("printDNSRecords() json: got %+v, want %+v", got, want)
+	}
+
+	if err := printDNSRecords(&out, records, "yaml"); err == nil {
+		t.Errorf("printDNSRecords() yaml: got nil error, want error")
+	}
+}
+
+func TestGenerateDNSRecords_InvokingDomainCallsign(t *testing.T) {
+	keyringPath := writeTestKeyring(t)
+	testCases := []struct {
+		desc            string
+		callsigns       []string
+		invokingDomains []string
+		wantErr         bool
+	}{
+		{"single callsign", []string{"example.com"}, []string{"invoking.com"}, false},
+		{"explicit callsign", nil, []string{"invoking.com=example.net"}, false},
+		{"ambiguous callsign", nil, []string{"invoking.com"}, true},
+	}
+	for _, tC := range testCases {
+		_, err := generateDNSRecords(&dnsrecordsParameters{
+			keyringPath:     keyringPath,
+			callsigns:       tC.callsigns,
+			invokingDomains: tC.invokingDomains,
+		})
+		if (err != nil) != tC.wantErr {
+			t.Errorf("generateDNSRecords() %s: got error %v, want error %v", tC.desc, err, tC.wantErr)
+		}
+	}
+}
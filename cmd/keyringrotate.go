@@ -0,0 +1,116 @@
+/*
+Copyright © 2022 IAB Technology Laboratory, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/IABTechLab/adscert/internal/keyring"
+	"github.com/IABTechLab/adscert/internal/server"
+	"github.com/IABTechLab/adscert/pkg/adscert/keysecurity"
+	"github.com/IABTechLab/adscert/pkg/adscert/logger"
+	"github.com/benbjohnson/clock"
+	"github.com/spf13/cobra"
+)
+
+// keyringrotateCmd represents the keyringrotate command
+var (
+	keyringrotateParams = &keyringrotateParameters{}
+
+	keyringrotateCmd = &cobra.Command{
+		Use:   "keyringrotate",
+		Short: "Advances the keys of a keyring through their lifecycle.",
+		Long: `Reads the keyring and advances the keys of every realm through their lifecycle
+(created, activated, primary, secondary, archived), creating successor keys ahead
+of rotation, then saves the keyring.  With --interval, keeps running and rotates
+again every interval until interrupted.  New private keys are encrypted with the
+key encryption key at --key_encryption_key_uri.
+
+Keys from older keyrings, which have neither a status nor an encrypted private
+key, can't be used for signing: they are archived and replacement keys are
+created, so run keyringrotate once after upgrading such a keyring.
+
+Publish the DNS records generated by "adscert dnsrecords" after each rotation so
+that pending keys are visible to counterparties before they are used.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := keyringRotate(keyringrotateParams); err != nil {
+				logger.Fatalf("Failed to rotate keyring: %v", err)
+			}
+		},
+	}
+)
+
+type keyringrotateParameters struct {
+	keyringPath             string
+	keyEncryptionKeyURI     string
+	keyEncryptionKeysetFile string
+	insecurePlaintextKMS    bool
+	interval                time.Duration
+}
+
+func init() {
+	rootCmd.AddCommand(keyringrotateCmd)
+
+	keyringrotateCmd.Flags().StringVar(&keyringrotateParams.keyringPath, "keyring_path", keyring.DefaultKeyringFile, "path to the adscertkeyring.json file")
+	keyringrotateCmd.Flags().StringVar(&keyringrotateParams.keyEncryptionKeyURI, "key_encryption_key_uri", "", "URI of the key encryption key protecting new private keys, such as local-key-encryption://")
+	keyringrotateCmd.Flags().StringVar(&keyringrotateParams.keyEncryptionKeysetFile, "key_encryption_keyset_file", "", "cleartext Tink keyset (JSON) used for local-key-encryption:// key encryption keys")
+	keyringrotateCmd.Flags().BoolVar(&keyringrotateParams.insecurePlaintextKMS, "insecure_plaintext_kms", false, "If true, accepts insecure-plaintext-kms:// key encryption keys (testing only)")
+	keyringrotateCmd.Flags().DurationVar(&keyringrotateParams.interval, "interval", 0, "rotate again every interval until interrupted; 0 rotates once and exits")
+}
+
+func keyringRotate(params *keyringrotateParameters) error {
+	if params.keyEncryptionKeyURI == "" {
+		return errors.New("key encryption key URI is required")
+	}
+	if err := server.RegisterKeyEncryptionClients(params.keyEncryptionKeysetFile, params.insecurePlaintextKMS); err != nil {
+		return err
+	}
+	keyEncrypter, err := keysecurity.NewKeyEncrypterTinkAead()
+	if err != nil {
+		return err
+	}
+
+	configList, err := keyring.ReadKeyringFileFromPath(params.keyringPath)
+	if err != nil {
+		return err
+	}
+	manager := keyring.NewKeyringManager(configList, clock.New(), keyring.NewKeyGenerator(keyEncrypter, params.keyEncryptionKeyURI))
+	persist := func(configList *keyring.AdsCertCallSignConfigList) error {
+		return keyring.WriteKeyringFileToPath(params.keyringPath, configList)
+	}
+
+	if err := manager.Rotate(); err != nil {
+		return err
+	}
+	if err := persist(configList); err != nil {
+		return err
+	}
+	if params.interval == 0 {
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := manager.RunRotationScheduler(ctx, params.interval, persist); !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
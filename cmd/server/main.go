@@ -2,22 +2,61 @@ package main
 
 import (
 	"flag"
+	"net"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/IABTechLab/adscert/internal/server"
 	"github.com/IABTechLab/adscert/internal/utils"
+	"github.com/IABTechLab/adscert/pkg/adscert/api"
+	"github.com/IABTechLab/adscert/pkg/adscert/discovery"
 	"github.com/IABTechLab/adscert/pkg/adscert/logger"
+	"github.com/IABTechLab/adscert/pkg/adscert/metrics"
+	"github.com/IABTechLab/adscert/pkg/adscert/signatory"
 	"google.golang.org/grpc"
 )
 
 var (
-	serverPort            = flag.Int("server_port", 3000, "grpc server port")
-	metricsPort           = flag.Int("metrics_port", 3001, "http metrics port")
-	logLevel              = flag.String("loglevel", utils.GetEnvVarString("LOGLEVEL", "INFO"), "minimum log verbosity")
-	origin                = flag.String("origin", utils.GetEnvVarString("ORIGIN", ""), "ads.cert Call Sign domain name for this party's Signatory service deployment")
-	domainCheckInterval   = flag.Duration("domain_check_interval", time.Duration(utils.GetEnvVarInt("DOMAIN_CHECK_INTERVAL", 30))*time.Second, "interval for checking domain records")
-	domainRenewalInterval = flag.Duration("domain_renewal_interval", time.Duration(utils.GetEnvVarInt("DOMAIN_RENEWAL_INTERVAL", 300))*time.Second, "interval before considering domain records for renewal")
-	privateKey            = flag.String("private_key", utils.GetEnvVarString("PRIVATE_KEY", ""), "base-64 encoded private key")
+	serverPort               = flag.Int("server_port", 3000, "grpc server port")
+	metricsPort              = flag.Int("metrics_port", 3001, "http metrics port")
+	logLevel                 = flag.String("loglevel", utils.GetEnvVarString("LOGLEVEL", "INFO"), "minimum log verbosity")
+	origin                   = flag.String("origin", utils.GetEnvVarString("ORIGIN", ""), "ads.cert Call Sign domain name for this party's Signatory service deployment")
+	domainCheckInterval      = flag.Duration("domain_check_interval", time.Duration(utils.GetEnvVarInt("DOMAIN_CHECK_INTERVAL", 30))*time.Second, "interval for checking domain records")
+	domainRenewalInterval    = flag.Duration("domain_renewal_interval", time.Duration(utils.GetEnvVarInt("DOMAIN_RENEWAL_INTERVAL", 300))*time.Second, "interval before considering domain records for renewal when record TTLs are unavailable")
+	minRefreshInterval       = flag.Duration("min_refresh_interval", time.Duration(utils.GetEnvVarInt("MIN_REFRESH_INTERVAL", 30))*time.Second, "minimum interval between refreshes of domain records, regardless of record TTL")
+	maxRefreshInterval       = flag.Duration("max_refresh_interval", time.Duration(utils.GetEnvVarInt("MAX_REFRESH_INTERVAL", 3600))*time.Second, "maximum interval between refreshes of domain records, regardless of record TTL")
+	negativeCacheInterval    = flag.Duration("negative_cache_interval", time.Duration(utils.GetEnvVarInt("NEGATIVE_CACHE_INTERVAL", 30))*time.Second, "initial interval before re-checking domains with missing or unparseable records, doubled on each consecutive failure")
+	maxNegativeCacheInterval = flag.Duration("max_negative_cache_interval", time.Duration(utils.GetEnvVarInt("MAX_NEGATIVE_CACHE_INTERVAL", 3600))*time.Second, "maximum interval before re-checking domains with missing or unparseable records")
+	synchronousLookupTimeout = flag.Duration("synchronous_lookup_timeout", time.Duration(utils.GetEnvVarInt("SYNCHRONOUS_LOOKUP_TIMEOUT_MS", 0))*time.Millisecond, "maximum time a request waits for the first lookup of a new counterparty; 0 fails the request and looks the counterparty up in the background")
+	privateKey               = flag.String("private_key", utils.GetEnvVarString("PRIVATE_KEY", ""), "base-64 encoded private key")
+	replayWindow             = flag.Duration("replay_window", time.Duration(utils.GetEnvVarInt("REPLAY_WINDOW", 0))*time.Second, "maximum age of a signature timestamp accepted during verification; 0 disables replay protection")
+	replayCacheSize          = flag.Int("replay_cache_size", utils.GetEnvVarInt("REPLAY_CACHE_SIZE", 100000), "maximum number of recently seen nonces retained for replay protection; signatures with new nonces are rejected while it is full")
+	signingStatus            = flag.String("signing_status", utils.GetEnvVarString("SIGNING_STATUS", "ok"), "status declared in signatures unless a request asks for another, such as testing or advisory_only while rolling out")
+	deactivationFile         = flag.String("deactivation_file", utils.GetEnvVarString("DEACTIVATION_FILE", ""), "JSON file of deactivation settings, reloaded on SIGHUP replacing any set through the admin service; nothing is deactivated if empty or missing at startup")
+	signWhenDeactivated      = flag.Bool("sign_when_deactivated", utils.GetEnvVarBool("SIGN_WHEN_DEACTIVATED", false), "If true, deactivated signing produces signatures declaring the deactivated status instead of none")
+	adminPort                = flag.Int("admin_port", utils.GetEnvVarInt("ADMIN_PORT", 0), "TCP port of the unauthenticated admin gRPC service for changing deactivation settings at runtime; disabled if 0")
+	adminAddress             = flag.String("admin_address", utils.GetEnvVarString("ADMIN_ADDRESS", "localhost"), "host or IP address the admin gRPC service listens on; only change where the address is reachable by trusted clients")
+	auditLogFile             = flag.String("audit_log_file", utils.GetEnvVarString("AUDIT_LOG_FILE", ""), "JSONL file recording every signature signed or verified; nothing is recorded if empty")
+	auditLogMaxBytes         = flag.Int("audit_log_max_bytes", utils.GetEnvVarInt("AUDIT_LOG_MAX_BYTES", 100<<20), "size at which the audit log file is rotated; 0 never rotates")
+	auditLogMaxBackups       = flag.Int("audit_log_max_backups", utils.GetEnvVarInt("AUDIT_LOG_MAX_BACKUPS", 5), "number of rotated audit log files kept")
+	auditBufferSize          = flag.Int("audit_buffer_size", utils.GetEnvVarInt("AUDIT_BUFFER_SIZE", 10000), "number of audit events buffered for writing before further events are dropped")
+	auditSampling            = flag.String("audit_sampling", utils.GetEnvVarString("AUDIT_SAMPLING", ""), "comma-separated audit sampling rules of the form [counterparty:]outcome=rate, such as \"failure=1,success=0.001\"; unmatched events are always audited")
+	auditRedactionKeyFile    = flag.String("audit_redaction_key_file", utils.GetEnvVarString("AUDIT_REDACTION_KEY_FILE", ""), "file holding a key used to replace audited signatures by their HMAC; signatures are audited as-is if empty; redacted audit logs can't be replayed with verifylog")
+	auditOmitHashes          = flag.Bool("audit_omit_hashes", utils.GetEnvVarBool("AUDIT_OMIT_HASHES", false), "leave body and URL hashes out of audit events, which then can't be replayed with verifylog")
+	additionalOrigins        = flag.String("additional_origins", utils.GetEnvVarString("ADDITIONAL_ORIGINS", ""), "comma-separated callsign=base64key entries for additional ads.cert Call Signs hosted by this signatory")
+	recipientAliases         = flag.String("recipient_aliases", utils.GetEnvVarString("RECIPIENT_ALIASES", ""), "comma-separated invoking domains whose policy record delegates to a hosted origin, accepted as the recipient of signatures")
+	keyringPath              = flag.String("keyring_path", utils.GetEnvVarString("KEYRING_PATH", ""), "path to an adscertkeyring.json file; active keys for the origin are decrypted and loaded, and other callsigns in the keyring are hosted as additional origins")
+	keyEncryptionKeysetFile  = flag.String("key_encryption_keyset_file", utils.GetEnvVarString("KEY_ENCRYPTION_KEYSET_FILE", ""), "cleartext Tink keyset (JSON) used to decrypt keyring entries protected with local-key-encryption://")
+	insecurePlaintextKMS     = flag.Bool("insecure_plaintext_kms", false, "If true, accepts keyring entries protected with insecure-plaintext-kms:// (testing only)")
+	metricsCounterparty      = flag.Bool("metrics_counterparty_labels", utils.GetEnvVarBool("METRICS_COUNTERPARTY_LABELS", false), "If true, labels sign, verify and DNS lookup metrics with the counterparty domain")
+	metricsKeyAlias          = flag.Bool("metrics_key_alias_labels", utils.GetEnvVarBool("METRICS_KEY_ALIAS_LABELS", false), "If true, also labels sign and verify metrics with the counterparty's key alias")
+	metricsAllowlist         = flag.String("metrics_counterparty_allowlist", utils.GetEnvVarString("METRICS_COUNTERPARTY_ALLOWLIST", ""), "comma-separated counterparty domains always given their own metrics label")
+	metricsMaxCounterparties = flag.Int("metrics_max_counterparties", utils.GetEnvVarInt("METRICS_MAX_COUNTERPARTIES", 100), "number of counterparties beyond the allowlist given their own metrics label, in the order first seen; others are labeled \"other\"")
+	domainStorePath          = flag.String("domain_store_path", utils.GetEnvVarString("DOMAIN_STORE_PATH", ""), "file used to persist counterparty domain records across restarts; records are held in memory only if empty")
+	dnssec                   = flag.Bool("dnssec", utils.GetEnvVarBool("DNSSEC", false), "If true, requires DNSSEC validation of all ads.cert DNS records")
+	dnssecNameserver         = flag.String("dnssec_nameserver", utils.GetEnvVarString("DNSSEC_NAMESERVER", ""), "host:port of the recursive nameserver used for DNSSEC lookups (defaults to the first nameserver in /etc/resolv.conf)")
+	dnssecTrustAnchorFile    = flag.String("dnssec_trust_anchor_file", utils.GetEnvVarString("DNSSEC_TRUST_ANCHOR_FILE", ""), "file of DS records (one per line) to use as DNSSEC trust anchors instead of the root zone trust anchor")
 )
 
 func main() {
@@ -32,8 +71,8 @@ func main() {
 		logger.Fatalf("Origin ads.cert Call Sign domain name is required")
 	}
 
-	if *privateKey == "" {
-		logger.Fatalf("Private key is required")
+	if *privateKey == "" && *keyringPath == "" {
+		logger.Fatalf("Private key or keyring is required")
 	}
 
 	logger.Info("Starting Metrics server")
@@ -48,8 +87,114 @@ func main() {
 	logger.Infof("Origin ads.cert Call Sign domain: %v", *origin)
 	logger.Infof("Port: %v", *serverPort)
 
+	dnsResolver, err := server.NewDNSResolver(*dnssec, *dnssecNameserver, *dnssecTrustAnchorFile)
+	if err != nil {
+		logger.Fatalf("Error setting up DNS resolver: %v", err)
+	}
+
+	domainStore, err := server.NewDomainStore(*domainStorePath)
+	if err != nil {
+		logger.Fatalf("Error opening domain store: %v", err)
+	}
+
+	var metricsAllowlistEntries []string
+	if *metricsAllowlist != "" {
+		metricsAllowlistEntries = strings.Split(*metricsAllowlist, ",")
+	}
+	server.SetUpMetrics(metrics.LabelOptions{
+		Counterparty:      *metricsCounterparty,
+		KeyAlias:          *metricsKeyAlias,
+		Allowlist:         metricsAllowlistEntries,
+		MaxCounterparties: *metricsMaxCounterparties,
+	}, domainStore)
+
+	var originKeyEntries []string
+	if *additionalOrigins != "" {
+		originKeyEntries = strings.Split(*additionalOrigins, ",")
+	}
+	originKeys, err := server.ParseOriginKeys(originKeyEntries)
+	if err != nil {
+		logger.Fatalf("Error parsing additional origins: %v", err)
+	}
+
+	var privateKeys []string
+	var privateKeyRoles map[string]discovery.PrivateKeyRole
+	if *privateKey != "" {
+		privateKeys = append(privateKeys, *privateKey)
+	}
+	if *keyringPath != "" {
+		keyringKeys, keyringRoles, err := server.LoadKeyringPrivateKeys(*keyringPath, *keyEncryptionKeysetFile, *insecurePlaintextKMS)
+		if err != nil {
+			logger.Fatalf("Error loading keyring: %v", err)
+		}
+		privateKeyRoles = keyringRoles
+		keyringOriginKeys, keyringOrigins := server.SplitOriginKeys(*origin, keyringKeys)
+		privateKeys = append(privateKeys, keyringOriginKeys...)
+		for callsign, keys := range keyringOrigins {
+			originKeys[callsign] = append(originKeys[callsign], keys...)
+		}
+	}
+	if len(privateKeys) == 0 {
+		logger.Fatalf("Private key or keyring with active keys for origin %s is required", *origin)
+	}
+
+	deactivation := &api.DeactivationSettings{}
+	if *deactivationFile != "" {
+		if deactivation, err = server.LoadDeactivationFile(*deactivationFile); err != nil {
+			logger.Fatalf("Error loading deactivation settings: %v", err)
+		}
+	}
+
+	auditSink, err := server.NewAuditSink(*auditLogFile, int64(*auditLogMaxBytes), *auditLogMaxBackups, *auditBufferSize)
+	if err != nil {
+		logger.Fatalf("Error opening audit log: %v", err)
+	}
+	auditSampler, err := server.NewAuditSampler(*auditSampling)
+	if err != nil {
+		logger.Fatalf("Error parsing audit sampling rules: %v", err)
+	}
+	auditRedactionKey, err := server.LoadAuditRedactionKey(*auditRedactionKeyFile)
+	if err != nil {
+		logger.Fatalf("Error loading audit redaction key: %v", err)
+	}
+
+	var recipientAliasEntries []string
+	if *recipientAliases != "" {
+		recipientAliasEntries = strings.Split(*recipientAliases, ",")
+	}
+
 	grpcServer := grpc.NewServer()
-	server.SetUpAdsCertSignatoryServer(grpcServer, *origin, *domainCheckInterval, *domainRenewalInterval, []string{*privateKey})
+	signatoryApi := server.SetUpAdsCertSignatoryServer(grpcServer, *origin, privateKeys, dnsResolver, domainStore, &signatory.LocalAuthenticatedConnectionsSignatoryOptions{
+		DomainCheckInterval:      *domainCheckInterval,
+		DomainRenewalInterval:    *domainRenewalInterval,
+		MinRefreshInterval:       *minRefreshInterval,
+		MaxRefreshInterval:       *maxRefreshInterval,
+		NegativeCacheInterval:    *negativeCacheInterval,
+		MaxNegativeCacheInterval: *maxNegativeCacheInterval,
+		SynchronousLookupTimeout: *synchronousLookupTimeout,
+		AdditionalOrigins:        originKeys,
+		RecipientAliases:         recipientAliasEntries,
+		PrivateKeyRoles:          privateKeyRoles,
+		ReplayWindow:             *replayWindow,
+		ReplayCacheSize:          *replayCacheSize,
+		SigningStatus:            *signingStatus,
+		Deactivation:             deactivation,
+		SignWhenDeactivated:      *signWhenDeactivated,
+		AuditSink:                auditSink,
+		AuditSampler:             auditSampler,
+		AuditRedactionKey:        auditRedactionKey,
+		AuditOmitHashes:          *auditOmitHashes,
+	})
+	if *adminPort != 0 {
+		go func() {
+			if err := server.StartServingAdminRequests(signatoryApi, net.JoinHostPort(*adminAddress, strconv.Itoa(*adminPort))); err != nil {
+				logger.Fatalf("Admin gRPC server failure: %v", err)
+			}
+		}()
+	}
+	if *deactivationFile != "" {
+		server.ReloadDeactivationFileOnSignal(*deactivationFile, signatoryApi)
+	}
 	if err := server.StartServingRequests(grpcServer, *serverPort); err != nil {
 		logger.Fatalf("gRPC server failure: %v", err)
 	}
@@ -17,9 +17,16 @@ package cmd
 
 import (
 	"fmt"
+	"net"
+	"strconv"
 	"time"
 
 	"github.com/IABTechLab/adscert/internal/server"
+	"github.com/IABTechLab/adscert/pkg/adscert/api"
+	"github.com/IABTechLab/adscert/pkg/adscert/discovery"
+	"github.com/IABTechLab/adscert/pkg/adscert/logger"
+	"github.com/IABTechLab/adscert/pkg/adscert/metrics"
+	"github.com/IABTechLab/adscert/pkg/adscert/signatory"
 	"github.com/spf13/cobra"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
@@ -32,7 +39,9 @@ var (
 		Short: "Runs a gRPC server with ads.cert signing/verification capabilities.",
 		Run: func(cmd *cobra.Command, args []string) {
 			fmt.Printf("signatory called, listening on %d and monitoring %d\n", signatoryParams.serverPort, signatoryParams.metricsPort)
-			signatoryStart(signatoryParams)
+			if err := signatoryStart(signatoryParams); err != nil {
+				logger.Fatalf("signatory failure: %v", err)
+			}
 		},
 	}
 
@@ -46,6 +55,48 @@ type signatoryParameters struct {
 	domainCheckInterval   time.Duration
 	domainRenewalInterval time.Duration
 
+	minRefreshInterval       time.Duration
+	maxRefreshInterval       time.Duration
+	negativeCacheInterval    time.Duration
+	maxNegativeCacheInterval time.Duration
+	synchronousLookupTimeout time.Duration
+
+	replayWindow    time.Duration
+	replayCacheSize int
+
+	signingStatus string
+
+	deactivationFile    string
+	signWhenDeactivated bool
+	adminPort           int
+	adminAddress        string
+
+	auditLogFile       string
+	auditLogMaxBytes   int64
+	auditLogMaxBackups int
+	auditBufferSize    int
+	auditSampling      string
+	auditRedactionKey  string
+	auditOmitHashes    bool
+
+	dnssec                bool
+	dnssecNameserver      string
+	dnssecTrustAnchorFile string
+
+	domainStorePath string
+
+	metricsCounterpartyLabels bool
+	metricsKeyAliasLabels     bool
+	metricsAllowlist          []string
+	metricsMaxCounterparties  int
+
+	additionalOrigins []string
+	recipientAliases  []string
+
+	keyringPath             string
+	keyEncryptionKeysetFile string
+	insecurePlaintextKMS    bool
+
 	// deprecated flags
 	origin     string
 	privateKey string
@@ -58,7 +109,47 @@ func init() {
 	signatoryCmd.Flags().IntVar(&signatoryParams.metricsPort, "metrics_port", 3001, "Server will expose monitoring on this TCP port via an HTTP server.")
 
 	signatoryCmd.Flags().DurationVar(&signatoryParams.domainCheckInterval, "domain_check_interval", 30*time.Second, "interval for checking domain records")
-	signatoryCmd.Flags().DurationVar(&signatoryParams.domainRenewalInterval, "domain_renewal_interval", 300*time.Second, "interval before considering domain records for renewal")
+	signatoryCmd.Flags().DurationVar(&signatoryParams.domainRenewalInterval, "domain_renewal_interval", 300*time.Second, "interval before considering domain records for renewal when record TTLs are unavailable")
+	signatoryCmd.Flags().DurationVar(&signatoryParams.minRefreshInterval, "min_refresh_interval", 30*time.Second, "minimum interval between refreshes of domain records, regardless of record TTL")
+	signatoryCmd.Flags().DurationVar(&signatoryParams.maxRefreshInterval, "max_refresh_interval", time.Hour, "maximum interval between refreshes of domain records, regardless of record TTL")
+	signatoryCmd.Flags().DurationVar(&signatoryParams.negativeCacheInterval, "negative_cache_interval", 30*time.Second, "initial interval before re-checking domains with missing or unparseable records, doubled on each consecutive failure")
+	signatoryCmd.Flags().DurationVar(&signatoryParams.maxNegativeCacheInterval, "max_negative_cache_interval", time.Hour, "maximum interval before re-checking domains with missing or unparseable records")
+	signatoryCmd.Flags().DurationVar(&signatoryParams.synchronousLookupTimeout, "synchronous_lookup_timeout", 0, "maximum time a request waits for the first lookup of a new counterparty; 0 fails the request and looks the counterparty up in the background")
+
+	signatoryCmd.Flags().DurationVar(&signatoryParams.replayWindow, "replay_window", 0, "maximum age of a signature timestamp accepted during verification; 0 disables replay protection")
+	signatoryCmd.Flags().IntVar(&signatoryParams.replayCacheSize, "replay_cache_size", 100000, "maximum number of recently seen nonces retained for replay protection; signatures with new nonces are rejected while it is full")
+
+	signatoryCmd.Flags().StringVar(&signatoryParams.signingStatus, "signing_status", "ok", "status declared in signatures unless a request asks for another, such as testing or advisory_only while rolling out")
+
+	signatoryCmd.Flags().StringVar(&signatoryParams.deactivationFile, "deactivation_file", "", "JSON file of deactivation settings, reloaded on SIGHUP replacing any set through the admin service; nothing is deactivated if empty or missing at startup")
+	signatoryCmd.Flags().BoolVar(&signatoryParams.signWhenDeactivated, "sign_when_deactivated", false, "If true, deactivated signing produces signatures declaring the deactivated status instead of none")
+	signatoryCmd.Flags().IntVar(&signatoryParams.adminPort, "admin_port", 0, "TCP port of the unauthenticated admin gRPC service for changing deactivation settings at runtime; disabled if 0")
+	signatoryCmd.Flags().StringVar(&signatoryParams.adminAddress, "admin_address", "localhost", "host or IP address the admin gRPC service listens on; only change where the address is reachable by trusted clients")
+
+	signatoryCmd.Flags().StringVar(&signatoryParams.auditLogFile, "audit_log_file", "", "JSONL file recording every signature signed or verified; nothing is recorded if empty")
+	signatoryCmd.Flags().Int64Var(&signatoryParams.auditLogMaxBytes, "audit_log_max_bytes", 100<<20, "size at which the audit log file is rotated; 0 never rotates")
+	signatoryCmd.Flags().IntVar(&signatoryParams.auditLogMaxBackups, "audit_log_max_backups", 5, "number of rotated audit log files kept")
+	signatoryCmd.Flags().IntVar(&signatoryParams.auditBufferSize, "audit_buffer_size", 10000, "number of audit events buffered for writing before further events are dropped")
+	signatoryCmd.Flags().StringVar(&signatoryParams.auditSampling, "audit_sampling", "", "comma-separated audit sampling rules of the form [counterparty:]outcome=rate, such as \"failure=1,success=0.001\"; unmatched events are always audited")
+	signatoryCmd.Flags().StringVar(&signatoryParams.auditRedactionKey, "audit_redaction_key_file", "", "file holding a key used to replace audited signatures by their HMAC; signatures are audited as-is if empty; redacted audit logs can't be replayed with verifylog")
+	signatoryCmd.Flags().BoolVar(&signatoryParams.auditOmitHashes, "audit_omit_hashes", false, "leave body and URL hashes out of audit events, which then can't be replayed with verifylog")
+
+	signatoryCmd.Flags().BoolVar(&signatoryParams.dnssec, "dnssec", false, "If true, requires DNSSEC validation of all ads.cert DNS records")
+	signatoryCmd.Flags().StringVar(&signatoryParams.dnssecNameserver, "dnssec_nameserver", "", "host:port of the recursive nameserver used for DNSSEC lookups (defaults to the first nameserver in /etc/resolv.conf)")
+	signatoryCmd.Flags().StringVar(&signatoryParams.dnssecTrustAnchorFile, "dnssec_trust_anchor_file", "", "file of DS records (one per line) to use as DNSSEC trust anchors instead of the root zone trust anchor")
+
+	signatoryCmd.Flags().StringVar(&signatoryParams.domainStorePath, "domain_store_path", "", "file used to persist counterparty domain records across restarts; records are held in memory only if empty")
+
+	signatoryCmd.Flags().BoolVar(&signatoryParams.metricsCounterpartyLabels, "metrics_counterparty_labels", false, "label sign, verify and DNS lookup metrics with the counterparty domain")
+	signatoryCmd.Flags().BoolVar(&signatoryParams.metricsKeyAliasLabels, "metrics_key_alias_labels", false, "also label sign and verify metrics with the counterparty's key alias")
+	signatoryCmd.Flags().StringSliceVar(&signatoryParams.metricsAllowlist, "metrics_counterparty_allowlist", nil, "counterparty domains always given their own metrics label")
+	signatoryCmd.Flags().IntVar(&signatoryParams.metricsMaxCounterparties, "metrics_max_counterparties", 100, "number of counterparties beyond the allowlist given their own metrics label, in the order first seen; others are labeled \"other\"")
+	signatoryCmd.Flags().StringArrayVar(&signatoryParams.additionalOrigins, "additional_origin", nil, "additional ads.cert Call Sign hosted by this signatory, as callsign=base64key; repeat for more origins or keys")
+	signatoryCmd.Flags().StringSliceVar(&signatoryParams.recipientAliases, "recipient_aliases", nil, "invoking domains whose policy record delegates to a hosted origin, accepted as the recipient of signatures")
+
+	signatoryCmd.Flags().StringVar(&signatoryParams.keyringPath, "keyring_path", "", "path to an adscertkeyring.json file; active keys for the origin are decrypted and loaded, and other callsigns in the keyring are hosted as additional origins")
+	signatoryCmd.Flags().StringVar(&signatoryParams.keyEncryptionKeysetFile, "key_encryption_keyset_file", "", "cleartext Tink keyset (JSON) used to decrypt keyring entries protected with local-key-encryption://")
+	signatoryCmd.Flags().BoolVar(&signatoryParams.insecurePlaintextKMS, "insecure_plaintext_kms", false, "If true, accepts keyring entries protected with insecure-plaintext-kms:// (testing only)")
 
 	signatoryCmd.Flags().StringVar(&signatoryParams.origin, "origin", "", "ads.cert Call Sign domain name for this party's Signatory service deployment")
 	signatoryCmd.Flags().StringVar(&signatoryParams.privateKey, "private_key", "", "base-64 encoded private key")
@@ -70,18 +161,109 @@ func signatoryStart(signatoryParams *signatoryParameters) error {
 	// accepting a context.Context as a parameter.
 	g := errgroup.Group{}
 
+	dnsResolver, err := server.NewDNSResolver(signatoryParams.dnssec, signatoryParams.dnssecNameserver, signatoryParams.dnssecTrustAnchorFile)
+	if err != nil {
+		return err
+	}
+
+	domainStore, err := server.NewDomainStore(signatoryParams.domainStorePath)
+	if err != nil {
+		return err
+	}
+	server.SetUpMetrics(metrics.LabelOptions{
+		Counterparty:      signatoryParams.metricsCounterpartyLabels,
+		KeyAlias:          signatoryParams.metricsKeyAliasLabels,
+		Allowlist:         signatoryParams.metricsAllowlist,
+		MaxCounterparties: signatoryParams.metricsMaxCounterparties,
+	}, domainStore)
+
+	additionalOrigins, err := server.ParseOriginKeys(signatoryParams.additionalOrigins)
+	if err != nil {
+		return err
+	}
+
+	var privateKeys []string
+	var privateKeyRoles map[string]discovery.PrivateKeyRole
+	if signatoryParams.privateKey != "" {
+		privateKeys = append(privateKeys, signatoryParams.privateKey)
+	}
+	if signatoryParams.keyringPath != "" {
+		keyringKeys, keyringRoles, err := server.LoadKeyringPrivateKeys(signatoryParams.keyringPath, signatoryParams.keyEncryptionKeysetFile, signatoryParams.insecurePlaintextKMS)
+		if err != nil {
+			return err
+		}
+		privateKeyRoles = keyringRoles
+		originKeys, keyringOrigins := server.SplitOriginKeys(signatoryParams.origin, keyringKeys)
+		privateKeys = append(privateKeys, originKeys...)
+		for callsign, keys := range keyringOrigins {
+			additionalOrigins[callsign] = append(additionalOrigins[callsign], keys...)
+		}
+	}
+	if len(privateKeys) == 0 {
+		return fmt.Errorf("private key or keyring with active keys for origin %s is required", signatoryParams.origin)
+	}
+
+	deactivation := &api.DeactivationSettings{}
+	if signatoryParams.deactivationFile != "" {
+		if deactivation, err = server.LoadDeactivationFile(signatoryParams.deactivationFile); err != nil {
+			return err
+		}
+	}
+
+	auditSink, err := server.NewAuditSink(signatoryParams.auditLogFile, signatoryParams.auditLogMaxBytes, signatoryParams.auditLogMaxBackups, signatoryParams.auditBufferSize)
+	if err != nil {
+		return err
+	}
+	auditSampler, err := server.NewAuditSampler(signatoryParams.auditSampling)
+	if err != nil {
+		return err
+	}
+	auditRedactionKey, err := server.LoadAuditRedactionKey(signatoryParams.auditRedactionKey)
+	if err != nil {
+		return err
+	}
+
 	g.Go(func() error {
 		return server.StartMetricsServer(signatoryParams.metricsPort)
 	})
 
 	g.Go(func() error {
 		grpcServer := grpc.NewServer()
-		server.SetUpAdsCertSignatoryServer(
+		signatoryApi := server.SetUpAdsCertSignatoryServer(
 			grpcServer,
 			signatoryParams.origin,
-			signatoryParams.domainCheckInterval,
-			signatoryParams.domainRenewalInterval,
-			[]string{signatoryParams.privateKey})
+			privateKeys,
+			dnsResolver,
+			domainStore,
+			&signatory.LocalAuthenticatedConnectionsSignatoryOptions{
+				DomainCheckInterval:      signatoryParams.domainCheckInterval,
+				DomainRenewalInterval:    signatoryParams.domainRenewalInterval,
+				MinRefreshInterval:       signatoryParams.minRefreshInterval,
+				MaxRefreshInterval:       signatoryParams.maxRefreshInterval,
+				NegativeCacheInterval:    signatoryParams.negativeCacheInterval,
+				MaxNegativeCacheInterval: signatoryParams.maxNegativeCacheInterval,
+				SynchronousLookupTimeout: signatoryParams.synchronousLookupTimeout,
+				AdditionalOrigins:        additionalOrigins,
+				RecipientAliases:         signatoryParams.recipientAliases,
+				PrivateKeyRoles:          privateKeyRoles,
+				ReplayWindow:             signatoryParams.replayWindow,
+				ReplayCacheSize:          signatoryParams.replayCacheSize,
+				SigningStatus:            signatoryParams.signingStatus,
+				Deactivation:             deactivation,
+				SignWhenDeactivated:      signatoryParams.signWhenDeactivated,
+				AuditSink:                auditSink,
+				AuditSampler:             auditSampler,
+				AuditRedactionKey:        auditRedactionKey,
+				AuditOmitHashes:          signatoryParams.auditOmitHashes,
+			})
+		if signatoryParams.adminPort != 0 {
+			g.Go(func() error {
+				return server.StartServingAdminRequests(signatoryApi, net.JoinHostPort(signatoryParams.adminAddress, strconv.Itoa(signatoryParams.adminPort)))
+			})
+		}
+		if signatoryParams.deactivationFile != "" {
+			server.ReloadDeactivationFileOnSignal(signatoryParams.deactivationFile, signatoryApi)
+		}
 		return server.StartServingRequests(grpcServer, signatoryParams.serverPort)
 	})
 
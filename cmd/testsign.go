@@ -76,6 +76,8 @@ type testsignParameters struct {
 	sendRequest    bool
 	method         string
 	signURLAsHTTPS bool
+	origin         string
+	signingStatus  string
 }
 
 func init() {
@@ -88,6 +90,8 @@ func init() {
 	testsignCmd.Flags().DurationVar(&testsignParams.signingTimeout, "signing_timeout", 50*time.Millisecond, "Specifies how long this client will wait for signing to finish before abandoning.")
 	testsignCmd.Flags().BoolVar(&testsignParams.sendRequest, "send_request", false, "If true, invokes the specified URL on the remote server")
 	testsignCmd.Flags().StringVar(&testsignParams.method, "method", "GET", "The HTTP request method, GET or POST")
+	testsignCmd.Flags().StringVar(&testsignParams.origin, "origin", "", "ads.cert Call Sign to sign as, when the signatory hosts several (defaults to the signatory's origin)")
+	testsignCmd.Flags().StringVar(&testsignParams.signingStatus, "signing_status", "", "status to declare in the signature, such as testing or advisory_only (defaults to the signatory's status)")
 }
 
 func signRequest(testsignParams *testsignParameters) *api.AuthenticatedConnectionSignatureResponse {
@@ -131,7 +135,9 @@ func signRequest(testsignParams *testsignParameters) *api.AuthenticatedConnectio
 	logger.Infof("Signing request for URL: %v", urlToSign)
 	signatureResponse, err := signatoryClient.SignAuthenticatedConnection(
 		&api.AuthenticatedConnectionSignatureRequest{
-			RequestInfo: reqInfo,
+			RequestInfo:   reqInfo,
+			Origin:        testsignParams.origin,
+			SigningStatus: testsignParams.signingStatus,
 		})
 	if err != nil {
 		logger.Warningf("unable to sign message: %v", err)
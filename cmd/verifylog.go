@@ -0,0 +1,419 @@
+/*
+Copyright © 2022 IAB Technology Laboratory, Inc
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bufio"
+	"context"
+	crypto_rand "crypto/rand"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/IABTechLab/adscert/internal/server"
+	"github.com/IABTechLab/adscert/pkg/adscert/api"
+	"github.com/IABTechLab/adscert/pkg/adscert/discovery"
+	"github.com/IABTechLab/adscert/pkg/adscert/logger"
+	"github.com/IABTechLab/adscert/pkg/adscert/signatory"
+	"github.com/benbjohnson/clock"
+	"github.com/spf13/cobra"
+)
+
+// verifylogCmd represents the verifylog command
+var (
+	verifylogParams = &verifylogParameters{}
+
+	verifylogCmd = &cobra.Command{
+		Use:   "verifylog [file...]",
+		Short: "Verifies the signatures recorded in request logs offline.",
+		Long: `Reads logged requests from the given files, or stdin when none or "-" is
+given, verifies their signatures and prints a breakdown of the results by
+counterparty and signature decode status.
+
+Each CSV line holds the invoking domain, the signature, and the base64 encoded
+SHA-256 hashes of the request body and URL.  Each JSONL line holds an object
+with invoking_domain, signatures (a list) or signature, body_hash and url_hash
+fields, so that signatory audit logs can be replayed: events whose operation
+isn't "verify" are skipped.
+Records whose signature was redacted by the signatory's audit redaction key
+can't be verified, so they are skipped and counted separately.
+
+Counterparty records are looked up in live DNS, or read from a snapshot file
+mapping record names to TXT values so that logs can be verified against the
+records in place when they were written.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			summary, err := verifyLogs(verifylogParams, args)
+			if err != nil {
+				logger.Fatalf("Failed to verify logs: %v", err)
+			}
+			if err := printVerifyLogSummary(os.Stdout, summary, verifylogParams.format); err != nil {
+				logger.Fatalf("Failed to print summary: %v", err)
+			}
+		},
+	}
+)
+
+type verifylogParameters struct {
+	inputFormat string
+	format      string
+	workers     int
+
+	origin                  string
+	privateKey              string
+	keyringPath             string
+	keyEncryptionKeysetFile string
+	insecurePlaintextKMS    bool
+
+	dnsSnapshotPath string
+	lookupTimeout   time.Duration
+}
+
+// verifylogRecord is one logged request in JSONL input.
+type verifylogRecord struct {
+	Operation      string   `json:"operation"`
+	InvokingDomain string   `json:"invoking_domain"`
+	Signatures     []string `json:"signatures"`
+	Signature      string   `json:"signature"`
+	BodyHash       string   `json:"body_hash"`
+	URLHash        string   `json:"url_hash"`
+}
+
+// errRedactedRecord is returned when parsing a record whose signature was
+// redacted in the audit log.
+var errRedactedRecord = errors.New("signature is redacted")
+
+// errNotVerifyRecord is returned when parsing an audit event of an operation
+// other than verification, such as signing, which isn't a received request.
+var errNotVerifyRecord = errors.New("not a verify event")
+
+// verifylogSummary counts the verification results of logged signatures by
+// counterparty and signature decode status.  Signatures which couldn't be
+// decoded are counted under an empty counterparty.
+type verifylogSummary struct {
+	Records            int                       `json:"records"`
+	UnsignedRecords    int                       `json:"unsigned_records"`
+	RedactedRecords    int                       `json:"redacted_records"`
+	ParseErrors        int                       `json:"parse_errors"`
+	VerificationErrors int                       `json:"verification_errors"`
+	Counterparties     map[string]map[string]int `json:"counterparties"`
+
+	mutex sync.Mutex
+}
+
+func init() {
+	rootCmd.AddCommand(verifylogCmd)
+
+	verifylogCmd.Flags().StringVar(&verifylogParams.inputFormat, "input_format", "csv", "format of the logs, csv or jsonl")
+	verifylogCmd.Flags().StringVar(&verifylogParams.format, "format", "text", "output format, text or json")
+	verifylogCmd.Flags().IntVar(&verifylogParams.workers, "workers", runtime.GOMAXPROCS(0), "number of records verified concurrently")
+
+	verifylogCmd.Flags().StringVar(&verifylogParams.origin, "origin", "", "ads.cert Call Sign domain name the logged requests were sent to")
+	verifylogCmd.Flags().StringVar(&verifylogParams.privateKey, "private_key", "", "base-64 encoded private key of the origin")
+	verifylogCmd.Flags().StringVar(&verifylogParams.keyringPath, "keyring_path", "", "path to an adscertkeyring.json file; active keys for the origin are decrypted and loaded, and other callsigns in the keyring are accepted as recipients too")
+	verifylogCmd.Flags().StringVar(&verifylogParams.keyEncryptionKeysetFile, "key_encryption_keyset_file", "", "cleartext Tink keyset (JSON) used to decrypt keyring entries protected with local-key-encryption://")
+	verifylogCmd.Flags().BoolVar(&verifylogParams.insecurePlaintextKMS, "insecure_plaintext_kms", false, "If true, accepts keyring entries protected with insecure-plaintext-kms:// (testing only)")
+
+	verifylogCmd.Flags().StringVar(&verifylogParams.dnsSnapshotPath, "dns_snapshot", "", "JSON file mapping DNS record names to TXT record values, used instead of live DNS")
+	verifylogCmd.Flags().DurationVar(&verifylogParams.lookupTimeout, "lookup_timeout", 5*time.Second, "maximum time to wait for the records of each counterparty")
+}
+
+func verifyLogs(params *verifylogParameters, inputs []string) (*verifylogSummary, error) {
+	if params.origin == "" {
+		return nil, errors.New("origin ads.cert Call Sign domain name is required")
+	}
+	if params.inputFormat != "csv" && params.inputFormat != "jsonl" {
+		return nil, fmt.Errorf("unknown input format %q, want csv or jsonl", params.inputFormat)
+	}
+
+	var privateKeys []string
+	var privateKeyRoles map[string]discovery.PrivateKeyRole
+	additionalOrigins := map[string][]string{}
+	if params.privateKey != "" {
+		privateKeys = append(privateKeys, params.privateKey)
+	}
+	if params.keyringPath != "" {
+		keyringKeys, keyringRoles, err := server.LoadKeyringPrivateKeys(params.keyringPath, params.keyEncryptionKeysetFile, params.insecurePlaintextKMS)
+		if err != nil {
+			return nil, err
+		}
+		privateKeyRoles = keyringRoles
+		var originKeys []string
+		originKeys, additionalOrigins = server.SplitOriginKeys(params.origin, keyringKeys)
+		privateKeys = append(privateKeys, originKeys...)
+	}
+	if len(privateKeys) == 0 {
+		return nil, errors.New("private key or keyring with keys for the origin is required")
+	}
+
+	dnsResolver := discovery.NewDefaultDnsResolver()
+	if params.dnsSnapshotPath != "" {
+		var err error
+		if dnsResolver, err = discovery.LoadSnapshotDNSResolver(params.dnsSnapshotPath); err != nil {
+			return nil, err
+		}
+	}
+
+	signatoryApi := signatory.NewLocalAuthenticatedConnectionsSignatoryWithOptions(
+		params.origin,
+		crypto_rand.Reader,
+		clock.New(),
+		dnsResolver,
+		discovery.NewDefaultDomainStore(),
+		privateKeys,
+		&signatory.LocalAuthenticatedConnectionsSignatoryOptions{
+			AdditionalOrigins:        additionalOrigins,
+			PrivateKeyRoles:          privateKeyRoles,
+			SynchronousLookupTimeout: params.lookupTimeout,
+		})
+
+	summary := &verifylogSummary{Counterparties: map[string]map[string]int{}}
+	requests := make(chan *api.RequestInfo)
+
+	workers := params.workers
+	if workers < 1 {
+		workers = 1
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for requestInfo := range requests {
+				response, err := signatoryApi.VerifyAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionVerificationRequest{
+					RequestInfo: []*api.RequestInfo{requestInfo},
+				})
+				summary.add(response, err)
+			}
+		}()
+	}
+
+	if len(inputs) == 0 {
+		inputs = []string{"-"}
+	}
+	var readErr error
+	for _, input := range inputs {
+		if readErr = readLogRecords(input, params.inputFormat, summary, requests); readErr != nil {
+			break
+		}
+	}
+	close(requests)
+	wg.Wait()
+
+	return summary, readErr
+}
+
+// readLogRecords parses each record of the input, "-" being stdin, and sends
+// it for verification, counting records which fail to parse.
+func readLogRecords(input string, inputFormat string, summary *verifylogSummary, requests chan<- *api.RequestInfo) error {
+	var reader io.Reader = os.Stdin
+	if input != "-" {
+		file, err := os.Open(input)
+		if err != nil {
+			return fmt.Errorf("unable to open log %s: %v", input, err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var requestInfo *api.RequestInfo
+		var err error
+		if inputFormat == "jsonl" {
+			requestInfo, err = parseJSONLogRecord(line)
+		} else {
+			requestInfo, err = parseCSVLogRecord(line)
+		}
+		if err == errNotVerifyRecord {
+			continue
+		} else if err == errRedactedRecord {
+			summary.addRedacted()
+			continue
+		} else if err != nil {
+			logger.Errorf("Error parsing %s line %d: %v", input, lineNumber, err)
+			summary.addParseError()
+			continue
+		}
+		requests <- requestInfo
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading log %s: %v", input, err)
+	}
+	return nil
+}
+
+func parseCSVLogRecord(line string) (*api.RequestInfo, error) {
+	fields, err := csv.NewReader(strings.NewReader(line)).Read()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) != 4 {
+		return nil, fmt.Errorf("got %d fields, want invoking domain, signature, body hash and URL hash", len(fields))
+	}
+	var signatures []string
+	if fields[1] != "" {
+		signatures = []string{fields[1]}
+	}
+	return newLogRequestInfo(fields[0], signatures, fields[2], fields[3])
+}
+
+func parseJSONLogRecord(line string) (*api.RequestInfo, error) {
+	var record verifylogRecord
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		return nil, err
+	}
+	if record.Operation != "" && record.Operation != signatory.AuditOperationVerify {
+		return nil, errNotVerifyRecord
+	}
+	signatures := record.Signatures
+	if record.Signature != "" {
+		signatures = append(signatures, record.Signature)
+	}
+	return newLogRequestInfo(record.InvokingDomain, signatures, record.BodyHash, record.URLHash)
+}
+
+func newLogRequestInfo(invokingDomain string, signatures []string, bodyHashBase64 string, urlHashBase64 string) (*api.RequestInfo, error) {
+	for _, signature := range signatures {
+		if strings.HasPrefix(signature, signatory.RedactedSignaturePrefix) {
+			return nil, errRedactedRecord
+		}
+	}
+	if invokingDomain == "" {
+		return nil, errors.New("invoking domain is missing")
+	}
+	bodyHash, err := decodeLoggedHash(bodyHashBase64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid body hash: %v", err)
+	}
+	urlHash, err := decodeLoggedHash(urlHashBase64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL hash: %v", err)
+	}
+
+	requestInfo := &api.RequestInfo{
+		InvokingDomain: invokingDomain,
+		BodyHash:       bodyHash,
+		UrlHash:        urlHash,
+	}
+	signatory.SetRequestSignatures(requestInfo, signatures)
+	return requestInfo, nil
+}
+
+func decodeLoggedHash(hashBase64 string) ([]byte, error) {
+	hash, err := base64.StdEncoding.DecodeString(hashBase64)
+	if err != nil {
+		return nil, err
+	}
+	if len(hash) != 32 {
+		return nil, fmt.Errorf("got %d bytes, want a 32 byte SHA-256 hash", len(hash))
+	}
+	return hash, nil
+}
+
+func (s *verifylogSummary) add(response *api.AuthenticatedConnectionVerificationResponse, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.Records++
+	if err != nil {
+		s.VerificationErrors++
+		return
+	}
+	for _, verificationInfo := range response.GetVerificationInfo() {
+		if len(verificationInfo.SignatureResults) == 0 {
+			s.UnsignedRecords++
+		}
+		for _, result := range verificationInfo.SignatureResults {
+			statuses, ok := s.Counterparties[result.FromDomain]
+			if !ok {
+				statuses = map[string]int{}
+				s.Counterparties[result.FromDomain] = statuses
+			}
+			statuses[result.SignatureDecodeStatus.String()]++
+		}
+	}
+}
+
+func (s *verifylogSummary) addRedacted() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.Records++
+	s.RedactedRecords++
+}
+
+func (s *verifylogSummary) addParseError() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.Records++
+	s.ParseErrors++
+}
+
+func printVerifyLogSummary(out io.Writer, summary *verifylogSummary, format string) error {
+	switch format {
+	case "text":
+		fmt.Fprintf(out, "records: %d, unsigned: %d, redacted: %d, parse errors: %d, verification errors: %d\n",
+			summary.Records, summary.UnsignedRecords, summary.RedactedRecords, summary.ParseErrors, summary.VerificationErrors)
+
+		counterparties := make([]string, 0, len(summary.Counterparties))
+		for counterparty := range summary.Counterparties {
+			counterparties = append(counterparties, counterparty)
+		}
+		sort.Strings(counterparties)
+
+		w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+		for _, counterparty := range counterparties {
+			name := counterparty
+			if name == "" {
+				name = "(undecodable)"
+			}
+			fmt.Fprintf(w, "\n%s\n", name)
+
+			statuses := make([]string, 0, len(summary.Counterparties[counterparty]))
+			for status := range summary.Counterparties[counterparty] {
+				statuses = append(statuses, status)
+			}
+			sort.Strings(statuses)
+			for _, status := range statuses {
+				fmt.Fprintf(w, "  %s\t%d\n", status, summary.Counterparties[counterparty][status])
+			}
+		}
+		return w.Flush()
+	case "json":
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(summary)
+	default:
+		return fmt.Errorf("unknown output format %q, want text or json", format)
+	}
+}
@@ -0,0 +1,374 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IABTechLab/adscert/pkg/adscert/api"
+	"github.com/IABTechLab/adscert/pkg/adscert/discovery"
+	"github.com/IABTechLab/adscert/pkg/adscert/signatory"
+	"github.com/benbjohnson/clock"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func testHash(s string) []byte {
+	hash := sha256.Sum256([]byte(s))
+	return hash[:]
+}
+
+func testHashBase64(s string) string {
+	return base64.StdEncoding.EncodeToString(testHash(s))
+}
+
+func TestDecodeLoggedHash(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		input   string
+		want    []byte
+		wantErr bool
+	}{
+		{desc: "valid", input: testHashBase64("body"), want: testHash("body")},
+		{desc: "empty", input: "", wantErr: true},
+		{desc: "not base64", input: "not base64!", wantErr: true},
+		{desc: "url-safe base64", input: base64.URLEncoding.EncodeToString(bytes.Repeat([]byte{0xff}, 32)), wantErr: true},
+		{desc: "too short", input: base64.StdEncoding.EncodeToString(make([]byte, 16)), wantErr: true},
+	}
+	for _, tC := range testCases {
+		got, err := decodeLoggedHash(tC.input)
+		if (err != nil) != tC.wantErr {
+			t.Errorf("decodeLoggedHash() %s: got error %v, want error %v", tC.desc, err, tC.wantErr)
+			continue
+		}
+		if !bytes.Equal(got, tC.want) {
+			t.Errorf("decodeLoggedHash() %s: got %x, want %x", tC.desc, got, tC.want)
+		}
+	}
+}
+
+func TestParseCSVLogRecord(t *testing.T) {
+	bodyHash, urlHash := testHashBase64("body"), testHashBase64("url")
+	signature := "from=signer.com&from_key=abc&invoking=verifier.com; sigb=x&sigu=y"
+
+	testCases := []struct {
+		desc    string
+		line    string
+		want    *api.RequestInfo
+		wantErr bool
+	}{
+		{
+			desc: "signed",
+			line: fmt.Sprintf("verifier.com,%q,%s,%s", signature, bodyHash, urlHash),
+			want: &api.RequestInfo{
+				InvokingDomain: "verifier.com",
+				BodyHash:       testHash("body"),
+				UrlHash:        testHash("url"),
+				SignatureInfo:  []*api.SignatureInfo{{SignatureMessage: signature}},
+			},
+		},
+		{
+			desc: "unsigned",
+			line: fmt.Sprintf("verifier.com,,%s,%s", bodyHash, urlHash),
+			want: &api.RequestInfo{
+				InvokingDomain: "verifier.com",
+				BodyHash:       testHash("body"),
+				UrlHash:        testHash("url"),
+			},
+		},
+		{desc: "too few fields", line: fmt.Sprintf("verifier.com,%s,%s", bodyHash, urlHash), wantErr: true},
+		{desc: "too many fields", line: fmt.Sprintf("verifier.com,sig,%s,%s,extra", bodyHash, urlHash), wantErr: true},
+		{desc: "missing invoking domain", line: fmt.Sprintf(",sig,%s,%s", bodyHash, urlHash), wantErr: true},
+		{desc: "bad body hash", line: fmt.Sprintf("verifier.com,sig,bad,%s", urlHash), wantErr: true},
+		{desc: "bad URL hash", line: fmt.Sprintf("verifier.com,sig,%s,%s", bodyHash, base64.StdEncoding.EncodeToString([]byte("short"))), wantErr: true},
+		{desc: "unterminated quote", line: `verifier.com,"sig`, wantErr: true},
+		{desc: "redacted", line: fmt.Sprintf("verifier.com,%s,%s,%s", signatory.RedactedSignaturePrefix+"abc", bodyHash, urlHash), wantErr: true},
+	}
+	for _, tC := range testCases {
+		got, err := parseCSVLogRecord(tC.line)
+		if (err != nil) != tC.wantErr {
+			t.Errorf("parseCSVLogRecord() %s: got error %v, want error %v", tC.desc, err, tC.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(tC.want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("parseCSVLogRecord() %s diff (-want +got):\n%s", tC.desc, diff)
+		}
+	}
+}
+
+func TestParseJSONLogRecord(t *testing.T) {
+	bodyHash, urlHash := testHashBase64("body"), testHashBase64("url")
+
+	testCases := []struct {
+		desc    string
+		line    string
+		want    *api.RequestInfo
+		wantErr bool
+	}{
+		{
+			desc: "signature list",
+			line: fmt.Sprintf(`{"invoking_domain": "verifier.com", "signatures": ["a", "b"], "body_hash": %q, "url_hash": %q}`, bodyHash, urlHash),
+			want: &api.RequestInfo{
+				InvokingDomain: "verifier.com",
+				BodyHash:       testHash("body"),
+				UrlHash:        testHash("url"),
+				SignatureInfo:  []*api.SignatureInfo{{SignatureMessage: "a"}, {SignatureMessage: "b"}},
+			},
+		},
+		{
+			desc: "single signature",
+			line: fmt.Sprintf(`{"invoking_domain": "verifier.com", "signature": "a", "body_hash": %q, "url_hash": %q}`, bodyHash, urlHash),
+			want: &api.RequestInfo{
+				InvokingDomain: "verifier.com",
+				BodyHash:       testHash("body"),
+				UrlHash:        testHash("url"),
+				SignatureInfo:  []*api.SignatureInfo{{SignatureMessage: "a"}},
+			},
+		},
+		{
+			desc: "unsigned audit event",
+			line: fmt.Sprintf(`{"operation": "verify", "invoking_domain": "verifier.com", "body_hash": %q, "url_hash": %q}`, bodyHash, urlHash),
+			want: &api.RequestInfo{
+				InvokingDomain: "verifier.com",
+				BodyHash:       testHash("body"),
+				UrlHash:        testHash("url"),
+			},
+		},
+		{desc: "sign audit event", line: fmt.Sprintf(`{"operation": "sign", "invoking_domain": "verifier.com", "signature": "a", "body_hash": %q, "url_hash": %q}`, bodyHash, urlHash), wantErr: true},
+		{desc: "malformed", line: `{"invoking_domain": `, wantErr: true},
+		{desc: "missing invoking domain", line: fmt.Sprintf(`{"signature": "a", "body_hash": %q, "url_hash": %q}`, bodyHash, urlHash), wantErr: true},
+		{desc: "missing hashes", line: `{"invoking_domain": "verifier.com", "signature": "a"}`, wantErr: true},
+		{desc: "bad URL hash", line: fmt.Sprintf(`{"invoking_domain": "verifier.com", "body_hash": %q, "url_hash": "bad"}`, bodyHash), wantErr: true},
+		{desc: "redacted", line: fmt.Sprintf(`{"invoking_domain": "verifier.com", "signature": %q, "body_hash": %q, "url_hash": %q}`, signatory.RedactedSignaturePrefix+"abc", bodyHash, urlHash), wantErr: true},
+	}
+	for _, tC := range testCases {
+		got, err := parseJSONLogRecord(tC.line)
+		if (err != nil) != tC.wantErr {
+			t.Errorf("parseJSONLogRecord() %s: got error %v, want error %v", tC.desc, err, tC.wantErr)
+			continue
+		}
+		if diff := cmp.Diff(tC.want, got, protocmp.Transform()); diff != "" {
+			t.Errorf("parseJSONLogRecord() %s diff (-want +got):\n%s", tC.desc, diff)
+		}
+	}
+}
+
+func newTestVerifylogSummary() *verifylogSummary {
+	summary := &verifylogSummary{Counterparties: map[string]map[string]int{}}
+	summary.add(nil, fmt.Errorf("signatory unavailable"))
+	summary.add(&api.AuthenticatedConnectionVerificationResponse{
+		VerificationInfo: []*api.RequestVerificationInfo{{}},
+	}, nil)
+	summary.add(&api.AuthenticatedConnectionVerificationResponse{
+		VerificationInfo: []*api.RequestVerificationInfo{{
+			SignatureResults: []*api.SignatureVerificationResult{
+				{FromDomain: "b.com", SignatureDecodeStatus: api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID},
+				{FromDomain: "a.com", SignatureDecodeStatus: api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_INVALID_SIGNATURE},
+				{SignatureDecodeStatus: api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_SIGNATURE_MALFORMED},
+			},
+		}},
+	}, nil)
+	summary.add(&api.AuthenticatedConnectionVerificationResponse{
+		VerificationInfo: []*api.RequestVerificationInfo{{
+			SignatureResults: []*api.SignatureVerificationResult{
+				{FromDomain: "b.com", SignatureDecodeStatus: api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID},
+			},
+		}},
+	}, nil)
+	summary.addRedacted()
+	summary.addParseError()
+	return summary
+}
+
+func TestVerifylogSummary_Add(t *testing.T) {
+	summary := newTestVerifylogSummary()
+
+	if summary.Records != 6 || summary.UnsignedRecords != 1 || summary.RedactedRecords != 1 || summary.ParseErrors != 1 || summary.VerificationErrors != 1 {
+		t.Errorf("add(): got records %d, unsigned %d, redacted %d, parse errors %d, verification errors %d, want 6, 1, 1, 1, 1",
+			summary.Records, summary.UnsignedRecords, summary.RedactedRecords, summary.ParseErrors, summary.VerificationErrors)
+	}
+	want := map[string]map[string]int{
+		"a.com": {"SIGNATURE_DECODE_STATUS_INVALID_SIGNATURE": 1},
+		"b.com": {"SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID": 2},
+		"":      {"SIGNATURE_DECODE_STATUS_SIGNATURE_MALFORMED": 1},
+	}
+	if !reflect.DeepEqual(summary.Counterparties, want) {
+		t.Errorf("add(): got counterparties %v, want %v", summary.Counterparties, want)
+	}
+}
+
+func TestPrintVerifyLogSummary(t *testing.T) {
+	summary := newTestVerifylogSummary()
+
+	var text bytes.Buffer
+	if err := printVerifyLogSummary(&text, summary, "text"); err != nil {
+		t.Fatalf("printVerifyLogSummary() text: %v", err)
+	}
+	wantText := `records: 6, unsigned: 1, redacted: 1, parse errors: 1, verification errors: 1
+
+(undecodable)
+  SIGNATURE_DECODE_STATUS_SIGNATURE_MALFORMED  1
+
+a.com
+  SIGNATURE_DECODE_STATUS_INVALID_SIGNATURE  1
+
+b.com
+  SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID  2
+`
+	if text.String() != wantText {
+		t.Errorf("printVerifyLogSummary() text: got\n%s\nwant\n%s", text.String(), wantText)
+	}
+
+	var out bytes.Buffer
+	if err := printVerifyLogSummary(&out, summary, "json"); err != nil {
+		t.Fatalf("printVerifyLogSummary() json: %v", err)
+	}
+	got := &verifylogSummary{}
+	if err := json.Unmarshal(out.Bytes(), got); err != nil {
+		t.Fatalf("printVerifyLogSummary() json: %v", err)
+	}
+	if got.Records != summary.Records || got.UnsignedRecords != summary.UnsignedRecords || got.RedactedRecords != summary.RedactedRecords ||
+		got.ParseErrors != summary.ParseErrors || got.VerificationErrors != summary.VerificationErrors ||
+		!reflect.DeepEqual(got.Counterparties, summary.Counterparties) {
+		t.Errorf("printVerifyLogSummary() json: got %+v, want %+v", got, summary)
+	}
+
+	if err := printVerifyLogSummary(&out, summary, "yaml"); err == nil {
+		t.Errorf("printVerifyLogSummary() yaml: got nil error, want error")
+	}
+}
+
+func TestVerifyLogs(t *testing.T) {
+	records := map[string][]string{
+		"_delivery._adscert.signer.com":   {signatory.GenerateFakeAdsCertRecordForTesting("signer.com")},
+		"_delivery._adscert.verifier.com": {signatory.GenerateFakeAdsCertRecordForTesting("verifier.com")},
+	}
+	dir := t.TempDir()
+	snapshot, _ := json.Marshal(records)
+	snapshotPath := filepath.Join(dir, "snapshot.json")
+	if err := os.WriteFile(snapshotPath, snapshot, 0644); err != nil {
+		t.Fatalf("WriteFile(): %v", err)
+	}
+
+	_, signerKey := signatory.GenerateFakeKeyPairFromDomainNameForTesting("signer.com")
+	signer := signatory.NewLocalAuthenticatedConnectionsSignatoryWithOptions(
+		"signer.com", rand.Reader, clock.New(), discovery.NewSnapshotDNSResolver(records), discovery.NewDefaultDomainStore(),
+		[]string{base64.RawURLEncoding.EncodeToString(signerKey[:])},
+		&signatory.LocalAuthenticatedConnectionsSignatoryOptions{SynchronousLookupTimeout: time.Second})
+	sign := func(url string, body string) *api.RequestInfo {
+		requestInfo := &api.RequestInfo{}
+		signatory.SetRequestInfo(requestInfo, url, []byte(body))
+		response, err := signer.SignAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionSignatureRequest{RequestInfo: requestInfo})
+		if err != nil || response.SignatureOperationStatus != api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK {
+			t.Fatalf("SignAuthenticatedConnectionContext(): got %v, %v", response.GetSignatureOperationStatus(), err)
+		}
+		return response.RequestInfo
+	}
+
+	var csvLines, jsonLines []string
+	for i, body := range []string{"first", "second", "tampered"} {
+		requestInfo := sign(fmt.Sprintf("https://verifier.com/bid?id=%d", i), body)
+		bodyHash := requestInfo.BodyHash
+		if body == "tampered" {
+			bodyHash = testHash("something else")
+		}
+		signatures := signatory.GetSignatures(&api.AuthenticatedConnectionSignatureResponse{RequestInfo: requestInfo})
+		csvLines = append(csvLines, fmt.Sprintf("%s,%q,%s,%s", requestInfo.InvokingDomain, signatures[0],
+			base64.StdEncoding.EncodeToString(bodyHash), base64.StdEncoding.EncodeToString(requestInfo.UrlHash)))
+
+		jsonLine, _ := json.Marshal(&verifylogRecord{
+			Operation:      signatory.AuditOperationVerify,
+			InvokingDomain: requestInfo.InvokingDomain,
+			Signatures:     signatures,
+			BodyHash:       base64.StdEncoding.EncodeToString(bodyHash),
+			URLHash:        base64.StdEncoding.EncodeToString(requestInfo.UrlHash),
+		})
+		jsonLines = append(jsonLines, string(jsonLine))
+
+		// Audit logs interleave the events of signing, which aren't counted.
+		signEvent, _ := json.Marshal(&verifylogRecord{
+			Operation:      signatory.AuditOperationSign,
+			InvokingDomain: requestInfo.InvokingDomain,
+			Signatures:     signatures,
+			BodyHash:       base64.StdEncoding.EncodeToString(requestInfo.BodyHash),
+			URLHash:        base64.StdEncoding.EncodeToString(requestInfo.UrlHash),
+		})
+		jsonLines = append(jsonLines, string(signEvent))
+	}
+	redacted := signatory.RedactedSignaturePrefix + "abc"
+	csvLines = append(csvLines,
+		fmt.Sprintf("verifier.com,,%s,%s", testHashBase64("body"), testHashBase64("url")),
+		fmt.Sprintf("verifier.com,%s,%s,%s", redacted, testHashBase64("body"), testHashBase64("url")),
+		"not,a,record")
+	jsonLines = append(jsonLines,
+		fmt.Sprintf(`{"invoking_domain": "verifier.com", "body_hash": %q, "url_hash": %q}`, testHashBase64("body"), testHashBase64("url")),
+		fmt.Sprintf(`{"invoking_domain": "verifier.com", "signature": %q, "body_hash": %q, "url_hash": %q}`, redacted, testHashBase64("body"), testHashBase64("url")),
+		"{")
+
+	_, verifierKey := signatory.GenerateFakeKeyPairFromDomainNameForTesting("verifier.com")
+	testCases := []struct {
+		inputFormat string
+		lines       []string
+	}{
+		{"csv", csvLines},
+		{"jsonl", jsonLines},
+	}
+	for _, tC := range testCases {
+		logPath := filepath.Join(dir, "requests."+tC.inputFormat)
+		if err := os.WriteFile(logPath, []byte(strings.Join(tC.lines, "\n")+"\n"), 0644); err != nil {
+			t.Fatalf("WriteFile(): %v", err)
+		}
+
+		summary, err := verifyLogs(&verifylogParameters{
+			inputFormat:     tC.inputFormat,
+			workers:         2,
+			origin:          "verifier.com",
+			privateKey:      base64.RawURLEncoding.EncodeToString(verifierKey[:]),
+			dnsSnapshotPath: snapshotPath,
+			lookupTimeout:   time.Second,
+		}, []string{logPath})
+		if err != nil {
+			t.Fatalf("verifyLogs() %s: %v", tC.inputFormat, err)
+		}
+
+		if summary.Records != 6 || summary.UnsignedRecords != 1 || summary.RedactedRecords != 1 || summary.ParseErrors != 1 || summary.VerificationErrors != 0 {
+			t.Errorf("verifyLogs() %s: got records %d, unsigned %d, redacted %d, parse errors %d, verification errors %d, want 6, 1, 1, 1, 0",
+				tC.inputFormat, summary.Records, summary.UnsignedRecords, summary.RedactedRecords, summary.ParseErrors, summary.VerificationErrors)
+		}
+		want := map[string]map[string]int{
+			"signer.com": {
+				"SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID": 2,
+				"SIGNATURE_DECODE_STATUS_INVALID_SIGNATURE":  1,
+			},
+		}
+		if !reflect.DeepEqual(summary.Counterparties, want) {
+			t.Errorf("verifyLogs() %s: got counterparties %v, want %v", tC.inputFormat, summary.Counterparties, want)
+		}
+	}
+}
+
+func TestVerifyLogs_Errors(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		params *verifylogParameters
+	}{
+		{"missing origin", &verifylogParameters{inputFormat: "csv", privateKey: "key"}},
+		{"unknown input format", &verifylogParameters{inputFormat: "xml", origin: "verifier.com", privateKey: "key"}},
+		{"missing private key", &verifylogParameters{inputFormat: "csv", origin: "verifier.com"}},
+	}
+	for _, tC := range testCases {
+		if _, err := verifyLogs(tC.params, nil); err == nil {
+			t.Errorf("verifyLogs() %s: got nil error, want error", tC.desc)
+		}
+	}
+}
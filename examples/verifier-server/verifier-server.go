@@ -2,13 +2,10 @@ package main
 
 import (
 	crypto_rand "crypto/rand"
-	"encoding/base64"
 	"flag"
 	"fmt"
 	"io/ioutil"
-	"log"
 	"net/http"
-	"os"
 	"time"
 
 	"github.com/IABTechLab/adscert/pkg/adscert/api"
@@ -22,7 +19,7 @@ import (
 
 var (
 	origin           = flag.String("origin", "", "ads.cert Call Sign domain for the receiving party")
-	signatureLogFile = flag.String("signature_log_file", "", "(optional) write signature and hashes to file for offline verification")
+	signatureLogFile = flag.String("signature_log_file", "", "(optional) write audit events to a JSONL file for offline verification with adscert verifylog")
 )
 
 func main() {
@@ -32,31 +29,34 @@ func main() {
 
 	base64PrivateKeys := signatory.GenerateFakePrivateKeysForTesting(*origin)
 
-	var signatureFileLogger *log.Logger
+	var auditSink signatory.AuditSink
 	if *signatureLogFile != "" {
-		file, err := os.OpenFile(*signatureLogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		fileSink, err := signatory.NewRotatingFileAuditSink(*signatureLogFile, 0, 0)
 		if err != nil {
 			logger.Fatalf("Error opening signature log file %q: %v", *signatureLogFile, err)
 		}
-		defer file.Close()
+		defer fileSink.Close()
 
-		signatureFileLogger = log.New(file, "" /*=prefix*/, 0 /*=flag=*/)
+		asyncSink := signatory.NewAsyncAuditSink(fileSink, 1000)
+		defer asyncSink.Close()
+		auditSink = asyncSink
 	}
 
-	signatoryApi := signatory.NewLocalAuthenticatedConnectionsSignatory(
+	signatoryApi := signatory.NewLocalAuthenticatedConnectionsSignatoryWithOptions(
 		*origin,
 		crypto_rand.Reader,
 		clock.New(),
 		discovery.NewDefaultDnsResolver(),
 		discovery.NewDefaultDomainStore(),
-		time.Duration(30*time.Second), // domain check interval
-		time.Duration(30*time.Second), // domain renewal interval
-		base64PrivateKeys)
+		base64PrivateKeys,
+		&signatory.LocalAuthenticatedConnectionsSignatoryOptions{
+			DomainCheckInterval:   30 * time.Second,
+			DomainRenewalInterval: 30 * time.Second,
+			AuditSink:             auditSink,
+		})
 
 	demoServer := &DemoServer{
 		Signatory: signatoryApi,
-
-		SignatureFileLogger: signatureFileLogger,
 	}
 
 	http.HandleFunc("/request", demoServer.HandleRequest)
@@ -65,9 +65,7 @@ func main() {
 }
 
 type DemoServer struct {
-	Signatory signatory.AuthenticatedConnectionsSignatory
-
-	SignatureFileLogger *log.Logger
+	Signatory signatory.ContextAuthenticatedConnectionsSignatory
 }
 
 func (s *DemoServer) HandleRequest(w http.ResponseWriter, req *http.Request) {
@@ -93,29 +91,32 @@ func (s *DemoServer) HandleRequest(w http.ResponseWriter, req *http.Request) {
 	signatory.SetRequestInfo(reqInfo, reconstructedURL.String(), body)
 	signatory.SetRequestSignatures(reqInfo, signatureHeaders)
 
-	if s.SignatureFileLogger != nil {
-		s.SignatureFileLogger.Printf("%s,%s,%s,%s",
-			reqInfo.BodyHash,
-			reqInfo.SignatureInfo[0],
-			base64.StdEncoding.EncodeToString(reqInfo.BodyHash),
-			base64.StdEncoding.EncodeToString(reqInfo.UrlHash))
-	}
-
 	verificationRequest := &api.AuthenticatedConnectionVerificationRequest{RequestInfo: []*api.RequestInfo{reqInfo}}
-	verificationResponse, err := s.Signatory.VerifyAuthenticatedConnection(verificationRequest)
+	verificationResponse, err := s.Signatory.VerifyAuthenticatedConnectionContext(req.Context(), verificationRequest)
 	if err != nil {
 		logger.Errorf("unable to verify message: %s", err)
 	}
 
 	var bodyValid, urlValid bool
-	for _, decode := range verificationResponse.VerificationInfo[0].SignatureDecodeStatus {
-		if decode == api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID {
-			bodyValid = true
-			urlValid = true
-			break
-		} else if decode == api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_VALID {
-			bodyValid = true
-			break
+	if verificationResponse == nil || len(verificationResponse.VerificationInfo) == 0 {
+		logger.Infof("no verification performed: %s", verificationResponse.GetVerificationOperationStatus())
+	} else {
+		for _, result := range verificationResponse.VerificationInfo[0].SignatureResults {
+			if result.ErrorCode != "" {
+				logger.Infof("signature from %s (key %s) to %s (key %s) at %s failed: %s (%s)",
+					result.FromDomain, result.FromKey, result.ToDomain, result.ToKey, result.Timestamp, result.Reason, result.ErrorCode)
+			}
+		}
+
+		for _, decode := range verificationResponse.VerificationInfo[0].SignatureDecodeStatus {
+			if decode == api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID {
+				bodyValid = true
+				urlValid = true
+				break
+			} else if decode == api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_VALID {
+				bodyValid = true
+				break
+			}
 		}
 	}
 
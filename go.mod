@@ -6,8 +6,10 @@ require (
 	github.com/benbjohnson/clock v1.3.0
 	github.com/google/go-cmp v0.5.9
 	github.com/google/tink/go v1.6.1
+	github.com/miekg/dns v1.1.50
 	github.com/prometheus/client_golang v1.12.1
 	github.com/spf13/cobra v1.3.0
+	go.etcd.io/bbolt v1.3.6
 	golang.org/x/crypto v0.1.0
 	golang.org/x/net v0.1.0
 	golang.org/x/sync v0.0.0-20220929204114-8fcdb60fdcc0
@@ -10,6 +10,7 @@ type DiscoveryErrorCode *errorcode.Error
 
 var (
 	ErrDNSLookup             DiscoveryErrorCode = errorcode.New("dns_lookup", errors.New("failed to lookup DNS"))
+	ErrDNSSECValidation      DiscoveryErrorCode = errorcode.New("dnssec_validation", errors.New("failed to validate DNSSEC chain of trust"))
 	ErrDNSDecodePolicy       DiscoveryErrorCode = errorcode.New("dns_decode_policy", errors.New("failed to decode dns record policy"))
 	ErrDNSDecodeKeys         DiscoveryErrorCode = errorcode.New("dns_decode_key", errors.New("failed to decode dns record keys"))
 	ErrDiscoverySharedSecret DiscoveryErrorCode = errorcode.New("discovery_create_shared_secret", errors.New("failed to create shared secret"))
@@ -21,6 +22,9 @@ var (
 	ErrSigningGenerateNonce      SigningErrorCode = errorcode.New("generate_nonce", errors.New("failed to generate nonce"))
 	ErrSigningCounterpartyLookup SigningErrorCode = errorcode.New("invocation_counterparty_lookup", errors.New("failed to lookup invocation counterparty"))
 	ErrSigningEmbossMessage      SigningErrorCode = errorcode.New("emboss_message", errors.New("failed to emboss message"))
+	ErrSigningUnknownOrigin      SigningErrorCode = errorcode.New("unknown_origin", errors.New("requested origin is not hosted by this signatory"))
+	ErrSigningUnknownStatus      SigningErrorCode = errorcode.New("unknown_signing_status", errors.New("requested signing status is not known"))
+	ErrSigningDeactivated        SigningErrorCode = errorcode.New("signing_deactivated", errors.New("signing is deactivated for this origin or counterparty"))
 )
 
 type VerifyErrorCode *errorcode.Error
@@ -31,4 +35,11 @@ var (
 	ErrVerifyCounterpartyLookup           VerifyErrorCode = errorcode.New("counterparty_lookup", errors.New("failed to lookup signature counterparty"))
 	ErrVerifyMissingSharedSecret          VerifyErrorCode = errorcode.New("missing_shared_secret", errors.New("signature counterparty missing shared secret"))
 	ErrVerifyInvalidSignature             VerifyErrorCode = errorcode.New("invalid_signature", errors.New("signature is not valid"))
+	ErrVerifyStaleSignature               VerifyErrorCode = errorcode.New("stale_signature", errors.New("signature timestamp is outside the freshness window"))
+	ErrVerifyReplayedSignature            VerifyErrorCode = errorcode.New("replayed_signature", errors.New("signature nonce has already been seen"))
+	ErrVerifyReplayCacheFull              VerifyErrorCode = errorcode.New("replay_cache_full", errors.New("replay cache is full of unexpired nonces"))
+	ErrVerifyKeyNotPublished              VerifyErrorCode = errorcode.New("key_not_published", errors.New("signature key alias is no longer published"))
+	ErrVerifyWrongRecipient               VerifyErrorCode = errorcode.New("wrong_recipient", errors.New("signature is addressed to a different recipient"))
+	ErrVerifyRejectedSigningStatus        VerifyErrorCode = errorcode.New("rejected_signing_status", errors.New("signature status is rejected by policy"))
+	ErrVerifyDeactivated                  VerifyErrorCode = errorcode.New("verification_deactivated", errors.New("verification is deactivated for this origin or counterparty"))
 )
@@ -25,3 +25,46 @@ const (
 	StatusSuppressed
 	StatusDelayed
 )
+
+var statusNames = map[AuthenticatedConnectionProtocolStatus]string{
+	StatusUnspecified:                    "unspecified",
+	StatusOK:                             "ok",
+	StatusDeactivated:                    "deactivated",
+	StatusUnavailable:                    "unavailable",
+	StatusTesting:                        "testing",
+	StatusNotYetChecked:                  "not_yet_checked",
+	StatusErrorOnSignature:               "error_on_signature",
+	StatusErrorOnDNS:                     "error_on_dns",
+	StatusErrorOnDNSSEC:                  "error_on_dnssec",
+	StatusErrorOnAdsCertConfigParse:      "error_on_adscert_config_parse",
+	StatusErrorOnAdsCertConfigEval:       "error_on_adscert_config_eval",
+	StatusErrorOnKeyValidation:           "error_on_key_validation",
+	StatusErrorOnSharedSecretCalculation: "error_on_shared_secret_calculation",
+	StatusKeyFetchPending:                "key_fetch_pending",
+	StatusReviewPending:                  "review_pending",
+	StatusDnsReturnedRCode:               "dns_returned_rcode",
+	StatusADPFParseError:                 "adpf_parse_error",
+	StatusADCRTDParseError:               "adcrtd_parse_error",
+	StatusAdvisoryOnly:                   "advisory_only",
+	StatusSuppressed:                     "suppressed",
+	StatusDelayed:                        "delayed",
+}
+
+// Name returns a readable name for the status, such as "testing".  Unknown
+// status values are named by their number.
+func (s AuthenticatedConnectionProtocolStatus) Name() string {
+	if name, ok := statusNames[s]; ok {
+		return name
+	}
+	return StatusToString(s)
+}
+
+// StatusFromName returns the status with the given readable name.
+func StatusFromName(name string) (AuthenticatedConnectionProtocolStatus, bool) {
+	for status, statusName := range statusNames {
+		if statusName == name {
+			return status, true
+		}
+	}
+	return StatusUnspecified, false
+}
@@ -69,6 +69,18 @@ func (s *AuthenticatedConnectionSignature) GetAttributeToKey() string {
 	return s.toKey
 }
 
+func (s *AuthenticatedConnectionSignature) GetAttributeTimestamp() string {
+	return s.timestamp
+}
+
+func (s *AuthenticatedConnectionSignature) GetAttributeNonce() string {
+	return s.nonce
+}
+
+func (s *AuthenticatedConnectionSignature) GetAttributeStatus() AuthenticatedConnectionProtocolStatus {
+	return s.status
+}
+
 func (s *AuthenticatedConnectionSignature) GetAttributeStatusAsString() string {
 	return StatusToString(s.status)
 }
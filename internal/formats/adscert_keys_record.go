@@ -4,6 +4,10 @@ import (
 	"strings"
 )
 
+// adsCertKeysRecordPrefix is the fixed version, key algorithm and hash
+// algorithm portion of an ads.cert keys record.
+const adsCertKeysRecordPrefix = "v=adcrtd k=x25519 h=sha256"
+
 type ParsedPublicKey struct {
 	PublicKeyBytes []byte
 	KeyAlias       string
@@ -11,11 +15,27 @@ type ParsedPublicKey struct {
 
 type AdsCertKeys struct {
 	PublicKeys []ParsedPublicKey
+
+	// PrimaryKeyAlias names the key counterparties should use when signing
+	// requests to this domain.  It is optional; records without it are
+	// treated as having their first listed key as primary.
+	PrimaryKeyAlias string
+}
+
+// GetPrimaryKey returns the key marked as primary, or the first listed key
+// when the record doesn't mark one.
+func (k *AdsCertKeys) GetPrimaryKey() (ParsedPublicKey, bool) {
+	for _, key := range k.PublicKeys {
+		if k.PrimaryKeyAlias == "" || key.KeyAlias == k.PrimaryKeyAlias {
+			return key, true
+		}
+	}
+	return ParsedPublicKey{}, false
 }
 
 func DecodeAdsCertKeysRecord(keysRecord string) (*AdsCertKeys, error) {
 	parsedKeys := &AdsCertKeys{}
-	var versionOK, keyAlgoOK, hashAlgoOK int
+	var versionOK, keyAlgoOK, hashAlgoOK, primaryOK int
 	keysRecord = strings.TrimSpace(keysRecord)
 
 	if keysRecord == "" {
@@ -54,6 +74,9 @@ func DecodeAdsCertKeysRecord(keysRecord string) (*AdsCertKeys, error) {
 				}
 				break
 			}
+		case "primary":
+			parsedKeys.PrimaryKeyAlias = value
+			primaryOK++
 		case "p":
 			publicKeyBytes, err := ParseBase64EncodedKey(value, 32)
 			if err != nil {
@@ -78,9 +101,43 @@ func DecodeAdsCertKeysRecord(keysRecord string) (*AdsCertKeys, error) {
 	if len(parsedKeys.PublicKeys) == 0 {
 		return nil, ErrPublicKeysMissing
 	}
+	if primaryOK > 1 {
+		return nil, ErrPrimaryKeyWrongNumber
+	}
+	if _, ok := parsedKeys.GetPrimaryKey(); !ok {
+		return nil, ErrPrimaryKeyNotListed
+	}
 	return parsedKeys, nil
 }
 
+// EncodeAdsCertKeysRecord produces the TXT record value published at
+// _delivery._adscert.<domain> for the given public keys, listed in order, and
+// marks the primary key when one is set.
+func EncodeAdsCertKeysRecord(keys *AdsCertKeys) (string, error) {
+	if keys == nil || len(keys.PublicKeys) == 0 {
+		return "", ErrPublicKeysMissing
+	}
+
+	if _, ok := keys.GetPrimaryKey(); !ok {
+		return "", ErrPrimaryKeyNotListed
+	}
+
+	var sb strings.Builder
+	sb.WriteString(adsCertKeysRecordPrefix)
+	if keys.PrimaryKeyAlias != "" {
+		sb.WriteString(" primary=")
+		sb.WriteString(keys.PrimaryKeyAlias)
+	}
+	for _, key := range keys.PublicKeys {
+		if len(key.PublicKeyBytes) != 32 {
+			return "", ErrWrongKeySize
+		}
+		sb.WriteString(" p=")
+		sb.WriteString(EncodeKeyBase64(key.PublicKeyBytes))
+	}
+	return sb.String(), nil
+}
+
 func ExtractKeyAliasFromPublicKeyBase64(publicKeyBase64 string) string {
 	return publicKeyBase64[:6]
 }
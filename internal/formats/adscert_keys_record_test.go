@@ -43,6 +43,11 @@ var (
 	}
 )
 
+var wantAdsCertWithTwoKeysPrimaryMarked = &formats.AdsCertKeys{
+	PublicKeys:      wantAdsCertWithTwoKeys.PublicKeys,
+	PrimaryKeyAlias: "VfLEG8",
+}
+
 func TestDecodeAdsCertKeysRecord(t *testing.T) {
 	testCases := []struct {
 		desc  string
@@ -136,6 +141,21 @@ func TestDecodeAdsCertKeysRecord(t *testing.T) {
 			input:   "v=adcrtd k=x25519 h=sha256",
 			wantErr: formats.ErrPublicKeysMissing,
 		},
+		{
+			desc:            "primary key marker",
+			input:           "v=adcrtd k=x25519 h=sha256 primary=VfLEG8 p=Bm8J1RW3RxHp_-mx3lE7eAuYObfALvwurVjXtcaYFVA p=VfLEG883mudlLgxEA3RJvXm32PowzMgTZGOGCT72zWw",
+			wantAdsCertKeys: wantAdsCertWithTwoKeysPrimaryMarked,
+		},
+		{
+			desc:    "primary key not listed",
+			input:   "v=adcrtd k=x25519 h=sha256 primary=AAAAAA p=Bm8J1RW3RxHp_-mx3lE7eAuYObfALvwurVjXtcaYFVA",
+			wantErr: formats.ErrPrimaryKeyNotListed,
+		},
+		{
+			desc:    "too many primary key markers",
+			input:   "v=adcrtd k=x25519 h=sha256 primary=Bm8J1R primary=Bm8J1R p=Bm8J1RW3RxHp_-mx3lE7eAuYObfALvwurVjXtcaYFVA",
+			wantErr: formats.ErrPrimaryKeyWrongNumber,
+		},
 		{
 			desc:    "incorrect version placement",
 			input:   "k=x25519 v=adcrtd h=sha256 p=Bm8J1RW3RxHp_-mx3lE7eAuYObfALvwurVjXtcaYFVA",
@@ -205,3 +225,72 @@ func TestDecodeAdsCertKeysRecord(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodeAdsCertKeysRecord(t *testing.T) {
+	testCases := []struct {
+		desc string
+		keys *formats.AdsCertKeys
+
+		wantErr    error
+		wantRecord string
+	}{
+		{
+			desc:       "one key",
+			keys:       wantAdsCertWithOneKey,
+			wantRecord: "v=adcrtd k=x25519 h=sha256 p=Bm8J1RW3RxHp_-mx3lE7eAuYObfALvwurVjXtcaYFVA",
+		},
+		{
+			desc:       "two keys",
+			keys:       wantAdsCertWithTwoKeys,
+			wantRecord: "v=adcrtd k=x25519 h=sha256 p=Bm8J1RW3RxHp_-mx3lE7eAuYObfALvwurVjXtcaYFVA p=VfLEG883mudlLgxEA3RJvXm32PowzMgTZGOGCT72zWw",
+		},
+		{
+			desc:       "primary key marked",
+			keys:       wantAdsCertWithTwoKeysPrimaryMarked,
+			wantRecord: "v=adcrtd k=x25519 h=sha256 primary=VfLEG8 p=Bm8J1RW3RxHp_-mx3lE7eAuYObfALvwurVjXtcaYFVA p=VfLEG883mudlLgxEA3RJvXm32PowzMgTZGOGCT72zWw",
+		},
+		{
+			desc: "primary key not listed",
+			keys: &formats.AdsCertKeys{
+				PublicKeys:      wantAdsCertWithOneKey.PublicKeys,
+				PrimaryKeyAlias: "VfLEG8",
+			},
+			wantErr: formats.ErrPrimaryKeyNotListed,
+		},
+		{
+			desc:    "no keys",
+			keys:    &formats.AdsCertKeys{},
+			wantErr: formats.ErrPublicKeysMissing,
+		},
+		{
+			desc: "wrong key size",
+			keys: &formats.AdsCertKeys{
+				PublicKeys: []formats.ParsedPublicKey{{PublicKeyBytes: []byte{0x01, 0x02}}},
+			},
+			wantErr: formats.ErrWrongKeySize,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			gotRecord, err := formats.EncodeAdsCertKeysRecord(tC.keys)
+			if !errors.Is(err, tC.wantErr) {
+				t.Fatalf("mismatched error: got %v, want %v", err, tC.wantErr)
+			}
+			if gotRecord != tC.wantRecord {
+				t.Errorf("EncodeAdsCertKeysRecord(): got %q, want %q", gotRecord, tC.wantRecord)
+			}
+			if err != nil {
+				return
+			}
+
+			// The encoded record must parse back to the same keys.
+			gotAdsCertKeys, err := formats.DecodeAdsCertKeysRecord(gotRecord)
+			if err != nil {
+				t.Fatalf("DecodeAdsCertKeysRecord(%q) unexpected error: %v", gotRecord, err)
+			}
+			if diff := cmp.Diff(gotAdsCertKeys, tC.keys); diff != "" {
+				t.Errorf("mismatched round trip representation\n%s", diff)
+			}
+		})
+	}
+}
@@ -57,3 +57,19 @@ func DecodeAdsCertPolicyRecord(input string) (*AdsCertPolicy, error) {
 	}
 	return parsedAdsCertPolicy, nil
 }
+
+// EncodeAdsCertPolicyRecord produces the TXT record value published at
+// _adscert.<domain> to delegate the domain to an ads.cert Call Sign.
+func EncodeAdsCertPolicyRecord(policy *AdsCertPolicy) (string, error) {
+	if policy == nil || policy.CanonicalCallsignDomain == "" {
+		return "", ErrEmptyInput
+	}
+	tldPlusOne, err := publicsuffix.EffectiveTLDPlusOne(policy.CanonicalCallsignDomain)
+	if err != nil {
+		return "", fmt.Errorf("callsign domain parse error: %v %w", err, ErrPublicSuffixParseFailure)
+	}
+	if tldPlusOne != policy.CanonicalCallsignDomain {
+		return "", fmt.Errorf("callsign error: %s %w", policy.CanonicalCallsignDomain, ErrNotTLDPlusOneDomain)
+	}
+	return "v=adpf a=" + policy.CanonicalCallsignDomain, nil
+}
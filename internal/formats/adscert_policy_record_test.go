@@ -76,3 +76,56 @@ func TestDecodeAdsCertPolicyRecord(t *testing.T) {
 		})
 	}
 }
+
+func TestEncodeAdsCertPolicyRecord(t *testing.T) {
+	testCases := []struct {
+		desc   string
+		policy *formats.AdsCertPolicy
+
+		wantErr    error
+		wantRecord string
+	}{
+		{
+			desc:       "normal input",
+			policy:     wantAdsCertPolicyWithAlias,
+			wantRecord: "v=adpf a=adscorp.com",
+		},
+		{
+			desc:    "empty callsign",
+			policy:  &formats.AdsCertPolicy{},
+			wantErr: formats.ErrEmptyInput,
+		},
+		{
+			desc:    "not a public suffix",
+			policy:  &formats.AdsCertPolicy{CanonicalCallsignDomain: "adscorp"},
+			wantErr: formats.ErrPublicSuffixParseFailure,
+		},
+		{
+			desc:    "subdomain callsign",
+			policy:  &formats.AdsCertPolicy{CanonicalCallsignDomain: "subdomain.adscorp.com"},
+			wantErr: formats.ErrNotTLDPlusOneDomain,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			gotRecord, err := formats.EncodeAdsCertPolicyRecord(tC.policy)
+			if !errors.Is(err, tC.wantErr) {
+				t.Errorf("mismatched error: got %v, want %v", err, tC.wantErr)
+			}
+			if gotRecord != tC.wantRecord {
+				t.Errorf("EncodeAdsCertPolicyRecord(): got %q, want %q", gotRecord, tC.wantRecord)
+			}
+			if err != nil {
+				return
+			}
+
+			gotAdsCertPolicy, err := formats.DecodeAdsCertPolicyRecord(gotRecord)
+			if err != nil {
+				t.Fatalf("DecodeAdsCertPolicyRecord(%q) unexpected error: %v", gotRecord, err)
+			}
+			if diff := cmp.Diff(gotAdsCertPolicy, tC.policy); diff != "" {
+				t.Errorf("mismatched round trip representation\n%s", diff)
+			}
+		})
+	}
+}
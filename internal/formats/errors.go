@@ -10,6 +10,8 @@ var (
 	ErrKeyAlgorithmWrongNumber  = errors.New("key algorithm missing or too many")
 	ErrHashAlgorithmWrongNumber = errors.New("hash algorithm missing or too many")
 	ErrPublicKeysMissing        = errors.New("public keys missing")
+	ErrPrimaryKeyWrongNumber    = errors.New("too many primary key markers")
+	ErrPrimaryKeyNotListed      = errors.New("primary key not among listed keys")
 	ErrWrongKeySize             = errors.New("wrong key size")
 	ErrZeroValueKey             = errors.New("zero-value key")
 	ErrEmptyKey                 = errors.New("empty value for key")
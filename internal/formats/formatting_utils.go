@@ -53,3 +53,19 @@ func ParseBase64EncodedKey(encodedKey string, length int) ([]byte, error) {
 func EncodeKeyBase64(keyBytes []byte) string {
 	return base64.RawURLEncoding.EncodeToString(keyBytes)
 }
+
+// MaxTXTStringLength is the longest character-string a DNS TXT record can
+// hold.  Longer values are published as several strings in one record, which
+// resolvers concatenate.
+const MaxTXTStringLength = 255
+
+// SplitTXTRecordStrings splits a TXT record value into character-strings of at
+// most MaxTXTStringLength bytes.
+func SplitTXTRecordStrings(value string) []string {
+	var result []string
+	for len(value) > MaxTXTStringLength {
+		result = append(result, value[:MaxTXTStringLength])
+		value = value[MaxTXTStringLength:]
+	}
+	return append(result, value)
+}
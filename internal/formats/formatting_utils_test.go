@@ -2,6 +2,7 @@ package formats_test
 
 import (
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/IABTechLab/adscert/internal/formats"
@@ -186,3 +187,40 @@ func TestParseBase64EncodedKey(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitTXTRecordStrings(t *testing.T) {
+	long := strings.Repeat("a", 300)
+	testCases := []struct {
+		desc  string
+		value string
+
+		want []string
+	}{
+		{
+			desc:  "short value",
+			value: "v=adpf a=adscorp.com",
+			want:  []string{"v=adpf a=adscorp.com"},
+		},
+		{
+			desc:  "exactly one string",
+			value: long[:255],
+			want:  []string{long[:255]},
+		},
+		{
+			desc:  "split across strings",
+			value: long,
+			want:  []string{long[:255], long[255:]},
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got := formats.SplitTXTRecordStrings(tC.value)
+			if diff := cmp.Diff(got, tC.want); diff != "" {
+				t.Errorf("SplitTXTRecordStrings() mismatch\n%s", diff)
+			}
+			if joined := strings.Join(got, ""); joined != tC.value {
+				t.Errorf("SplitTXTRecordStrings() joined: got %q, want %q", joined, tC.value)
+			}
+		})
+	}
+}
@@ -5,13 +5,25 @@ import (
 	"encoding/base64"
 	"fmt"
 
+	"github.com/IABTechLab/adscert/pkg/adscert/keysecurity"
 	"golang.org/x/crypto/curve25519"
 )
 
 // n.b. compile time check that keyGeneratorImpl implements KeyGenerator interface
 var _ KeyGenerator = (*keyGeneratorImpl)(nil)
 
+// NewKeyGenerator returns a KeyGenerator which encrypts each new private key
+// with the key encryption key at keyEncryptionKeyURI.
+func NewKeyGenerator(keyEncrypter keysecurity.KeyEncrypter, keyEncryptionKeyURI string) KeyGenerator {
+	return &keyGeneratorImpl{
+		keyEncrypter:        keyEncrypter,
+		keyEncryptionKeyURI: keyEncryptionKeyURI,
+	}
+}
+
 type keyGeneratorImpl struct {
+	keyEncrypter        keysecurity.KeyEncrypter
+	keyEncryptionKeyURI string
 }
 
 func (k *keyGeneratorImpl) GenerateKeysForConfig(config *AdsCertKeyConfig) error {
@@ -19,12 +31,20 @@ func (k *keyGeneratorImpl) GenerateKeysForConfig(config *AdsCertKeyConfig) error
 	if err != nil {
 		return err
 	}
+	defer clearKey(privateKeyBytes[:])
+
 	config.PublicKeyBase64 = publicKeyBase64
 	config.KeyID = publicKeyBase64[0:6]
 
-	// Somehow we need to indicate which KMS is preferred
+	if k.keyEncrypter != nil {
+		encryptedPrivateKey, err := k.keyEncrypter.EncryptKeyToBase64Ciphertext(k.keyEncryptionKeyURI, privateKeyBytes[:], publicKeyBase64)
+		if err != nil {
+			return fmt.Errorf("unable to encrypt private key: %v", err)
+		}
+		config.EncryptedPrivateKey = encryptedPrivateKey
+		config.KeyEncryptionKeyURI = k.keyEncryptionKeyURI
+	}
 
-	clearKey(privateKeyBytes[:])
 	return nil
 }
 
@@ -0,0 +1,121 @@
+package keyring
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrInvalidKeyTransition = errors.New("invalid key status transition")
+	ErrNoPrimaryKey         = errors.New("transition would leave realm without a primary key")
+	ErrKeyNotFound          = errors.New("key not found in realm")
+	ErrRealmNotFound        = errors.New("call sign realm not found in keyring")
+)
+
+// KeyLifecyclePolicy sets how long a key dwells in each status before Rotate
+// advances it.
+//
+// A key is created unpublished, activated once it may be published in DNS,
+// becomes primary when it is used for signing, is demoted to secondary (still
+// published so in-flight signatures verify) when its successor is promoted,
+// and is finally archived.
+type KeyLifecyclePolicy struct {
+	CreatedDwellTime   time.Duration // created → activated
+	ActivatedDwellTime time.Duration // activated → primary, so counterparties pick up the new key
+	PrimaryDwellTime   time.Duration // primary → secondary, the rotation period
+	SecondaryDwellTime time.Duration // secondary → archived
+}
+
+// DefaultKeyLifecyclePolicy rotates the primary key quarterly.
+var DefaultKeyLifecyclePolicy = KeyLifecyclePolicy{
+	CreatedDwellTime:   24 * time.Hour,
+	ActivatedDwellTime: 7 * 24 * time.Hour,
+	PrimaryDwellTime:   90 * 24 * time.Hour,
+	SecondaryDwellTime: 7 * 24 * time.Hour,
+}
+
+// allowedKeyTransitions lists the statuses each status may advance to.
+var allowedKeyTransitions = map[string][]string{
+	KeyStatusCreated:   {KeyStatusActivated, KeyStatusArchived},
+	KeyStatusActivated: {KeyStatusPrimary, KeyStatusArchived},
+	KeyStatusPrimary:   {KeyStatusSecondary},
+	KeyStatusSecondary: {KeyStatusArchived},
+}
+
+// GetStatus returns the lifecycle status of the key.  Keys written before
+// statuses were tracked are treated as created.
+func (k *AdsCertKeyConfig) GetStatus() string {
+	if k.Status == "" {
+		return KeyStatusCreated
+	}
+	return k.Status
+}
+
+// isLegacyKey reports whether the key was written before statuses and
+// encrypted private keys were tracked.  Such a key can't be loaded for
+// signing, so Rotate archives it and creates a replacement.
+func (k *AdsCertKeyConfig) isLegacyKey() bool {
+	return k.Status == "" && k.EncryptedPrivateKey == ""
+}
+
+// transitionKey moves a key to a new status and records when it happened.  A
+// primary key may only be demoted while the realm has another primary key.
+func transitionKey(realm *AdsCertRealm, key *AdsCertKeyConfig, status string, actionTimestamp string) error {
+	from := key.GetStatus()
+	allowed := false
+	for _, s := range allowedKeyTransitions[from] {
+		allowed = allowed || s == status
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %s to %s for key %s", ErrInvalidKeyTransition, from, status, key.KeyID)
+	}
+	if from == KeyStatusPrimary && countKeysWithStatus(realm, KeyStatusPrimary) < 2 {
+		return fmt.Errorf("%w: realm %s key %s", ErrNoPrimaryKey, realm.Realm, key.KeyID)
+	}
+
+	key.Status = status
+	switch status {
+	case KeyStatusActivated:
+		key.TimestampActivated = actionTimestamp
+	case KeyStatusPrimary:
+		key.TimestampPrimaried = actionTimestamp
+	case KeyStatusSecondary:
+		key.TimestampSecondaried = actionTimestamp
+	case KeyStatusArchived:
+		key.TimestampArchived = actionTimestamp
+	}
+	return nil
+}
+
+func countKeysWithStatus(realm *AdsCertRealm, status string) int {
+	count := 0
+	for _, k := range realm.Keys {
+		if k.GetStatus() == status {
+			count++
+		}
+	}
+	return count
+}
+
+// keysWithStatus returns the keys with a status, oldest first.
+func keysWithStatus(realm *AdsCertRealm, status string) []*AdsCertKeyConfig {
+	var result []*AdsCertKeyConfig
+	for _, k := range realm.Keys {
+		if k.GetStatus() == status {
+			result = append(result, k)
+		}
+	}
+	return result
+}
+
+// dwellElapsed reports whether at least dwell has passed since timestamp.  A
+// missing or malformed timestamp counts as elapsed so that keys can't get
+// stuck in a status.
+func dwellElapsed(timestamp string, dwell time.Duration, now time.Time) bool {
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return true
+	}
+	return !now.Before(t.Add(dwell))
+}
@@ -31,6 +31,22 @@ func (p *AdsCertCallSignConfigList) GetCallSignRealm(adscertCallSign string, rea
 	return p.getCallSignConfig(adscertCallSign).getRealm(realm)
 }
 
+// FindCallSignRealm returns the realm of a Call Sign without adding either to
+// the keyring when missing.
+func (p *AdsCertCallSignConfigList) FindCallSignRealm(adscertCallSign string, realm string) (*AdsCertRealm, bool) {
+	for _, c := range p.Domains {
+		if c.Domain != adscertCallSign {
+			continue
+		}
+		for _, r := range c.Realms {
+			if r.Realm == realm {
+				return r, true
+			}
+		}
+	}
+	return nil, false
+}
+
 func (p *AdsCertCallSignConfigList) GetAllCallSignRealms() []*AdsCertRealm {
 	var result []*AdsCertRealm
 	for _, c := range p.Domains {
@@ -64,3 +80,38 @@ func (p *AdsCertCallSignConfig) getRealm(realm string) *AdsCertRealm {
 	p.Realms = append(p.Realms, c)
 	return c
 }
+
+// Key lifecycle statuses recorded in AdsCertKeyConfig.Status.
+const (
+	KeyStatusCreated   = "created"
+	KeyStatusActivated = "activated"
+	KeyStatusPrimary   = "primary"
+	KeyStatusSecondary = "secondary"
+	KeyStatusArchived  = "archived"
+)
+
+// IsActive reports whether the key is published and usable for signing and
+// verification.
+func (k *AdsCertKeyConfig) IsActive() bool {
+	switch k.GetStatus() {
+	case KeyStatusActivated, KeyStatusPrimary, KeyStatusSecondary:
+		return true
+	}
+	return false
+}
+
+// IsPublishable reports whether the key's public key belongs in DNS: either it
+// is active, or it is pending activation and is published ahead of rotation so
+// that counterparties have it cached by the time it is used.
+func (k *AdsCertKeyConfig) IsPublishable() bool {
+	return k.IsActive() || k.GetStatus() == KeyStatusCreated
+}
+
+// GetCallSigns returns the ads.cert Call Signs present in the keyring.
+func (p *AdsCertCallSignConfigList) GetCallSigns() []string {
+	var result []string
+	for _, c := range p.Domains {
+		result = append(result, c.Domain)
+	}
+	return result
+}
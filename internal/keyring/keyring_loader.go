@@ -0,0 +1,83 @@
+package keyring
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/IABTechLab/adscert/pkg/adscert/keysecurity"
+	"golang.org/x/crypto/curve25519"
+)
+
+var (
+	ErrNoActiveKeys = errors.New("no active keys in keyring")
+	ErrLegacyKeys   = errors.New(`keyring holds keys without a status or an encrypted private key; run "adscert keyringrotate" to archive them and create replacements`)
+)
+
+// DecryptActivePrivateKeys decrypts the private keys of every active key held
+// for an ads.cert Call Sign, across all of its realms, and returns them base64
+// encoded.  Each decrypted key is checked against its recorded public key.
+// When there are no active keys because the call sign only holds legacy keys,
+// ErrLegacyKeys is returned rather than ErrNoActiveKeys.
+func DecryptActivePrivateKeys(configList *AdsCertCallSignConfigList, adscertCallSign string, keyEncrypter keysecurity.KeyEncrypter) ([]string, error) {
+	var privateKeys []string
+	seen := map[string]bool{}
+	hasLegacyKeys := false
+
+	for _, c := range configList.Domains {
+		if c.Domain != adscertCallSign {
+			continue
+		}
+		for _, r := range c.Realms {
+			for _, k := range r.Keys {
+				hasLegacyKeys = hasLegacyKeys || k.isLegacyKey()
+				if !k.IsActive() || seen[k.PublicKeyBase64] {
+					continue
+				}
+				privateKey, err := decryptPrivateKey(k, keyEncrypter)
+				if err != nil {
+					return nil, fmt.Errorf("unable to load key %s for %s: %v", k.KeyID, adscertCallSign, err)
+				}
+				seen[k.PublicKeyBase64] = true
+				privateKeys = append(privateKeys, privateKey)
+			}
+		}
+	}
+
+	if len(privateKeys) == 0 && hasLegacyKeys {
+		return nil, fmt.Errorf("%w for %s", ErrLegacyKeys, adscertCallSign)
+	} else if len(privateKeys) == 0 {
+		return nil, fmt.Errorf("%w for %s", ErrNoActiveKeys, adscertCallSign)
+	}
+	return privateKeys, nil
+}
+
+func decryptPrivateKey(k *AdsCertKeyConfig, keyEncrypter keysecurity.KeyEncrypter) (string, error) {
+	if k.EncryptedPrivateKey == "" {
+		return "", errors.New("key has no encrypted private key")
+	}
+	privateKeyBytes, err := keyEncrypter.DecryptKeyFromBase64Ciphertext(k.KeyEncryptionKeyURI, k.EncryptedPrivateKey, k.PublicKeyBase64)
+	if err != nil {
+		return "", err
+	}
+	defer clearKey(privateKeyBytes)
+
+	if len(privateKeyBytes) != 32 {
+		return "", fmt.Errorf("wrong private key size: %d != 32", len(privateKeyBytes))
+	}
+
+	publicKeyBytes, err := curve25519.X25519(privateKeyBytes, curve25519.Basepoint)
+	if err != nil {
+		return "", err
+	}
+	wantPublicKeyBytes, err := base64.RawURLEncoding.DecodeString(k.PublicKeyBase64)
+	if err != nil {
+		return "", err
+	}
+	if subtle.ConstantTimeCompare(publicKeyBytes, wantPublicKeyBytes) != 1 {
+		return "", errors.New("private key does not match public key")
+	}
+
+	return encodeKeyBase64(privateKeyBytes), nil
+}
@@ -0,0 +1,100 @@
+package keyring
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/IABTechLab/adscert/pkg/adscert/keysecurity"
+	"github.com/google/tink/go/core/registry"
+)
+
+const insecurePlaintextURI = "insecure-plaintext-kms://"
+
+func TestDecryptActivePrivateKeys(t *testing.T) {
+	registry.RegisterKMSClient(keysecurity.NewInsecurePlaintextClient())
+	keyEncrypter, err := keysecurity.NewKeyEncrypterTinkAead()
+	if err != nil {
+		t.Fatalf("NewKeyEncrypterTinkAead() unexpected error: %v", err)
+	}
+	keyGenerator := NewKeyGenerator(keyEncrypter, insecurePlaintextURI)
+
+	newKey := func(status string) *AdsCertKeyConfig {
+		k := &AdsCertKeyConfig{Status: status}
+		if err := keyGenerator.GenerateKeysForConfig(k); err != nil {
+			t.Fatalf("GenerateKeysForConfig() unexpected error: %v", err)
+		}
+		return k
+	}
+
+	configList := &AdsCertCallSignConfigList{}
+	realm := configList.GetCallSignRealm("example.com", "delivery")
+	realm.Keys = []*AdsCertKeyConfig{
+		newKey(KeyStatusPrimary),
+		newKey(KeyStatusSecondary),
+		newKey(KeyStatusCreated),
+		newKey(KeyStatusArchived),
+	}
+	configList.GetCallSignRealm("inactive.com", "delivery").Keys = []*AdsCertKeyConfig{newKey(KeyStatusArchived)}
+
+	privateKeys, err := DecryptActivePrivateKeys(configList, "example.com", keyEncrypter)
+	if err != nil {
+		t.Fatalf("DecryptActivePrivateKeys() unexpected error: %v", err)
+	}
+	if len(privateKeys) != 2 {
+		t.Errorf("DecryptActivePrivateKeys() got %d keys, want 2", len(privateKeys))
+	}
+
+	if _, err := DecryptActivePrivateKeys(configList, "inactive.com", keyEncrypter); !errors.Is(err, ErrNoActiveKeys) {
+		t.Errorf("DecryptActivePrivateKeys() with no active keys: got error %v, want %v", err, ErrNoActiveKeys)
+	}
+
+	// Keys written before statuses and encrypted private keys were tracked
+	// can't be loaded, which is reported rather than treated as no keys.
+	configList.GetCallSignRealm("legacy.com", "delivery").Keys = []*AdsCertKeyConfig{{PublicKeyBase64: realm.Keys[0].PublicKeyBase64}}
+	if _, err := DecryptActivePrivateKeys(configList, "legacy.com", keyEncrypter); !errors.Is(err, ErrLegacyKeys) {
+		t.Errorf("DecryptActivePrivateKeys() with legacy keys: got error %v, want %v", err, ErrLegacyKeys)
+	}
+
+	// A private key which doesn't belong to the recorded public key is rejected.
+	realm.Keys[0].PublicKeyBase64 = realm.Keys[1].PublicKeyBase64
+	if _, err := DecryptActivePrivateKeys(configList, "example.com", keyEncrypter); err == nil {
+		t.Errorf("DecryptActivePrivateKeys() with mismatched public key: got no error")
+	}
+}
+
+func TestGetPublishablePublicKeys(t *testing.T) {
+	configList := &AdsCertCallSignConfigList{}
+	configList.GetCallSignRealm("example.com", "delivery").Keys = []*AdsCertKeyConfig{
+		{KeyID: "created", PublicKeyBase64: "created", Status: KeyStatusCreated},
+		{KeyID: "archived", PublicKeyBase64: "archived", Status: KeyStatusArchived},
+		{KeyID: "secondary", PublicKeyBase64: "secondary", Status: KeyStatusSecondary},
+		{KeyID: "primary", PublicKeyBase64: "primary", Status: KeyStatusPrimary},
+		{KeyID: "legacy", PublicKeyBase64: "legacy"},
+	}
+	configList.GetCallSignRealm("example.com", "other").Keys = []*AdsCertKeyConfig{
+		{KeyID: "activated", PublicKeyBase64: "activated", Status: KeyStatusActivated},
+		{KeyID: "duplicate", PublicKeyBase64: "primary", Status: KeyStatusPrimary},
+	}
+	configList.GetCallSignRealm("other.com", "delivery").Keys = []*AdsCertKeyConfig{
+		{KeyID: "unrelated", PublicKeyBase64: "unrelated", Status: KeyStatusPrimary},
+	}
+
+	got := GetPublishablePublicKeys(configList, "example.com")
+	want := []string{"primary", "secondary", "activated", "created", "legacy"}
+	if len(got) != len(want) {
+		t.Fatalf("GetPublishablePublicKeys(): got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("GetPublishablePublicKeys(): got %v, want %v", got, want)
+			break
+		}
+	}
+
+	if got, ok := GetPrimaryPublicKey(configList, "example.com"); !ok || got != "primary" {
+		t.Errorf("GetPrimaryPublicKey(): got %q, %v, want %q, true", got, ok, "primary")
+	}
+	if got, ok := GetPrimaryPublicKey(configList, "missing.com"); ok {
+		t.Errorf("GetPrimaryPublicKey() for missing callsign: got %q, want none", got)
+	}
+}
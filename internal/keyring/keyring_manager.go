@@ -1,8 +1,11 @@
 package keyring
 
 import (
+	"context"
+	"fmt"
 	"time"
 
+	"github.com/IABTechLab/adscert/pkg/adscert/logger"
 	"github.com/benbjohnson/clock"
 )
 
@@ -10,13 +13,19 @@ type KeyringManager struct {
 	configList   *AdsCertCallSignConfigList
 	clock        clock.Clock
 	keyGenerator KeyGenerator
+	policy       KeyLifecyclePolicy
 }
 
 func NewKeyringManager(configList *AdsCertCallSignConfigList, clock clock.Clock, keyGenerator KeyGenerator) *KeyringManager {
+	return NewKeyringManagerWithPolicy(configList, clock, keyGenerator, DefaultKeyLifecyclePolicy)
+}
+
+func NewKeyringManagerWithPolicy(configList *AdsCertCallSignConfigList, clock clock.Clock, keyGenerator KeyGenerator, policy KeyLifecyclePolicy) *KeyringManager {
 	return &KeyringManager{
 		configList:   configList,
 		clock:        clock,
 		keyGenerator: keyGenerator,
+		policy:       policy,
 	}
 }
 
@@ -35,8 +44,138 @@ func (m *KeyringManager) AssignKeys() error {
 	return nil
 }
 
+// Rotate advances the keys of every realm through their lifecycle according
+// to the dwell times of the policy:
+//   - legacy keys, written without a status or an encrypted private key, are
+//     archived since they can't be used for signing,
+//   - created keys are activated,
+//   - a successor key is created ahead of time so that it has dwelled long
+//     enough as activated by the time the primary key is due for rotation,
+//   - an activated key is promoted to primary when the realm has none, or
+//     when the primary key is due, in which case the old primary is demoted
+//     to secondary,
+//   - secondary keys are archived.
+//
+// A primary key is never demoted unless a successor is promoted in its place.
+func (m *KeyringManager) Rotate() error {
+	now := m.clock.Now().UTC()
+	actionTimestamp := now.Format(time.RFC3339)
+
+	for _, r := range m.configList.GetAllCallSignRealms() {
+		if err := m.rotateRealm(r, now, actionTimestamp); err != nil {
+			return fmt.Errorf("unable to rotate realm %s: %v", r.Realm, err)
+		}
+	}
+	return nil
+}
+
+func (m *KeyringManager) rotateRealm(r *AdsCertRealm, now time.Time, actionTimestamp string) error {
+	for _, k := range r.Keys {
+		if k.isLegacyKey() {
+			if err := transitionKey(r, k, KeyStatusArchived, actionTimestamp); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, k := range keysWithStatus(r, KeyStatusCreated) {
+		if dwellElapsed(k.TimestampCreated, m.policy.CreatedDwellTime, now) {
+			if err := transitionKey(r, k, KeyStatusActivated, actionTimestamp); err != nil {
+				return err
+			}
+		}
+	}
+
+	primaries := keysWithStatus(r, KeyStatusPrimary)
+	pending := len(keysWithStatus(r, KeyStatusCreated)) + len(keysWithStatus(r, KeyStatusActivated))
+
+	// Create a successor (or the first key) early enough for it to be
+	// activated and published before it's needed.
+	successorLeadTime := m.policy.CreatedDwellTime + m.policy.ActivatedDwellTime
+	needsSuccessor := len(primaries) == 0
+	for _, k := range primaries {
+		needsSuccessor = needsSuccessor || dwellElapsed(k.TimestampPrimaried, m.policy.PrimaryDwellTime-successorLeadTime, now)
+	}
+	if needsSuccessor && pending == 0 {
+		keyConfig, err := m.makeNewKey(actionTimestamp)
+		if err != nil {
+			return err
+		}
+		r.Keys = append(r.Keys, keyConfig)
+	}
+
+	primaryDue := len(primaries) == 0
+	for _, k := range primaries {
+		primaryDue = primaryDue || dwellElapsed(k.TimestampPrimaried, m.policy.PrimaryDwellTime, now)
+	}
+	if primaryDue {
+		for _, k := range keysWithStatus(r, KeyStatusActivated) {
+			if !dwellElapsed(k.TimestampActivated, m.policy.ActivatedDwellTime, now) {
+				continue
+			}
+			if err := transitionKey(r, k, KeyStatusPrimary, actionTimestamp); err != nil {
+				return err
+			}
+			for _, old := range primaries {
+				if err := transitionKey(r, old, KeyStatusSecondary, actionTimestamp); err != nil {
+					return err
+				}
+			}
+			break
+		}
+	}
+
+	for _, k := range keysWithStatus(r, KeyStatusSecondary) {
+		if dwellElapsed(k.TimestampSecondaried, m.policy.SecondaryDwellTime, now) {
+			if err := transitionKey(r, k, KeyStatusArchived, actionTimestamp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// TransitionKey moves a single key to a new status, for manual intervention
+// such as an emergency rotation.  Transitions outside the lifecycle order, and
+// demoting the last primary key of a realm, are refused.
+func (m *KeyringManager) TransitionKey(adscertCallSign string, realm string, keyID string, status string) error {
+	r, ok := m.configList.FindCallSignRealm(adscertCallSign, realm)
+	if !ok {
+		return fmt.Errorf("%w: %s/%s", ErrRealmNotFound, adscertCallSign, realm)
+	}
+	for _, k := range r.Keys {
+		if k.KeyID == keyID {
+			return transitionKey(r, k, status, m.clock.Now().UTC().Format(time.RFC3339))
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrKeyNotFound, keyID)
+}
+
+// RunRotationScheduler calls Rotate every interval until ctx is done, passing
+// the keyring to persist after each rotation.
+func (m *KeyringManager) RunRotationScheduler(ctx context.Context, interval time.Duration, persist func(*AdsCertCallSignConfigList) error) error {
+	ticker := m.clock.Ticker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		if err := m.Rotate(); err != nil {
+			logger.Warningf("key rotation failed: %v", err)
+			continue
+		}
+		if err := persist(m.configList); err != nil {
+			return fmt.Errorf("unable to persist keyring: %w", err)
+		}
+	}
+}
+
 func (m *KeyringManager) makeNewKey(actionTimestamp string) (*AdsCertKeyConfig, error) {
 	keyConfig := &AdsCertKeyConfig{
+		Status:           KeyStatusCreated,
 		TimestampCreated: actionTimestamp,
 	}
 	if err := m.keyGenerator.GenerateKeysForConfig(keyConfig); err != nil {
@@ -0,0 +1,198 @@
+package keyring
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/benbjohnson/clock"
+)
+
+func TestKeyringManager_Rotate(t *testing.T) {
+	mockClock := clock.NewMock()
+	mockClock.Set(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	configList := &AdsCertCallSignConfigList{}
+	realm := configList.GetCallSignRealm("example.com", "delivery")
+	manager := NewKeyringManager(configList, mockClock, NewKeyGenerator(nil, ""))
+
+	var firstPrimary time.Time
+	promotions := 0
+	for day := 0; day < 365; day++ {
+		if err := manager.Rotate(); err != nil {
+			t.Fatalf("Rotate() day %d: unexpected error: %v", day, err)
+		}
+
+		primaries := countKeysWithStatus(realm, KeyStatusPrimary)
+		if !firstPrimary.IsZero() && primaries != 1 {
+			t.Fatalf("Rotate() day %d: got %d primary keys, want 1", day, primaries)
+		}
+		if primaries == 1 && firstPrimary.IsZero() {
+			firstPrimary = mockClock.Now()
+		}
+		for _, k := range realm.Keys {
+			if k.TimestampPrimaried == mockClock.Now().UTC().Format(time.RFC3339) {
+				promotions++
+			}
+		}
+
+		mockClock.Add(24 * time.Hour)
+	}
+
+	// The first key is published and promoted after its created and activated
+	// dwell times, then rotated every 90 days.
+	if want := time.Date(2022, 1, 9, 0, 0, 0, 0, time.UTC); !firstPrimary.Equal(want) {
+		t.Errorf("Rotate() first primary: got %v, want %v", firstPrimary, want)
+	}
+	if promotions != 4 {
+		t.Errorf("Rotate() promotions in a year: got %d, want 4", promotions)
+	}
+	if got := countKeysWithStatus(realm, KeyStatusArchived); got != 3 {
+		t.Errorf("Rotate() archived keys: got %d, want 3", got)
+	}
+}
+
+func TestKeyringManager_Rotate_LegacyKeys(t *testing.T) {
+	mockClock := clock.NewMock()
+	mockClock.Set(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	configList := &AdsCertCallSignConfigList{}
+	realm := configList.GetCallSignRealm("example.com", "delivery")
+	legacy := &AdsCertKeyConfig{KeyID: "legacy", PublicKeyBase64: "legacy"}
+	unstatused := &AdsCertKeyConfig{KeyID: "unstatused", PublicKeyBase64: "unstatused", EncryptedPrivateKey: "encrypted"}
+	realm.Keys = []*AdsCertKeyConfig{legacy, unstatused}
+	manager := NewKeyringManager(configList, mockClock, NewKeyGenerator(nil, ""))
+
+	if err := manager.Rotate(); err != nil {
+		t.Fatalf("Rotate() unexpected error: %v", err)
+	}
+
+	// A legacy key without a private key is archived, while a key which only
+	// lacks a status is activated like any other created key.
+	if got := legacy.GetStatus(); got != KeyStatusArchived {
+		t.Errorf("Rotate() legacy key status: got %s, want %s", got, KeyStatusArchived)
+	}
+	if got := unstatused.GetStatus(); got != KeyStatusActivated {
+		t.Errorf("Rotate() key without status: got %s, want %s", got, KeyStatusActivated)
+	}
+	if !unstatused.IsActive() {
+		t.Errorf("IsActive() key without status after activation: got false, want true")
+	}
+}
+
+func TestKeyringManager_TransitionKey(t *testing.T) {
+	mockClock := clock.NewMock()
+	configList := &AdsCertCallSignConfigList{}
+	realm := configList.GetCallSignRealm("example.com", "delivery")
+	realm.Keys = []*AdsCertKeyConfig{
+		{KeyID: "primary", Status: KeyStatusPrimary},
+		{KeyID: "activated", Status: KeyStatusActivated},
+	}
+	manager := NewKeyringManager(configList, mockClock, NewKeyGenerator(nil, ""))
+
+	testCases := []struct {
+		desc   string
+		keyID  string
+		status string
+		want   error
+	}{
+		{desc: "demote only primary", keyID: "primary", status: KeyStatusSecondary, want: ErrNoPrimaryKey},
+		{desc: "skip lifecycle step", keyID: "primary", status: KeyStatusArchived, want: ErrInvalidKeyTransition},
+		{desc: "unknown key", keyID: "missing", status: KeyStatusPrimary, want: ErrKeyNotFound},
+		{desc: "promote activated key", keyID: "activated", status: KeyStatusPrimary},
+		{desc: "demote old primary once replaced", keyID: "primary", status: KeyStatusSecondary},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			err := manager.TransitionKey("example.com", "delivery", tC.keyID, tC.status)
+			if !errors.Is(err, tC.want) {
+				t.Errorf("TransitionKey() %s: got error %v, want %v", tC.desc, err, tC.want)
+			}
+		})
+	}
+
+	// A mistyped Call Sign or realm must not be added to the keyring.
+	for _, name := range [][2]string{{"typo.com", "delivery"}, {"example.com", "typo"}} {
+		if err := manager.TransitionKey(name[0], name[1], "primary", KeyStatusSecondary); !errors.Is(err, ErrRealmNotFound) {
+			t.Errorf("TransitionKey() %s/%s: got error %v, want %v", name[0], name[1], err, ErrRealmNotFound)
+		}
+	}
+	if len(configList.Domains) != 1 || len(configList.Domains[0].Realms) != 1 {
+		t.Errorf("TransitionKey() unknown realms: got keyring %+v, want it unchanged", configList.Domains)
+	}
+}
+
+func TestKeyringManager_RunRotationScheduler(t *testing.T) {
+	mockClock := clock.NewMock()
+	configList := &AdsCertCallSignConfigList{}
+	configList.GetCallSignRealm("example.com", "delivery")
+	manager := NewKeyringManager(configList, mockClock, NewKeyGenerator(nil, ""))
+
+	persisted := make(chan int)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- manager.RunRotationScheduler(ctx, time.Hour, func(c *AdsCertCallSignConfigList) error {
+			persisted <- len(c.GetCallSignRealm("example.com", "delivery").Keys)
+			return nil
+		})
+	}()
+
+	// The ticker may not exist yet when the clock first advances, so keep
+	// advancing it until the scheduler rotates.
+	var keys int
+	for rotated := false; !rotated; {
+		mockClock.Add(time.Hour)
+		select {
+		case keys = <-persisted:
+			rotated = true
+		case err := <-done:
+			t.Fatalf("RunRotationScheduler() returned early: %v", err)
+		default:
+		}
+	}
+	if keys != 1 {
+		t.Errorf("RunRotationScheduler() first rotation: persisted %d keys, want 1", keys)
+	}
+
+	cancel()
+	for {
+		select {
+		case <-persisted:
+			continue
+		case err := <-done:
+			if !errors.Is(err, context.Canceled) {
+				t.Errorf("RunRotationScheduler() after cancel: got error %v, want %v", err, context.Canceled)
+			}
+		}
+		break
+	}
+}
+
+func TestKeyringManager_RunRotationScheduler_PersistError(t *testing.T) {
+	mockClock := clock.NewMock()
+	configList := &AdsCertCallSignConfigList{}
+	configList.GetCallSignRealm("example.com", "delivery")
+	manager := NewKeyringManager(configList, mockClock, NewKeyGenerator(nil, ""))
+
+	persistErr := errors.New("disk full")
+	done := make(chan error)
+	go func() {
+		done <- manager.RunRotationScheduler(context.Background(), time.Hour, func(*AdsCertCallSignConfigList) error {
+			return persistErr
+		})
+	}()
+
+	for {
+		mockClock.Add(time.Hour)
+		select {
+		case err := <-done:
+			if !errors.Is(err, persistErr) {
+				t.Errorf("RunRotationScheduler() with failing persist: got error %v, want %v", err, persistErr)
+			}
+			return
+		default:
+		}
+	}
+}
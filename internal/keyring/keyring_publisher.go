@@ -0,0 +1,60 @@
+package keyring
+
+// publishOrder ranks key statuses for listing in the ads.cert keys record.
+// Counterparties treat the first key listed as current, so the primary key
+// comes first and pending keys come last.
+var publishOrder = map[string]int{
+	KeyStatusPrimary:   0,
+	KeyStatusSecondary: 1,
+	KeyStatusActivated: 2,
+	KeyStatusCreated:   3,
+}
+
+// GetPublishablePublicKeys returns the base64 encoded public keys which should
+// be published for an ads.cert Call Sign, across all of its realms, with the
+// primary key first.  Duplicate keys are listed once.
+func GetPublishablePublicKeys(configList *AdsCertCallSignConfigList, adscertCallSign string) []string {
+	var byRank [4][]string
+	seen := map[string]bool{}
+
+	for _, c := range configList.Domains {
+		if c.Domain != adscertCallSign {
+			continue
+		}
+		for _, r := range c.Realms {
+			for _, k := range r.Keys {
+				if !k.IsPublishable() || k.PublicKeyBase64 == "" || seen[k.PublicKeyBase64] {
+					continue
+				}
+				seen[k.PublicKeyBase64] = true
+				rank := publishOrder[k.GetStatus()]
+				byRank[rank] = append(byRank[rank], k.PublicKeyBase64)
+			}
+		}
+	}
+
+	var result []string
+	for _, keys := range byRank {
+		result = append(result, keys...)
+	}
+	return result
+}
+
+// GetPrimaryPublicKey returns the base64 encoded public key of the primary key
+// held for an ads.cert Call Sign.  When several realms have a primary key, the
+// first one found is returned.
+func GetPrimaryPublicKey(configList *AdsCertCallSignConfigList, adscertCallSign string) (string, bool) {
+	for _, c := range configList.Domains {
+		if c.Domain != adscertCallSign {
+			continue
+		}
+		for _, r := range c.Realms {
+			for _, k := range r.Keys {
+				if k.GetStatus() == KeyStatusPrimary && k.PublicKeyBase64 != "" {
+					return k.PublicKeyBase64, true
+				}
+			}
+		}
+	}
+	return "", false
+}
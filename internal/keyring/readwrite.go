@@ -5,10 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 )
 
 func CreateNewKeyringFile() (*AdsCertCallSignConfigList, error) {
-	f, err := os.Open("adscertkeyring.json")
+	f, err := os.Open(DefaultKeyringFile)
 	if err != nil {
 		if !os.IsNotExist(err) {
 			return nil, err
@@ -28,7 +29,7 @@ func CreateNewKeyringFile() (*AdsCertCallSignConfigList, error) {
 		return nil, err
 	}
 
-	if err = os.WriteFile("adscertkeyring.json", data, 0644); err != nil {
+	if err = os.WriteFile(DefaultKeyringFile, data, 0644); err != nil {
 		return nil, err
 	}
 
@@ -41,7 +42,7 @@ func OverwriteKeyringFile(config *AdsCertCallSignConfigList) error {
 		return err
 	}
 
-	f, err := os.OpenFile("adscertkeyring.json", os.O_WRONLY|os.O_TRUNC, 0644)
+	f, err := os.OpenFile(DefaultKeyringFile, os.O_WRONLY|os.O_TRUNC, 0644)
 	if err != nil {
 		return err
 	}
@@ -53,8 +54,43 @@ func OverwriteKeyringFile(config *AdsCertCallSignConfigList) error {
 	return err
 }
 
+// WriteKeyringFileToPath replaces the keyring file at path.  The keyring is
+// written to a temporary file renamed into place, so that readers never see a
+// partially written keyring.
+func WriteKeyringFileToPath(path string, config *AdsCertCallSignConfigList) error {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(data)
+	if err1 := f.Close(); err1 != nil && err == nil {
+		err = err1
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// DefaultKeyringFile is the keyring file name used when no path is given.
+const DefaultKeyringFile = "adscertkeyring.json"
+
 func ReadKeyringFile() (*AdsCertCallSignConfigList, error) {
-	data, err := os.ReadFile("adscertkeyring.json")
+	return ReadKeyringFileFromPath(DefaultKeyringFile)
+}
+
+func ReadKeyringFileFromPath(path string) (*AdsCertCallSignConfigList, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,32 @@
+package keyring
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWriteKeyringFileToPath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, DefaultKeyringFile)
+	configList := &AdsCertCallSignConfigList{}
+	configList.GetCallSignRealm("example.com", "delivery").Keys = []*AdsCertKeyConfig{{KeyID: "key1", Status: KeyStatusPrimary}}
+
+	for i := 0; i < 2; i++ {
+		if err := WriteKeyringFileToPath(path, configList); err != nil {
+			t.Fatalf("WriteKeyringFileToPath() unexpected error: %v", err)
+		}
+	}
+
+	got, err := ReadKeyringFileFromPath(path)
+	if err != nil {
+		t.Fatalf("ReadKeyringFileFromPath() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, configList) {
+		t.Errorf("ReadKeyringFileFromPath() after write: got %+v, want %+v", got, configList)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("WriteKeyringFileToPath() left %d files, want only the keyring", len(entries))
+	}
+}
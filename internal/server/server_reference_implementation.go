@@ -6,35 +6,323 @@ import (
 	"fmt"
 	"net"
 	"net/http"
-	"time"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 
+	"github.com/IABTechLab/adscert/internal/formats"
+	"github.com/IABTechLab/adscert/internal/keyring"
 	"github.com/IABTechLab/adscert/pkg/adscert/api"
 	"github.com/IABTechLab/adscert/pkg/adscert/discovery"
+	"github.com/IABTechLab/adscert/pkg/adscert/keysecurity"
+	"github.com/IABTechLab/adscert/pkg/adscert/logger"
 	"github.com/IABTechLab/adscert/pkg/adscert/metrics"
 	"github.com/IABTechLab/adscert/pkg/adscert/server"
 	"github.com/IABTechLab/adscert/pkg/adscert/signatory"
 	"github.com/benbjohnson/clock"
+	"github.com/google/tink/go/core/registry"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
-func SetUpAdsCertSignatoryServer(grpcServer *grpc.Server, adscertCallSign string, domainCheckInterval time.Duration, domainRenewalInterval time.Duration, privateKeys []string) {
-	signatoryApi := signatory.NewLocalAuthenticatedConnectionsSignatory(
+func SetUpAdsCertSignatoryServer(grpcServer *grpc.Server, adscertCallSign string, privateKeys []string, dnsResolver discovery.DNSResolver, domainStore discovery.DomainStore, options *signatory.LocalAuthenticatedConnectionsSignatoryOptions) *signatory.LocalAuthenticatedConnectionsSignatory {
+	signatoryApi := signatory.NewLocalAuthenticatedConnectionsSignatoryWithOptions(
 		adscertCallSign,
 		crypto_rand.Reader,
 		clock.New(),
-		discovery.NewDefaultDnsResolver(),
-		discovery.NewDefaultDomainStore(),
-		domainCheckInterval,
-		domainRenewalInterval,
-		privateKeys)
+		dnsResolver,
+		domainStore,
+		privateKeys,
+		options)
 
 	handler := &server.AdsCertSignatoryServer{
 		SignatoryAPI: signatoryApi,
 	}
 	api.RegisterAdsCertSignatoryServer(grpcServer, handler)
 	reflection.Register(grpcServer)
+	return signatoryApi
+}
+
+// SetUpAdsCertSignatoryAdminServer registers the administrative service for
+// signatoryApi, which lets any client able to reach grpcServer deactivate it.
+func SetUpAdsCertSignatoryAdminServer(grpcServer *grpc.Server, signatoryApi *signatory.LocalAuthenticatedConnectionsSignatory) {
+	api.RegisterAdsCertSignatoryAdminServer(grpcServer, &server.AdsCertSignatoryAdminServer{
+		SignatoryAPI: signatoryApi,
+	})
+}
+
+// StartServingAdminRequests serves the administrative service for
+// signatoryApi on its own gRPC server listening at address, such as
+// "localhost:3002".  The service is unauthenticated, so it is kept off the
+// signing server and should only listen where trusted clients can reach it.
+func StartServingAdminRequests(signatoryApi *signatory.LocalAuthenticatedConnectionsSignatory, address string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("error listening on TCP for admin gRPC server: %v", err)
+	}
+	return serveAdminRequests(listener, signatoryApi)
+}
+
+func serveAdminRequests(listener net.Listener, signatoryApi *signatory.LocalAuthenticatedConnectionsSignatory) error {
+	grpcServer := grpc.NewServer()
+	SetUpAdsCertSignatoryAdminServer(grpcServer, signatoryApi)
+	reflection.Register(grpcServer)
+
+	// Start server and block indefinitely.
+	if err := grpcServer.Serve(listener); err != nil {
+		return fmt.Errorf("error serving admin gRPC: %v", err)
+	}
+	return nil
+}
+
+// LoadDeactivationFile reads deactivation settings from a JSON file, such as
+// {"origins": ["example.com"], "counterparties": ["partner.com"]}.  A missing
+// file deactivates nothing.
+func LoadDeactivationFile(path string) (*api.DeactivationSettings, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &api.DeactivationSettings{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to read deactivation file: %v", err)
+	}
+	settings := &api.DeactivationSettings{}
+	if err := protojson.Unmarshal(data, settings); err != nil {
+		return nil, fmt.Errorf("unable to parse deactivation file: %v", err)
+	}
+	return settings, nil
+}
+
+// ReloadDeactivationFileOnSignal reloads the deactivation file at path into
+// signatoryApi each time the process receives SIGHUP.  See
+// reloadDeactivationFile.
+func ReloadDeactivationFileOnSignal(path string, signatoryApi *signatory.LocalAuthenticatedConnectionsSignatory) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	go func() {
+		loaded := signatoryApi.GetDeactivation()
+		for range signals {
+			settings, err := reloadDeactivationFile(path, signatoryApi, loaded)
+			if err != nil {
+				logger.Errorf("Keeping current deactivation settings: %v", err)
+				continue
+			}
+			loaded = settings
+			logger.Infof("Reloaded deactivation settings from %s", path)
+		}
+	}()
+}
+
+// reloadDeactivationFile replaces the deactivation settings of signatoryApi by
+// those in the file at path, returning them.  The file is the source of truth:
+// settings changed through the admin service since the file was last loaded
+// (loaded) are overwritten, with a warning.  Unlike at startup, a missing file
+// is an error, as is one which fails to load, and either leaves the current
+// settings in place.
+func reloadDeactivationFile(path string, signatoryApi *signatory.LocalAuthenticatedConnectionsSignatory, loaded *api.DeactivationSettings) (*api.DeactivationSettings, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("unable to read deactivation file: %v", err)
+	}
+	settings, err := LoadDeactivationFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if current := signatoryApi.GetDeactivation(); !proto.Equal(current, loaded) {
+		logger.Warningf("Replacing deactivation settings changed through the admin service with those from %s", path)
+	}
+	signatoryApi.SetDeactivation(settings)
+	return settings, nil
+}
+
+// NewDNSResolver returns the DNS resolver used for counterparty discovery.  When
+// DNSSEC is enabled, trust anchors are read from trustAnchorFile (one DS record
+// per line) or default to the root zone trust anchor.
+func NewDNSResolver(enableDNSSEC bool, nameserver string, trustAnchorFile string) (discovery.DNSResolver, error) {
+	if !enableDNSSEC {
+		return discovery.NewDefaultDnsResolver(), nil
+	}
+
+	trustAnchors := []string{discovery.RootTrustAnchor}
+	if trustAnchorFile != "" {
+		data, err := os.ReadFile(trustAnchorFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read trust anchor file: %v", err)
+		}
+		trustAnchors = nil
+		for _, line := range strings.Split(string(data), "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" && !strings.HasPrefix(line, ";") {
+				trustAnchors = append(trustAnchors, line)
+			}
+		}
+	}
+	return discovery.NewDNSSECResolver(nameserver, trustAnchors)
+}
+
+// NewDomainStore returns the store used to hold counterparty domain records.
+// When path is set, records are persisted to a database file at that path and
+// reloaded on startup; otherwise they are held in memory only.
+func NewDomainStore(path string) (discovery.DomainStore, error) {
+	if path == "" {
+		return discovery.NewDefaultDomainStore(), nil
+	}
+	return discovery.NewBoltDomainStore(path)
+}
+
+// SetUpMetrics configures the optional counterparty labels on signatory
+// metrics and exports gauges for the domains held by domainStore.
+func SetUpMetrics(labelOptions metrics.LabelOptions, domainStore discovery.DomainStore) {
+	metrics.SetLabelOptions(labelOptions)
+	discovery.RegisterDomainStoreMetrics(domainStore)
+}
+
+// NewAuditSink returns the sink for signatory audit events: a JSONL file at
+// path, rotated at maxBytes and keeping maxBackups old files, written through a
+// buffer of bufferSize events.  When path is empty, events aren't audited.
+func NewAuditSink(path string, maxBytes int64, maxBackups int, bufferSize int) (signatory.AuditSink, error) {
+	if path == "" {
+		return nil, nil
+	}
+	fileSink, err := signatory.NewRotatingFileAuditSink(path, maxBytes, maxBackups)
+	if err != nil {
+		return nil, err
+	}
+	return signatory.NewAsyncAuditSink(fileSink, bufferSize), nil
+}
+
+// NewAuditSampler returns the sampler for signatory audit events from rules in
+// the format accepted by signatory.ParseAuditSamplingRules.  Events matching no
+// rule are always audited, and an empty spec audits everything.
+func NewAuditSampler(spec string) (signatory.AuditSampler, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	rules, err := signatory.ParseAuditSamplingRules(spec)
+	if err != nil {
+		return nil, err
+	}
+	return signatory.NewAuditSampler(rules, 1), nil
+}
+
+// LoadAuditRedactionKey reads the key used to redact signatures in audit
+// events from the file at path, ignoring surrounding whitespace.  When path is
+// empty, signatures aren't redacted.
+func LoadAuditRedactionKey(path string) ([]byte, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read audit redaction key: %v", err)
+	}
+	key := []byte(strings.TrimSpace(string(data)))
+	if len(key) == 0 {
+		return nil, fmt.Errorf("audit redaction key file %s is empty", path)
+	}
+	return key, nil
+}
+
+// ParseOriginKeys parses "callsign=base64key" entries into private keys per
+// origin callsign.  A callsign may appear more than once to supply several keys.
+func ParseOriginKeys(entries []string) (map[string][]string, error) {
+	originKeys := map[string][]string{}
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("malformed origin key %q, expected callsign=base64key", entry)
+		}
+		originKeys[parts[0]] = append(originKeys[parts[0]], parts[1])
+	}
+	return originKeys, nil
+}
+
+// RegisterKeyEncryptionClients registers the Tink KMS clients used to encrypt
+// and decrypt keyring private keys: the local key encryption client when
+// localKeysetFile is set, and the insecure plaintext client only when
+// allowInsecurePlaintext is true.
+func RegisterKeyEncryptionClients(localKeysetFile string, allowInsecurePlaintext bool) error {
+	if localKeysetFile != "" {
+		f, err := os.Open(localKeysetFile)
+		if err != nil {
+			return fmt.Errorf("unable to open key encryption keyset: %v", err)
+		}
+		defer f.Close()
+		kmsClient, err := keysecurity.NewLocalKeyEncryptionClient(f)
+		if err != nil {
+			return fmt.Errorf("unable to read key encryption keyset: %v", err)
+		}
+		registry.RegisterKMSClient(kmsClient)
+	}
+	if allowInsecurePlaintext {
+		registry.RegisterKMSClient(keysecurity.NewInsecurePlaintextClient())
+	}
+	return nil
+}
+
+// LoadKeyringPrivateKeys reads the keyring at path and decrypts the active
+// private keys of every ads.cert Call Sign in it, keyed by callsign, along with
+// the role of each key: keys with primary status sign, the rest only verify.  Key
+// encryption keys are resolved through the clients registered by
+// RegisterKeyEncryptionClients.
+func LoadKeyringPrivateKeys(path string, localKeysetFile string, allowInsecurePlaintext bool) (map[string][]string, map[string]discovery.PrivateKeyRole, error) {
+	if err := RegisterKeyEncryptionClients(localKeysetFile, allowInsecurePlaintext); err != nil {
+		return nil, nil, err
+	}
+
+	configList, err := keyring.ReadKeyringFileFromPath(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read keyring: %v", err)
+	}
+	keyEncrypter, err := keysecurity.NewKeyEncrypterTinkAead()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	privateKeys := map[string][]string{}
+	for _, callsign := range configList.GetCallSigns() {
+		keys, err := keyring.DecryptActivePrivateKeys(configList, callsign, keyEncrypter)
+		if errors.Is(err, keyring.ErrNoActiveKeys) {
+			logger.Warningf("Not loading keys for %s: %v", callsign, err)
+			continue
+		} else if err != nil {
+			return nil, nil, err
+		}
+		privateKeys[callsign] = keys
+	}
+
+	roles := map[string]discovery.PrivateKeyRole{}
+	for _, r := range configList.GetAllCallSignRealms() {
+		for _, k := range r.Keys {
+			if !k.IsActive() || k.PublicKeyBase64 == "" {
+				continue
+			}
+			alias := formats.ExtractKeyAliasFromPublicKeyBase64(k.PublicKeyBase64)
+			if k.GetStatus() == keyring.KeyStatusPrimary {
+				roles[alias] = discovery.PrivateKeyRolePrimary
+			} else if roles[alias] == "" {
+				roles[alias] = discovery.PrivateKeyRoleSecondary
+			}
+		}
+	}
+	return privateKeys, roles, nil
+}
+
+// SplitOriginKeys separates the private keys of the default origin from those
+// of any other callsigns, which become additional origins.
+func SplitOriginKeys(origin string, keysByCallsign map[string][]string) ([]string, map[string][]string) {
+	var originKeys []string
+	additionalOrigins := map[string][]string{}
+	for callsign, keys := range keysByCallsign {
+		if callsign == origin {
+			originKeys = append(originKeys, keys...)
+		} else {
+			additionalOrigins[callsign] = append(additionalOrigins[callsign], keys...)
+		}
+	}
+	return originKeys, additionalOrigins
 }
 
 func StartServingRequests(grpcServer *grpc.Server, serverPort int) error {
@@ -0,0 +1,196 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IABTechLab/adscert/pkg/adscert/api"
+	"github.com/IABTechLab/adscert/pkg/adscert/discovery"
+	"github.com/IABTechLab/adscert/pkg/adscert/signatory"
+	"github.com/benbjohnson/clock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestSignatory() *signatory.LocalAuthenticatedConnectionsSignatory {
+	_, privateKey := signatory.GenerateFakeKeyPairFromDomainNameForTesting("signer.com")
+	return signatory.NewLocalAuthenticatedConnectionsSignatoryWithOptions(
+		"signer.com", rand.Reader, clock.New(), discovery.NewSnapshotDNSResolver(nil), discovery.NewDefaultDomainStore(),
+		[]string{base64.RawURLEncoding.EncodeToString(privateKey[:])},
+		&signatory.LocalAuthenticatedConnectionsSignatoryOptions{})
+}
+
+func writeFile(t *testing.T, name string, contents string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("WriteFile(): %v", err)
+	}
+	return path
+}
+
+func TestLoadDeactivationFile(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		contents string
+		missing  bool
+
+		want    *api.DeactivationSettings
+		wantErr bool
+	}{
+		{
+			desc:     "settings",
+			contents: `{"origins": ["example.com"], "counterparties": ["partner.com"]}`,
+			want:     &api.DeactivationSettings{Origins: []string{"example.com"}, Counterparties: []string{"partner.com"}},
+		},
+		{
+			desc:     "all",
+			contents: `{"all": true}`,
+			want:     &api.DeactivationSettings{All: true},
+		},
+		{
+			desc:    "missing",
+			missing: true,
+			want:    &api.DeactivationSettings{},
+		},
+		{
+			desc:     "malformed",
+			contents: `{"origins": "example.com"}`,
+			wantErr:  true,
+		},
+		{
+			desc:     "unknown field",
+			contents: `{"domains": ["example.com"]}`,
+			wantErr:  true,
+		},
+	}
+	for _, tC := range testCases {
+		path := filepath.Join(t.TempDir(), "deactivation.json")
+		if !tC.missing {
+			path = writeFile(t, "deactivation.json", tC.contents)
+		}
+		got, err := LoadDeactivationFile(path)
+		if (err != nil) != tC.wantErr {
+			t.Errorf("LoadDeactivationFile() %s: got error %v, want error %v", tC.desc, err, tC.wantErr)
+			continue
+		}
+		if !tC.wantErr && !proto.Equal(got, tC.want) {
+			t.Errorf("LoadDeactivationFile() %s: got %v, want %v", tC.desc, got, tC.want)
+		}
+	}
+}
+
+func TestReloadDeactivationFile(t *testing.T) {
+	current := &api.DeactivationSettings{Counterparties: []string{"partner.com"}}
+
+	testCases := []struct {
+		desc     string
+		contents string
+		missing  bool
+
+		want    *api.DeactivationSettings
+		wantErr bool
+	}{
+		{
+			desc:     "replaces settings",
+			contents: `{"origins": ["signer.com"]}`,
+			want:     &api.DeactivationSettings{Origins: []string{"signer.com"}},
+		},
+		{
+			desc:    "missing keeps settings",
+			missing: true,
+			want:    current,
+			wantErr: true,
+		},
+		{
+			desc:     "malformed keeps settings",
+			contents: `{`,
+			want:     current,
+			wantErr:  true,
+		},
+	}
+	for _, tC := range testCases {
+		signatoryApi := newTestSignatory()
+		signatoryApi.SetDeactivation(current)
+
+		path := filepath.Join(t.TempDir(), "deactivation.json")
+		if !tC.missing {
+			path = writeFile(t, "deactivation.json", tC.contents)
+		}
+		_, err := reloadDeactivationFile(path, signatoryApi, current)
+		if (err != nil) != tC.wantErr {
+			t.Errorf("reloadDeactivationFile() %s: got error %v, want error %v", tC.desc, err, tC.wantErr)
+		}
+		if got := signatoryApi.GetDeactivation(); !proto.Equal(got, tC.want) {
+			t.Errorf("reloadDeactivationFile() %s: got %v, want %v", tC.desc, got, tC.want)
+		}
+	}
+}
+
+func TestAdminServer(t *testing.T) {
+	signatoryApi := newTestSignatory()
+
+	listener := bufconn.Listen(1 << 20)
+	done := make(chan error, 1)
+	go func() { done <- serveAdminRequests(listener, signatoryApi) }()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.Dial() unexpected error: %v", err)
+	}
+	defer conn.Close()
+	ctx := context.Background()
+
+	// Only the admin service is served.
+	signResponse, err := api.NewAdsCertSignatoryClient(conn).SignAuthenticatedConnection(ctx, &api.AuthenticatedConnectionSignatureRequest{})
+	if err == nil {
+		t.Errorf("SignAuthenticatedConnection(): got %v, want error", signResponse)
+	}
+
+	client := api.NewAdsCertSignatoryAdminClient(conn)
+	settings := &api.DeactivationSettings{Origins: []string{"signer.com"}}
+	setResponse, err := client.SetDeactivation(ctx, &api.SetDeactivationRequest{Settings: settings})
+	if err != nil {
+		t.Fatalf("SetDeactivation(): unexpected error: %v", err)
+	}
+	if !proto.Equal(setResponse.Settings, settings) {
+		t.Errorf("SetDeactivation(): got %v, want %v", setResponse.Settings, settings)
+	}
+	if got := signatoryApi.GetDeactivation(); !proto.Equal(got, settings) {
+		t.Errorf("SetDeactivation(): signatory has %v, want %v", got, settings)
+	}
+
+	getResponse, err := client.GetDeactivation(ctx, &api.GetDeactivationRequest{})
+	if err != nil {
+		t.Fatalf("GetDeactivation(): unexpected error: %v", err)
+	}
+	if !proto.Equal(getResponse.Settings, settings) {
+		t.Errorf("GetDeactivation(): got %v, want %v", getResponse.Settings, settings)
+	}
+
+	listener.Close()
+	if err := <-done; err == nil {
+		t.Errorf("serveAdminRequests(): got nil error after the listener closed, want error")
+	}
+}
+
+func TestStartServingAdminRequests_ListenError(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("net.Listen(): %v", err)
+	}
+	defer listener.Close()
+
+	// The address is already taken, so serving fails instead of blocking.
+	if err := StartServingAdminRequests(newTestSignatory(), listener.Addr().String()); err == nil {
+		t.Errorf("StartServingAdminRequests(): got nil error, want error")
+	}
+}
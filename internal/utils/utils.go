@@ -21,6 +21,15 @@ func GetEnvVarInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func GetEnvVarBool(key string, defaultValue bool) bool {
+	if v, ok := os.LookupEnv(key); ok {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
 func MergeUniques(list []string) []string {
 	uniques := map[string]struct{}{}
 	for _, v := range list {
@@ -32,20 +32,32 @@ const (
 	SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_UNRELATED_SIGNATURE        SignatureDecodeStatus = 6
 	SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_COUNTERPARTY_LOOKUP_ERROR  SignatureDecodeStatus = 7
 	SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_NO_SHARED_SECRET_AVAILABLE SignatureDecodeStatus = 8
+	SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_STALE_SIGNATURE            SignatureDecodeStatus = 9
+	SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_REPLAYED_SIGNATURE         SignatureDecodeStatus = 10
+	SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_KEY_NOT_PUBLISHED          SignatureDecodeStatus = 11
+	SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_WRONG_RECIPIENT            SignatureDecodeStatus = 12
+	SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_REJECTED_SIGNING_STATUS    SignatureDecodeStatus = 13
+	SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_DEACTIVATED                SignatureDecodeStatus = 14
 )
 
 // Enum value maps for SignatureDecodeStatus.
 var (
 	SignatureDecodeStatus_name = map[int32]string{
-		0: "SIGNATURE_DECODE_STATUS_UNDEFINED",
-		1: "SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID",
-		2: "SIGNATURE_DECODE_STATUS_BODY_VALID",
-		3: "SIGNATURE_DECODE_STATUS_INVALID_SIGNATURE",
-		4: "SIGNATURE_DECODE_STATUS_SIGNATURE_NOT_PRESENT",
-		5: "SIGNATURE_DECODE_STATUS_SIGNATURE_MALFORMED",
-		6: "SIGNATURE_DECODE_STATUS_UNRELATED_SIGNATURE",
-		7: "SIGNATURE_DECODE_STATUS_COUNTERPARTY_LOOKUP_ERROR",
-		8: "SIGNATURE_DECODE_STATUS_NO_SHARED_SECRET_AVAILABLE",
+		0:  "SIGNATURE_DECODE_STATUS_UNDEFINED",
+		1:  "SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID",
+		2:  "SIGNATURE_DECODE_STATUS_BODY_VALID",
+		3:  "SIGNATURE_DECODE_STATUS_INVALID_SIGNATURE",
+		4:  "SIGNATURE_DECODE_STATUS_SIGNATURE_NOT_PRESENT",
+		5:  "SIGNATURE_DECODE_STATUS_SIGNATURE_MALFORMED",
+		6:  "SIGNATURE_DECODE_STATUS_UNRELATED_SIGNATURE",
+		7:  "SIGNATURE_DECODE_STATUS_COUNTERPARTY_LOOKUP_ERROR",
+		8:  "SIGNATURE_DECODE_STATUS_NO_SHARED_SECRET_AVAILABLE",
+		9:  "SIGNATURE_DECODE_STATUS_STALE_SIGNATURE",
+		10: "SIGNATURE_DECODE_STATUS_REPLAYED_SIGNATURE",
+		11: "SIGNATURE_DECODE_STATUS_KEY_NOT_PUBLISHED",
+		12: "SIGNATURE_DECODE_STATUS_WRONG_RECIPIENT",
+		13: "SIGNATURE_DECODE_STATUS_REJECTED_SIGNING_STATUS",
+		14: "SIGNATURE_DECODE_STATUS_DEACTIVATED",
 	}
 	SignatureDecodeStatus_value = map[string]int32{
 		"SIGNATURE_DECODE_STATUS_UNDEFINED":                  0,
@@ -57,6 +69,12 @@ var (
 		"SIGNATURE_DECODE_STATUS_UNRELATED_SIGNATURE":        6,
 		"SIGNATURE_DECODE_STATUS_COUNTERPARTY_LOOKUP_ERROR":  7,
 		"SIGNATURE_DECODE_STATUS_NO_SHARED_SECRET_AVAILABLE": 8,
+		"SIGNATURE_DECODE_STATUS_STALE_SIGNATURE":            9,
+		"SIGNATURE_DECODE_STATUS_REPLAYED_SIGNATURE":         10,
+		"SIGNATURE_DECODE_STATUS_KEY_NOT_PUBLISHED":          11,
+		"SIGNATURE_DECODE_STATUS_WRONG_RECIPIENT":            12,
+		"SIGNATURE_DECODE_STATUS_REJECTED_SIGNING_STATUS":    13,
+		"SIGNATURE_DECODE_STATUS_DEACTIVATED":                14,
 	}
 )
 
@@ -376,6 +394,9 @@ type RequestVerificationInfo struct {
 	unknownFields protoimpl.UnknownFields
 
 	SignatureDecodeStatus []SignatureDecodeStatus `protobuf:"varint,1,rep,packed,name=signature_decode_status,json=signatureDecodeStatus,proto3,enum=api.SignatureDecodeStatus" json:"signature_decode_status,omitempty"`
+	// signature_results describes each signature in the same order as
+	// signature_decode_status, with the attributes decoded from it.
+	SignatureResults []*SignatureVerificationResult `protobuf:"bytes,2,rep,name=signature_results,json=signatureResults,proto3" json:"signature_results,omitempty"`
 }
 
 func (x *RequestVerificationInfo) Reset() {
@@ -417,6 +438,164 @@ func (x *RequestVerificationInfo) GetSignatureDecodeStatus() []SignatureDecodeSt
 	return nil
 }
 
+func (x *RequestVerificationInfo) GetSignatureResults() []*SignatureVerificationResult {
+	if x != nil {
+		return x.SignatureResults
+	}
+	return nil
+}
+
+// SignatureVerificationResult describes the verification of a single signature.
+// Attributes are empty when the signature couldn't be decoded.
+type SignatureVerificationResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SignatureDecodeStatus SignatureDecodeStatus `protobuf:"varint,1,opt,name=signature_decode_status,json=signatureDecodeStatus,proto3,enum=api.SignatureDecodeStatus" json:"signature_decode_status,omitempty"`
+	FromDomain            string                `protobuf:"bytes,2,opt,name=from_domain,json=fromDomain,proto3" json:"from_domain,omitempty"`
+	FromKey               string                `protobuf:"bytes,3,opt,name=from_key,json=fromKey,proto3" json:"from_key,omitempty"`
+	ToDomain              string                `protobuf:"bytes,4,opt,name=to_domain,json=toDomain,proto3" json:"to_domain,omitempty"`
+	ToKey                 string                `protobuf:"bytes,5,opt,name=to_key,json=toKey,proto3" json:"to_key,omitempty"`
+	InvokingDomain        string                `protobuf:"bytes,6,opt,name=invoking_domain,json=invokingDomain,proto3" json:"invoking_domain,omitempty"`
+	Timestamp             string                `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Nonce                 string                `protobuf:"bytes,8,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	SigningStatus         string                `protobuf:"bytes,9,opt,name=signing_status,json=signingStatus,proto3" json:"signing_status,omitempty"`
+	// error_code is the machine-readable code for a failed verification, as
+	// reported in metrics. It is empty when the signature is valid.
+	ErrorCode string `protobuf:"bytes,10,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	// reason is a human-readable explanation of error_code.
+	Reason string `protobuf:"bytes,11,opt,name=reason,proto3" json:"reason,omitempty"`
+	// signing_status_name is the readable name of signing_status.
+	SigningStatusName string `protobuf:"bytes,12,opt,name=signing_status_name,json=signingStatusName,proto3" json:"signing_status_name,omitempty"`
+	// flagged is set on valid signatures whose status asks the verifier not to
+	// act on them, such as "testing" or "advisory_only".
+	Flagged bool `protobuf:"varint,13,opt,name=flagged,proto3" json:"flagged,omitempty"`
+}
+
+func (x *SignatureVerificationResult) Reset() {
+	*x = SignatureVerificationResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_adscert_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignatureVerificationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignatureVerificationResult) ProtoMessage() {}
+
+func (x *SignatureVerificationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_adscert_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignatureVerificationResult.ProtoReflect.Descriptor instead.
+func (*SignatureVerificationResult) Descriptor() ([]byte, []int) {
+	return file_api_adscert_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SignatureVerificationResult) GetSignatureDecodeStatus() SignatureDecodeStatus {
+	if x != nil {
+		return x.SignatureDecodeStatus
+	}
+	return SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_UNDEFINED
+}
+
+func (x *SignatureVerificationResult) GetFromDomain() string {
+	if x != nil {
+		return x.FromDomain
+	}
+	return ""
+}
+
+func (x *SignatureVerificationResult) GetFromKey() string {
+	if x != nil {
+		return x.FromKey
+	}
+	return ""
+}
+
+func (x *SignatureVerificationResult) GetToDomain() string {
+	if x != nil {
+		return x.ToDomain
+	}
+	return ""
+}
+
+func (x *SignatureVerificationResult) GetToKey() string {
+	if x != nil {
+		return x.ToKey
+	}
+	return ""
+}
+
+func (x *SignatureVerificationResult) GetInvokingDomain() string {
+	if x != nil {
+		return x.InvokingDomain
+	}
+	return ""
+}
+
+func (x *SignatureVerificationResult) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *SignatureVerificationResult) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *SignatureVerificationResult) GetSigningStatus() string {
+	if x != nil {
+		return x.SigningStatus
+	}
+	return ""
+}
+
+func (x *SignatureVerificationResult) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *SignatureVerificationResult) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *SignatureVerificationResult) GetSigningStatusName() string {
+	if x != nil {
+		return x.SigningStatusName
+	}
+	return ""
+}
+
+func (x *SignatureVerificationResult) GetFlagged() bool {
+	if x != nil {
+		return x.Flagged
+	}
+	return false
+}
+
 // AuthenticatedConnectionSignatureRequest contains the parameters for a signing
 // request.
 type AuthenticatedConnectionSignatureRequest struct {
@@ -424,28 +603,653 @@ type AuthenticatedConnectionSignatureRequest struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RequestInfo *RequestInfo `protobuf:"bytes,1,opt,name=request_info,json=requestInfo,proto3" json:"request_info,omitempty"`
-	Timestamp   string       `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
-	Nonce       string       `protobuf:"bytes,3,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	RequestInfo *RequestInfo `protobuf:"bytes,1,opt,name=request_info,json=requestInfo,proto3" json:"request_info,omitempty"`
+	Timestamp   string       `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Nonce       string       `protobuf:"bytes,3,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// origin selects which of the signatory's ads.cert Call Signs signs the
+	// request. When empty, the signatory's default origin is used.
+	Origin string `protobuf:"bytes,4,opt,name=origin,proto3" json:"origin,omitempty"`
+	// signing_status is the status declared in the signature, by name, such
+	// as "testing" or "advisory_only" while rolling out with a counterparty.
+	// When empty, the signatory's default status is used.
+	SigningStatus string `protobuf:"bytes,5,opt,name=signing_status,json=signingStatus,proto3" json:"signing_status,omitempty"`
+}
+
+func (x *AuthenticatedConnectionSignatureRequest) Reset() {
+	*x = AuthenticatedConnectionSignatureRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_adscert_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthenticatedConnectionSignatureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticatedConnectionSignatureRequest) ProtoMessage() {}
+
+func (x *AuthenticatedConnectionSignatureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_adscert_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticatedConnectionSignatureRequest.ProtoReflect.Descriptor instead.
+func (*AuthenticatedConnectionSignatureRequest) Descriptor() ([]byte, []int) {
+	return file_api_adscert_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AuthenticatedConnectionSignatureRequest) GetRequestInfo() *RequestInfo {
+	if x != nil {
+		return x.RequestInfo
+	}
+	return nil
+}
+
+func (x *AuthenticatedConnectionSignatureRequest) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *AuthenticatedConnectionSignatureRequest) GetNonce() string {
+	if x != nil {
+		return x.Nonce
+	}
+	return ""
+}
+
+func (x *AuthenticatedConnectionSignatureRequest) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *AuthenticatedConnectionSignatureRequest) GetSigningStatus() string {
+	if x != nil {
+		return x.SigningStatus
+	}
+	return ""
+}
+
+// AuthenticatedConnectionSignatureResponse contains the results of a signing
+// request, including any signature and relevant metadata. Multiple signatures
+// can technically be present according to the specification.
+type AuthenticatedConnectionSignatureResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SignatureOperationStatus SignatureOperationStatus `protobuf:"varint,1,opt,name=signature_operation_status,json=signatureOperationStatus,proto3,enum=api.SignatureOperationStatus" json:"signature_operation_status,omitempty"`
+	RequestInfo              *RequestInfo             `protobuf:"bytes,2,opt,name=request_info,json=requestInfo,proto3" json:"request_info,omitempty"`
+}
+
+func (x *AuthenticatedConnectionSignatureResponse) Reset() {
+	*x = AuthenticatedConnectionSignatureResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_adscert_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthenticatedConnectionSignatureResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticatedConnectionSignatureResponse) ProtoMessage() {}
+
+func (x *AuthenticatedConnectionSignatureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_adscert_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticatedConnectionSignatureResponse.ProtoReflect.Descriptor instead.
+func (*AuthenticatedConnectionSignatureResponse) Descriptor() ([]byte, []int) {
+	return file_api_adscert_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AuthenticatedConnectionSignatureResponse) GetSignatureOperationStatus() SignatureOperationStatus {
+	if x != nil {
+		return x.SignatureOperationStatus
+	}
+	return SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_UNDEFINED
+}
+
+func (x *AuthenticatedConnectionSignatureResponse) GetRequestInfo() *RequestInfo {
+	if x != nil {
+		return x.RequestInfo
+	}
+	return nil
+}
+
+// AuthenticatedConnectionBatchSignatureRequest contains many requests to sign
+// in one round trip, such as one per destination of an auction fanned out to
+// several bidders.  The requests share a timestamp, origin and signing status.
+type AuthenticatedConnectionBatchSignatureRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RequestInfo []*RequestInfo `protobuf:"bytes,1,rep,name=request_info,json=requestInfo,proto3" json:"request_info,omitempty"`
+	// timestamp applies to every signature.  When empty, the current time is
+	// used.
+	Timestamp     string `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Origin        string `protobuf:"bytes,3,opt,name=origin,proto3" json:"origin,omitempty"`
+	SigningStatus string `protobuf:"bytes,4,opt,name=signing_status,json=signingStatus,proto3" json:"signing_status,omitempty"`
+}
+
+func (x *AuthenticatedConnectionBatchSignatureRequest) Reset() {
+	*x = AuthenticatedConnectionBatchSignatureRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_adscert_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthenticatedConnectionBatchSignatureRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticatedConnectionBatchSignatureRequest) ProtoMessage() {}
+
+func (x *AuthenticatedConnectionBatchSignatureRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_adscert_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticatedConnectionBatchSignatureRequest.ProtoReflect.Descriptor instead.
+func (*AuthenticatedConnectionBatchSignatureRequest) Descriptor() ([]byte, []int) {
+	return file_api_adscert_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AuthenticatedConnectionBatchSignatureRequest) GetRequestInfo() []*RequestInfo {
+	if x != nil {
+		return x.RequestInfo
+	}
+	return nil
+}
+
+func (x *AuthenticatedConnectionBatchSignatureRequest) GetTimestamp() string {
+	if x != nil {
+		return x.Timestamp
+	}
+	return ""
+}
+
+func (x *AuthenticatedConnectionBatchSignatureRequest) GetOrigin() string {
+	if x != nil {
+		return x.Origin
+	}
+	return ""
+}
+
+func (x *AuthenticatedConnectionBatchSignatureRequest) GetSigningStatus() string {
+	if x != nil {
+		return x.SigningStatus
+	}
+	return ""
+}
+
+// AuthenticatedConnectionBatchSignatureResponse contains one response per
+// request in the batch, in the same order.
+type AuthenticatedConnectionBatchSignatureResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Responses []*AuthenticatedConnectionSignatureResponse `protobuf:"bytes,1,rep,name=responses,proto3" json:"responses,omitempty"`
+}
+
+func (x *AuthenticatedConnectionBatchSignatureResponse) Reset() {
+	*x = AuthenticatedConnectionBatchSignatureResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_adscert_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthenticatedConnectionBatchSignatureResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticatedConnectionBatchSignatureResponse) ProtoMessage() {}
+
+func (x *AuthenticatedConnectionBatchSignatureResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_adscert_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticatedConnectionBatchSignatureResponse.ProtoReflect.Descriptor instead.
+func (*AuthenticatedConnectionBatchSignatureResponse) Descriptor() ([]byte, []int) {
+	return file_api_adscert_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AuthenticatedConnectionBatchSignatureResponse) GetResponses() []*AuthenticatedConnectionSignatureResponse {
+	if x != nil {
+		return x.Responses
+	}
+	return nil
+}
+
+// SignStreamRequest carries one signing request over a SignStream.  The
+// request_id is chosen by the client and echoed in the matching response,
+// since responses may arrive in a different order.
+type SignStreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RequestId uint64                                   `protobuf:"varint,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Request   *AuthenticatedConnectionSignatureRequest `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+}
+
+func (x *SignStreamRequest) Reset() {
+	*x = SignStreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_adscert_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignStreamRequest) ProtoMessage() {}
+
+func (x *SignStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_adscert_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignStreamRequest.ProtoReflect.Descriptor instead.
+func (*SignStreamRequest) Descriptor() ([]byte, []int) {
+	return file_api_adscert_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SignStreamRequest) GetRequestId() uint64 {
+	if x != nil {
+		return x.RequestId
+	}
+	return 0
+}
+
+func (x *SignStreamRequest) GetRequest() *AuthenticatedConnectionSignatureRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+// SignStreamResponse carries the response to the SignStreamRequest with the
+// same request_id.  error is set when the request failed as a whole, as a
+// unary call would have failed.
+type SignStreamResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RequestId uint64                                    `protobuf:"varint,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Response  *AuthenticatedConnectionSignatureResponse `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`
+	Error     string                                    `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *SignStreamResponse) Reset() {
+	*x = SignStreamResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_adscert_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SignStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SignStreamResponse) ProtoMessage() {}
+
+func (x *SignStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_adscert_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SignStreamResponse.ProtoReflect.Descriptor instead.
+func (*SignStreamResponse) Descriptor() ([]byte, []int) {
+	return file_api_adscert_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SignStreamResponse) GetRequestId() uint64 {
+	if x != nil {
+		return x.RequestId
+	}
+	return 0
+}
+
+func (x *SignStreamResponse) GetResponse() *AuthenticatedConnectionSignatureResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *SignStreamResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// VerifyStreamRequest carries one verification request over a VerifyStream,
+// matched to its response by request_id.
+type VerifyStreamRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RequestId uint64                                      `protobuf:"varint,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Request   *AuthenticatedConnectionVerificationRequest `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
+}
+
+func (x *VerifyStreamRequest) Reset() {
+	*x = VerifyStreamRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_adscert_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyStreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyStreamRequest) ProtoMessage() {}
+
+func (x *VerifyStreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_adscert_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyStreamRequest.ProtoReflect.Descriptor instead.
+func (*VerifyStreamRequest) Descriptor() ([]byte, []int) {
+	return file_api_adscert_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *VerifyStreamRequest) GetRequestId() uint64 {
+	if x != nil {
+		return x.RequestId
+	}
+	return 0
+}
+
+func (x *VerifyStreamRequest) GetRequest() *AuthenticatedConnectionVerificationRequest {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+// VerifyStreamResponse carries the response to the VerifyStreamRequest with
+// the same request_id.
+type VerifyStreamResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RequestId uint64                                       `protobuf:"varint,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Response  *AuthenticatedConnectionVerificationResponse `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`
+	Error     string                                       `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (x *VerifyStreamResponse) Reset() {
+	*x = VerifyStreamResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_adscert_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyStreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyStreamResponse) ProtoMessage() {}
+
+func (x *VerifyStreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_adscert_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyStreamResponse.ProtoReflect.Descriptor instead.
+func (*VerifyStreamResponse) Descriptor() ([]byte, []int) {
+	return file_api_adscert_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *VerifyStreamResponse) GetRequestId() uint64 {
+	if x != nil {
+		return x.RequestId
+	}
+	return 0
+}
+
+func (x *VerifyStreamResponse) GetResponse() *AuthenticatedConnectionVerificationResponse {
+	if x != nil {
+		return x.Response
+	}
+	return nil
+}
+
+func (x *VerifyStreamResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// AuthenticatedConnectionVerificationRequest contains a request for verifying
+// signatures generated by another party.
+type AuthenticatedConnectionVerificationRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RequestInfo []*RequestInfo `protobuf:"bytes,1,rep,name=request_info,json=requestInfo,proto3" json:"request_info,omitempty"`
+}
+
+func (x *AuthenticatedConnectionVerificationRequest) Reset() {
+	*x = AuthenticatedConnectionVerificationRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_adscert_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthenticatedConnectionVerificationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticatedConnectionVerificationRequest) ProtoMessage() {}
+
+func (x *AuthenticatedConnectionVerificationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_adscert_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticatedConnectionVerificationRequest.ProtoReflect.Descriptor instead.
+func (*AuthenticatedConnectionVerificationRequest) Descriptor() ([]byte, []int) {
+	return file_api_adscert_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AuthenticatedConnectionVerificationRequest) GetRequestInfo() []*RequestInfo {
+	if x != nil {
+		return x.RequestInfo
+	}
+	return nil
+}
+
+// AuthenticatedConnectionVerificationResponse contains the results of verifying
+// signatures.
+type AuthenticatedConnectionVerificationResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	VerificationOperationStatus VerificationOperationStatus `protobuf:"varint,1,opt,name=verification_operation_status,json=verificationOperationStatus,proto3,enum=api.VerificationOperationStatus" json:"verification_operation_status,omitempty"`
+	VerificationInfo            []*RequestVerificationInfo  `protobuf:"bytes,2,rep,name=verification_info,json=verificationInfo,proto3" json:"verification_info,omitempty"`
+}
+
+func (x *AuthenticatedConnectionVerificationResponse) Reset() {
+	*x = AuthenticatedConnectionVerificationResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_adscert_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AuthenticatedConnectionVerificationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticatedConnectionVerificationResponse) ProtoMessage() {}
+
+func (x *AuthenticatedConnectionVerificationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_adscert_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticatedConnectionVerificationResponse.ProtoReflect.Descriptor instead.
+func (*AuthenticatedConnectionVerificationResponse) Descriptor() ([]byte, []int) {
+	return file_api_adscert_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AuthenticatedConnectionVerificationResponse) GetVerificationOperationStatus() VerificationOperationStatus {
+	if x != nil {
+		return x.VerificationOperationStatus
+	}
+	return VerificationOperationStatus_VERIFICATION_OPERATION_STATUS_UNDEFINED
+}
+
+func (x *AuthenticatedConnectionVerificationResponse) GetVerificationInfo() []*RequestVerificationInfo {
+	if x != nil {
+		return x.VerificationInfo
+	}
+	return nil
+}
+
+// DeactivationSettings selects what the signatory stops signing and verifying,
+// without a redeployment.
+type DeactivationSettings struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// all deactivates the signatory entirely.
+	All bool `protobuf:"varint,1,opt,name=all,proto3" json:"all,omitempty"`
+	// origins lists hosted ads.cert Call Signs which neither sign nor verify.
+	Origins []string `protobuf:"bytes,2,rep,name=origins,proto3" json:"origins,omitempty"`
+	// counterparties lists domains which are neither signed for nor verified
+	// from, matched against both invoking and identity domains.
+	Counterparties []string `protobuf:"bytes,3,rep,name=counterparties,proto3" json:"counterparties,omitempty"`
 }
 
-func (x *AuthenticatedConnectionSignatureRequest) Reset() {
-	*x = AuthenticatedConnectionSignatureRequest{}
+func (x *DeactivationSettings) Reset() {
+	*x = DeactivationSettings{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_adscert_proto_msgTypes[3]
+		mi := &file_api_adscert_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AuthenticatedConnectionSignatureRequest) String() string {
+func (x *DeactivationSettings) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AuthenticatedConnectionSignatureRequest) ProtoMessage() {}
+func (*DeactivationSettings) ProtoMessage() {}
 
-func (x *AuthenticatedConnectionSignatureRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_adscert_proto_msgTypes[3]
+func (x *DeactivationSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_api_adscert_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -456,61 +1260,58 @@ func (x *AuthenticatedConnectionSignatureRequest) ProtoReflect() protoreflect.Me
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AuthenticatedConnectionSignatureRequest.ProtoReflect.Descriptor instead.
-func (*AuthenticatedConnectionSignatureRequest) Descriptor() ([]byte, []int) {
-	return file_api_adscert_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use DeactivationSettings.ProtoReflect.Descriptor instead.
+func (*DeactivationSettings) Descriptor() ([]byte, []int) {
+	return file_api_adscert_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *AuthenticatedConnectionSignatureRequest) GetRequestInfo() *RequestInfo {
+func (x *DeactivationSettings) GetAll() bool {
 	if x != nil {
-		return x.RequestInfo
+		return x.All
 	}
-	return nil
+	return false
 }
 
-func (x *AuthenticatedConnectionSignatureRequest) GetTimestamp() string {
+func (x *DeactivationSettings) GetOrigins() []string {
 	if x != nil {
-		return x.Timestamp
+		return x.Origins
 	}
-	return ""
+	return nil
 }
 
-func (x *AuthenticatedConnectionSignatureRequest) GetNonce() string {
+func (x *DeactivationSettings) GetCounterparties() []string {
 	if x != nil {
-		return x.Nonce
+		return x.Counterparties
 	}
-	return ""
+	return nil
 }
 
-// AuthenticatedConnectionSignatureResponse contains the results of a signing
-// request, including any signature and relevant metadata. Multiple signatures
-// can technically be present according to the specification.
-type AuthenticatedConnectionSignatureResponse struct {
+// SetDeactivationRequest replaces the signatory's deactivation settings.
+type SetDeactivationRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	SignatureOperationStatus SignatureOperationStatus `protobuf:"varint,1,opt,name=signature_operation_status,json=signatureOperationStatus,proto3,enum=api.SignatureOperationStatus" json:"signature_operation_status,omitempty"`
-	RequestInfo              *RequestInfo             `protobuf:"bytes,2,opt,name=request_info,json=requestInfo,proto3" json:"request_info,omitempty"`
+	Settings *DeactivationSettings `protobuf:"bytes,1,opt,name=settings,proto3" json:"settings,omitempty"`
 }
 
-func (x *AuthenticatedConnectionSignatureResponse) Reset() {
-	*x = AuthenticatedConnectionSignatureResponse{}
+func (x *SetDeactivationRequest) Reset() {
+	*x = SetDeactivationRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_adscert_proto_msgTypes[4]
+		mi := &file_api_adscert_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AuthenticatedConnectionSignatureResponse) String() string {
+func (x *SetDeactivationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AuthenticatedConnectionSignatureResponse) ProtoMessage() {}
+func (*SetDeactivationRequest) ProtoMessage() {}
 
-func (x *AuthenticatedConnectionSignatureResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_adscert_proto_msgTypes[4]
+func (x *SetDeactivationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_adscert_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -521,52 +1322,44 @@ func (x *AuthenticatedConnectionSignatureResponse) ProtoReflect() protoreflect.M
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AuthenticatedConnectionSignatureResponse.ProtoReflect.Descriptor instead.
-func (*AuthenticatedConnectionSignatureResponse) Descriptor() ([]byte, []int) {
-	return file_api_adscert_proto_rawDescGZIP(), []int{4}
-}
-
-func (x *AuthenticatedConnectionSignatureResponse) GetSignatureOperationStatus() SignatureOperationStatus {
-	if x != nil {
-		return x.SignatureOperationStatus
-	}
-	return SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_UNDEFINED
+// Deprecated: Use SetDeactivationRequest.ProtoReflect.Descriptor instead.
+func (*SetDeactivationRequest) Descriptor() ([]byte, []int) {
+	return file_api_adscert_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *AuthenticatedConnectionSignatureResponse) GetRequestInfo() *RequestInfo {
+func (x *SetDeactivationRequest) GetSettings() *DeactivationSettings {
 	if x != nil {
-		return x.RequestInfo
+		return x.Settings
 	}
 	return nil
 }
 
-// AuthenticatedConnectionVerificationRequest contains a request for verifying
-// signatures generated by another party.
-type AuthenticatedConnectionVerificationRequest struct {
+// SetDeactivationResponse contains the deactivation settings now in effect.
+type SetDeactivationResponse struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RequestInfo []*RequestInfo `protobuf:"bytes,1,rep,name=request_info,json=requestInfo,proto3" json:"request_info,omitempty"`
+	Settings *DeactivationSettings `protobuf:"bytes,1,opt,name=settings,proto3" json:"settings,omitempty"`
 }
 
-func (x *AuthenticatedConnectionVerificationRequest) Reset() {
-	*x = AuthenticatedConnectionVerificationRequest{}
+func (x *SetDeactivationResponse) Reset() {
+	*x = SetDeactivationResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_adscert_proto_msgTypes[5]
+		mi := &file_api_adscert_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AuthenticatedConnectionVerificationRequest) String() string {
+func (x *SetDeactivationResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AuthenticatedConnectionVerificationRequest) ProtoMessage() {}
+func (*SetDeactivationResponse) ProtoMessage() {}
 
-func (x *AuthenticatedConnectionVerificationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_adscert_proto_msgTypes[5]
+func (x *SetDeactivationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_adscert_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -577,46 +1370,42 @@ func (x *AuthenticatedConnectionVerificationRequest) ProtoReflect() protoreflect
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AuthenticatedConnectionVerificationRequest.ProtoReflect.Descriptor instead.
-func (*AuthenticatedConnectionVerificationRequest) Descriptor() ([]byte, []int) {
-	return file_api_adscert_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use SetDeactivationResponse.ProtoReflect.Descriptor instead.
+func (*SetDeactivationResponse) Descriptor() ([]byte, []int) {
+	return file_api_adscert_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *AuthenticatedConnectionVerificationRequest) GetRequestInfo() []*RequestInfo {
+func (x *SetDeactivationResponse) GetSettings() *DeactivationSettings {
 	if x != nil {
-		return x.RequestInfo
+		return x.Settings
 	}
 	return nil
 }
 
-// AuthenticatedConnectionVerificationResponse contains the results of verifying
-// signatures.
-type AuthenticatedConnectionVerificationResponse struct {
+// GetDeactivationRequest requests the signatory's deactivation settings.
+type GetDeactivationRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
-
-	VerificationOperationStatus VerificationOperationStatus `protobuf:"varint,1,opt,name=verification_operation_status,json=verificationOperationStatus,proto3,enum=api.VerificationOperationStatus" json:"verification_operation_status,omitempty"`
-	VerificationInfo            []*RequestVerificationInfo  `protobuf:"bytes,2,rep,name=verification_info,json=verificationInfo,proto3" json:"verification_info,omitempty"`
 }
 
-func (x *AuthenticatedConnectionVerificationResponse) Reset() {
-	*x = AuthenticatedConnectionVerificationResponse{}
+func (x *GetDeactivationRequest) Reset() {
+	*x = GetDeactivationRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_api_adscert_proto_msgTypes[6]
+		mi := &file_api_adscert_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *AuthenticatedConnectionVerificationResponse) String() string {
+func (x *GetDeactivationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AuthenticatedConnectionVerificationResponse) ProtoMessage() {}
+func (*GetDeactivationRequest) ProtoMessage() {}
 
-func (x *AuthenticatedConnectionVerificationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_adscert_proto_msgTypes[6]
+func (x *GetDeactivationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_adscert_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -627,21 +1416,55 @@ func (x *AuthenticatedConnectionVerificationResponse) ProtoReflect() protoreflec
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AuthenticatedConnectionVerificationResponse.ProtoReflect.Descriptor instead.
-func (*AuthenticatedConnectionVerificationResponse) Descriptor() ([]byte, []int) {
-	return file_api_adscert_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use GetDeactivationRequest.ProtoReflect.Descriptor instead.
+func (*GetDeactivationRequest) Descriptor() ([]byte, []int) {
+	return file_api_adscert_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *AuthenticatedConnectionVerificationResponse) GetVerificationOperationStatus() VerificationOperationStatus {
-	if x != nil {
-		return x.VerificationOperationStatus
+// GetDeactivationResponse contains the deactivation settings in effect.
+type GetDeactivationResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Settings *DeactivationSettings `protobuf:"bytes,1,opt,name=settings,proto3" json:"settings,omitempty"`
+}
+
+func (x *GetDeactivationResponse) Reset() {
+	*x = GetDeactivationResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_api_adscert_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return VerificationOperationStatus_VERIFICATION_OPERATION_STATUS_UNDEFINED
 }
 
-func (x *AuthenticatedConnectionVerificationResponse) GetVerificationInfo() []*RequestVerificationInfo {
+func (x *GetDeactivationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeactivationResponse) ProtoMessage() {}
+
+func (x *GetDeactivationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_adscert_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeactivationResponse.ProtoReflect.Descriptor instead.
+func (*GetDeactivationResponse) Descriptor() ([]byte, []int) {
+	return file_api_adscert_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GetDeactivationResponse) GetSettings() *DeactivationSettings {
 	if x != nil {
-		return x.VerificationInfo
+		return x.Settings
 	}
 	return nil
 }
@@ -677,140 +1500,305 @@ var file_api_adscert_proto_rawDesc = []byte{
 	0x1b, 0x0a, 0x09, 0x74, 0x6f, 0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x06, 0x20, 0x01,
 	0x28, 0x09, 0x52, 0x08, 0x74, 0x6f, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x12, 0x15, 0x0a, 0x06,
 	0x74, 0x6f, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f,
-	0x4b, 0x65, 0x79, 0x22, 0x6d, 0x0a, 0x17, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x56, 0x65,
-	0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x52,
-	0x0a, 0x17, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x64, 0x65, 0x63, 0x6f,
-	0x64, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0e, 0x32,
-	0x1a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x44,
-	0x65, 0x63, 0x6f, 0x64, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x15, 0x73, 0x69, 0x67,
-	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x53, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x22, 0x92, 0x01, 0x0a, 0x27, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63,
-	0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69,
-	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x33,
-	0x0a, 0x0c, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0b, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49,
-	0x6e, 0x66, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
-	0x70, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x22, 0xbc, 0x01, 0x0a, 0x28, 0x41, 0x75, 0x74, 0x68,
+	0x4b, 0x65, 0x79, 0x22, 0xbc, 0x01, 0x0a, 0x17, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x56,
+	0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x12,
+	0x52, 0x0a, 0x17, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x64, 0x65, 0x63,
+	0x6f, 0x64, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0e,
+	0x32, 0x1a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x15, 0x73, 0x69,
+	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x53, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x4d, 0x0a, 0x11, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x5f, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x20,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x56, 0x65,
+	0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x52, 0x10, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x73, 0x22, 0xe6, 0x03, 0x0a, 0x1b, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x52, 0x0a, 0x17, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x5f,
+	0x64, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x15, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x66, 0x72, 0x6f, 0x6d, 0x5f, 0x64,
+	0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x66, 0x72, 0x6f,
+	0x6d, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x12, 0x19, 0x0a, 0x08, 0x66, 0x72, 0x6f, 0x6d, 0x5f,
+	0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x66, 0x72, 0x6f, 0x6d, 0x4b,
+	0x65, 0x79, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x6f, 0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x6f, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x12,
+	0x15, 0x0a, 0x06, 0x74, 0x6f, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x05, 0x74, 0x6f, 0x4b, 0x65, 0x79, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x76, 0x6f, 0x6b, 0x69,
+	0x6e, 0x67, 0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0e, 0x69, 0x6e, 0x76, 0x6f, 0x6b, 0x69, 0x6e, 0x67, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x12,
+	0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x14, 0x0a,
+	0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x6f,
+	0x6e, 0x63, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x69, 0x67,
+	0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72,
+	0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x12, 0x2e, 0x0a, 0x13, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11,
+	0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x66, 0x6c, 0x61, 0x67, 0x67, 0x65, 0x64, 0x18, 0x0d, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x66, 0x6c, 0x61, 0x67, 0x67, 0x65, 0x64, 0x22, 0xd1, 0x01, 0x0a, 0x27,
+	0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x0c, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52,
+	0x0b, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1c, 0x0a, 0x09,
+	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x6e, 0x6f,
+	0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6e, 0x6f, 0x6e, 0x63, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x69, 0x67, 0x6e,
+	0x69, 0x6e, 0x67, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22,
+	0xbc, 0x01, 0x0a, 0x28, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65,
+	0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5b, 0x0a, 0x1a,
+	0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x1d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52,
+	0x18, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x33, 0x0a, 0x0c, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x6e, 0x66,
+	0x6f, 0x52, 0x0b, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0xc0,
+	0x01, 0x0a, 0x2c, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53,
+	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x33, 0x0a, 0x0c, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0b, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x69,
+	0x67, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x22, 0x7c, 0x0a, 0x2d, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x4b, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x75, 0x74, 0x68,
 	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
 	0x69, 0x6f, 0x6e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5b, 0x0a, 0x1a, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
-	0x65, 0x5f, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53,
-	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x18, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
-	0x72, 0x65, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x12, 0x33, 0x0a, 0x0c, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x6e, 0x66,
-	0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0b, 0x72, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0x61, 0x0a, 0x2a, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e,
+	0x6f, 0x6e, 0x73, 0x65, 0x52, 0x09, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x73, 0x22,
+	0x7a, 0x0a, 0x11, 0x53, 0x69, 0x67, 0x6e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x49, 0x64, 0x12, 0x46, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65,
+	0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x52, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x94, 0x01, 0x0a, 0x12,
+	0x53, 0x69, 0x67, 0x6e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49,
+	0x64, 0x12, 0x49, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x2d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e,
 	0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x33, 0x0a, 0x0c, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f,
-	0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69,
-	0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0b, 0x72, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x22, 0xde, 0x01, 0x0a, 0x2b, 0x41, 0x75,
-	0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x64, 0x0a, 0x1d, 0x76, 0x65, 0x72,
-	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
-	0x32, 0x20, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
-	0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x52, 0x1b, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12,
-	0x49, 0x0a, 0x11, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
-	0x69, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x61, 0x70, 0x69,
-	0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x10, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69,
-	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66, 0x6f, 0x2a, 0xc9, 0x03, 0x0a, 0x15, 0x53,
-	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x53, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x12, 0x25, 0x0a, 0x21, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52,
-	0x45, 0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f,
-	0x55, 0x4e, 0x44, 0x45, 0x46, 0x49, 0x4e, 0x45, 0x44, 0x10, 0x00, 0x12, 0x2e, 0x0a, 0x2a, 0x53,
-	0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f,
-	0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x42, 0x4f, 0x44, 0x59, 0x5f, 0x41, 0x4e, 0x44, 0x5f,
-	0x55, 0x52, 0x4c, 0x5f, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x01, 0x12, 0x26, 0x0a, 0x22, 0x53,
-	0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f,
-	0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x42, 0x4f, 0x44, 0x59, 0x5f, 0x56, 0x41, 0x4c, 0x49,
-	0x44, 0x10, 0x02, 0x12, 0x2d, 0x0a, 0x29, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45,
-	0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x49,
-	0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45,
-	0x10, 0x03, 0x12, 0x31, 0x0a, 0x2d, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f,
-	0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x53, 0x49,
-	0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x50, 0x52, 0x45, 0x53,
-	0x45, 0x4e, 0x54, 0x10, 0x04, 0x12, 0x2f, 0x0a, 0x2b, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55,
-	0x52, 0x45, 0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53,
-	0x5f, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x4d, 0x41, 0x4c, 0x46, 0x4f,
-	0x52, 0x4d, 0x45, 0x44, 0x10, 0x05, 0x12, 0x2f, 0x0a, 0x2b, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54,
+	0x6e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05,
+	0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x22, 0x7f, 0x0a, 0x13, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x53, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x72, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x12, 0x49, 0x0a, 0x07, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6e,
+	0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x07, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x22, 0x99, 0x01, 0x0a, 0x14, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x53, 0x74,
+	0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1d, 0x0a, 0x0a,
+	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
+	0x52, 0x09, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x64, 0x12, 0x4c, 0x0a, 0x08, 0x72,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x30, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65,
+	0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x56, 0x65, 0x72, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52,
+	0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x22,
+	0x61, 0x0a, 0x2a, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x33, 0x0a,
+	0x0c, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0b, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x49, 0x6e,
+	0x66, 0x6f, 0x22, 0xde, 0x01, 0x0a, 0x2b, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63,
+	0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x56, 0x65,
+	0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x12, 0x64, 0x0a, 0x1d, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x5f, 0x6f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x20, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x1b, 0x76, 0x65, 0x72,
+	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x49, 0x0a, 0x11, 0x76, 0x65, 0x72, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x6e, 0x66,
+	0x6f, 0x52, 0x10, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x49,
+	0x6e, 0x66, 0x6f, 0x22, 0x6a, 0x0a, 0x14, 0x44, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x10, 0x0a, 0x03, 0x61,
+	0x6c, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x61, 0x6c, 0x6c, 0x12, 0x18, 0x0a,
+	0x07, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x65, 0x72, 0x70, 0x61, 0x72, 0x74, 0x69, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0e, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x65, 0x72, 0x70, 0x61, 0x72, 0x74, 0x69, 0x65, 0x73, 0x22,
+	0x4f, 0x0a, 0x16, 0x53, 0x65, 0x74, 0x44, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x35, 0x0a, 0x08, 0x73, 0x65, 0x74,
+	0x74, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x44, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65,
+	0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73,
+	0x22, 0x50, 0x0a, 0x17, 0x53, 0x65, 0x74, 0x44, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x73,
+	0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x44, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x53, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x52, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e,
+	0x67, 0x73, 0x22, 0x18, 0x0a, 0x16, 0x47, 0x65, 0x74, 0x44, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x50, 0x0a, 0x17,
+	0x47, 0x65, 0x74, 0x44, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69,
+	0x6e, 0x67, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x61, 0x70, 0x69, 0x2e,
+	0x44, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x65, 0x74, 0x74,
+	0x69, 0x6e, 0x67, 0x73, 0x52, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x2a, 0xe0,
+	0x05, 0x0a, 0x15, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x44, 0x65, 0x63, 0x6f,
+	0x64, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x25, 0x0a, 0x21, 0x53, 0x49, 0x47, 0x4e,
+	0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x54, 0x41,
+	0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x44, 0x45, 0x46, 0x49, 0x4e, 0x45, 0x44, 0x10, 0x00, 0x12,
+	0x2e, 0x0a, 0x2a, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x44, 0x45, 0x43,
+	0x4f, 0x44, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x42, 0x4f, 0x44, 0x59, 0x5f,
+	0x41, 0x4e, 0x44, 0x5f, 0x55, 0x52, 0x4c, 0x5f, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x01, 0x12,
+	0x26, 0x0a, 0x22, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x44, 0x45, 0x43,
+	0x4f, 0x44, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x42, 0x4f, 0x44, 0x59, 0x5f,
+	0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x02, 0x12, 0x2d, 0x0a, 0x29, 0x53, 0x49, 0x47, 0x4e, 0x41,
+	0x54, 0x55, 0x52, 0x45, 0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x55, 0x53, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x41,
+	0x54, 0x55, 0x52, 0x45, 0x10, 0x03, 0x12, 0x31, 0x0a, 0x2d, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54,
 	0x55, 0x52, 0x45, 0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55,
-	0x53, 0x5f, 0x55, 0x4e, 0x52, 0x45, 0x4c, 0x41, 0x54, 0x45, 0x44, 0x5f, 0x53, 0x49, 0x47, 0x4e,
-	0x41, 0x54, 0x55, 0x52, 0x45, 0x10, 0x06, 0x12, 0x35, 0x0a, 0x31, 0x53, 0x49, 0x47, 0x4e, 0x41,
+	0x53, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x4e, 0x4f, 0x54, 0x5f,
+	0x50, 0x52, 0x45, 0x53, 0x45, 0x4e, 0x54, 0x10, 0x04, 0x12, 0x2f, 0x0a, 0x2b, 0x53, 0x49, 0x47,
+	0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x54,
+	0x41, 0x54, 0x55, 0x53, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x4d,
+	0x41, 0x4c, 0x46, 0x4f, 0x52, 0x4d, 0x45, 0x44, 0x10, 0x05, 0x12, 0x2f, 0x0a, 0x2b, 0x53, 0x49,
+	0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x53,
+	0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x52, 0x45, 0x4c, 0x41, 0x54, 0x45, 0x44, 0x5f,
+	0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x10, 0x06, 0x12, 0x35, 0x0a, 0x31, 0x53,
+	0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f,
+	0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x43, 0x4f, 0x55, 0x4e, 0x54, 0x45, 0x52, 0x50, 0x41,
+	0x52, 0x54, 0x59, 0x5f, 0x4c, 0x4f, 0x4f, 0x4b, 0x55, 0x50, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52,
+	0x10, 0x07, 0x12, 0x36, 0x0a, 0x32, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f,
+	0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x4e, 0x4f,
+	0x5f, 0x53, 0x48, 0x41, 0x52, 0x45, 0x44, 0x5f, 0x53, 0x45, 0x43, 0x52, 0x45, 0x54, 0x5f, 0x41,
+	0x56, 0x41, 0x49, 0x4c, 0x41, 0x42, 0x4c, 0x45, 0x10, 0x08, 0x12, 0x2b, 0x0a, 0x27, 0x53, 0x49,
+	0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x53,
+	0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x53, 0x54, 0x41, 0x4c, 0x45, 0x5f, 0x53, 0x49, 0x47, 0x4e,
+	0x41, 0x54, 0x55, 0x52, 0x45, 0x10, 0x09, 0x12, 0x2e, 0x0a, 0x2a, 0x53, 0x49, 0x47, 0x4e, 0x41,
+	0x54, 0x55, 0x52, 0x45, 0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54,
+	0x55, 0x53, 0x5f, 0x52, 0x45, 0x50, 0x4c, 0x41, 0x59, 0x45, 0x44, 0x5f, 0x53, 0x49, 0x47, 0x4e,
+	0x41, 0x54, 0x55, 0x52, 0x45, 0x10, 0x0a, 0x12, 0x2d, 0x0a, 0x29, 0x53, 0x49, 0x47, 0x4e, 0x41,
 	0x54, 0x55, 0x52, 0x45, 0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54,
-	0x55, 0x53, 0x5f, 0x43, 0x4f, 0x55, 0x4e, 0x54, 0x45, 0x52, 0x50, 0x41, 0x52, 0x54, 0x59, 0x5f,
-	0x4c, 0x4f, 0x4f, 0x4b, 0x55, 0x50, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x07, 0x12, 0x36,
-	0x0a, 0x32, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x44, 0x45, 0x43, 0x4f,
-	0x44, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x4e, 0x4f, 0x5f, 0x53, 0x48, 0x41,
-	0x52, 0x45, 0x44, 0x5f, 0x53, 0x45, 0x43, 0x52, 0x45, 0x54, 0x5f, 0x41, 0x56, 0x41, 0x49, 0x4c,
-	0x41, 0x42, 0x4c, 0x45, 0x10, 0x08, 0x2a, 0x88, 0x02, 0x0a, 0x18, 0x53, 0x69, 0x67, 0x6e, 0x61,
-	0x74, 0x75, 0x72, 0x65, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x12, 0x28, 0x0a, 0x24, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45,
-	0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55,
-	0x53, 0x5f, 0x55, 0x4e, 0x44, 0x45, 0x46, 0x49, 0x4e, 0x45, 0x44, 0x10, 0x00, 0x12, 0x21, 0x0a,
-	0x1d, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41,
-	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x4f, 0x4b, 0x10, 0x01,
-	0x12, 0x34, 0x0a, 0x30, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x4f, 0x50,
-	0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x53,
-	0x49, 0x47, 0x4e, 0x41, 0x54, 0x4f, 0x52, 0x59, 0x5f, 0x44, 0x45, 0x41, 0x43, 0x54, 0x49, 0x56,
-	0x41, 0x54, 0x45, 0x44, 0x10, 0x02, 0x12, 0x37, 0x0a, 0x33, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54,
-	0x55, 0x52, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54,
-	0x41, 0x54, 0x55, 0x53, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x4f, 0x52, 0x59, 0x5f, 0x49,
-	0x4e, 0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x03, 0x12,
-	0x30, 0x0a, 0x2c, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x4f, 0x50, 0x45,
-	0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x4d, 0x41,
-	0x4c, 0x46, 0x4f, 0x52, 0x4d, 0x45, 0x44, 0x5f, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x10,
-	0x04, 0x2a, 0x9a, 0x02, 0x0a, 0x1b, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x4f, 0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x12, 0x2b, 0x0a, 0x27, 0x56, 0x45, 0x52, 0x49, 0x46, 0x49, 0x43, 0x41, 0x54, 0x49, 0x4f,
-	0x4e, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54,
-	0x55, 0x53, 0x5f, 0x55, 0x4e, 0x44, 0x45, 0x46, 0x49, 0x4e, 0x45, 0x44, 0x10, 0x00, 0x12, 0x24,
-	0x0a, 0x20, 0x56, 0x45, 0x52, 0x49, 0x46, 0x49, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4f,
+	0x55, 0x53, 0x5f, 0x4b, 0x45, 0x59, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x50, 0x55, 0x42, 0x4c, 0x49,
+	0x53, 0x48, 0x45, 0x44, 0x10, 0x0b, 0x12, 0x2b, 0x0a, 0x27, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54,
+	0x55, 0x52, 0x45, 0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55,
+	0x53, 0x5f, 0x57, 0x52, 0x4f, 0x4e, 0x47, 0x5f, 0x52, 0x45, 0x43, 0x49, 0x50, 0x49, 0x45, 0x4e,
+	0x54, 0x10, 0x0c, 0x12, 0x33, 0x0a, 0x2f, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45,
+	0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x52,
+	0x45, 0x4a, 0x45, 0x43, 0x54, 0x45, 0x44, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x49, 0x4e, 0x47, 0x5f,
+	0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x10, 0x0d, 0x12, 0x27, 0x0a, 0x23, 0x53, 0x49, 0x47, 0x4e,
+	0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x44, 0x45, 0x43, 0x4f, 0x44, 0x45, 0x5f, 0x53, 0x54, 0x41,
+	0x54, 0x55, 0x53, 0x5f, 0x44, 0x45, 0x41, 0x43, 0x54, 0x49, 0x56, 0x41, 0x54, 0x45, 0x44, 0x10,
+	0x0e, 0x2a, 0x88, 0x02, 0x0a, 0x18, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x4f,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x28,
+	0x0a, 0x24, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52,
+	0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x44,
+	0x45, 0x46, 0x49, 0x4e, 0x45, 0x44, 0x10, 0x00, 0x12, 0x21, 0x0a, 0x1d, 0x53, 0x49, 0x47, 0x4e,
+	0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f,
+	0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x4f, 0x4b, 0x10, 0x01, 0x12, 0x34, 0x0a, 0x30, 0x53,
+	0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x49,
+	0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54,
+	0x4f, 0x52, 0x59, 0x5f, 0x44, 0x45, 0x41, 0x43, 0x54, 0x49, 0x56, 0x41, 0x54, 0x45, 0x44, 0x10,
+	0x02, 0x12, 0x37, 0x0a, 0x33, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x4f,
 	0x50, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f,
-	0x4f, 0x4b, 0x10, 0x01, 0x12, 0x37, 0x0a, 0x33, 0x56, 0x45, 0x52, 0x49, 0x46, 0x49, 0x43, 0x41,
-	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53,
-	0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x4f, 0x52, 0x59, 0x5f,
-	0x44, 0x45, 0x41, 0x43, 0x54, 0x49, 0x56, 0x41, 0x54, 0x45, 0x44, 0x10, 0x02, 0x12, 0x3a, 0x0a,
-	0x36, 0x56, 0x45, 0x52, 0x49, 0x46, 0x49, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4f, 0x50,
-	0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x53,
-	0x49, 0x47, 0x4e, 0x41, 0x54, 0x4f, 0x52, 0x59, 0x5f, 0x49, 0x4e, 0x54, 0x45, 0x52, 0x4e, 0x41,
-	0x4c, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x03, 0x12, 0x33, 0x0a, 0x2f, 0x56, 0x45, 0x52,
+	0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x4f, 0x52, 0x59, 0x5f, 0x49, 0x4e, 0x54, 0x45, 0x52, 0x4e,
+	0x41, 0x4c, 0x5f, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x10, 0x03, 0x12, 0x30, 0x0a, 0x2c, 0x53, 0x49,
+	0x47, 0x4e, 0x41, 0x54, 0x55, 0x52, 0x45, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f,
+	0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x4d, 0x41, 0x4c, 0x46, 0x4f, 0x52, 0x4d,
+	0x45, 0x44, 0x5f, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x10, 0x04, 0x2a, 0x9a, 0x02, 0x0a,
+	0x1b, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4f, 0x70, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x2b, 0x0a, 0x27,
+	0x56, 0x45, 0x52, 0x49, 0x46, 0x49, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4f, 0x50, 0x45,
+	0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e,
+	0x44, 0x45, 0x46, 0x49, 0x4e, 0x45, 0x44, 0x10, 0x00, 0x12, 0x24, 0x0a, 0x20, 0x56, 0x45, 0x52,
 	0x49, 0x46, 0x49, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54,
-	0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x4d, 0x41, 0x4c, 0x46, 0x4f,
-	0x52, 0x4d, 0x45, 0x44, 0x5f, 0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x10, 0x04, 0x32, 0x97,
-	0x02, 0x0a, 0x10, 0x41, 0x64, 0x73, 0x43, 0x65, 0x72, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74,
-	0x6f, 0x72, 0x79, 0x12, 0x7c, 0x0a, 0x1b, 0x53, 0x69, 0x67, 0x6e, 0x41, 0x75, 0x74, 0x68, 0x65,
-	0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x12, 0x2c, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74,
-	0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x2d, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63,
-	0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69,
-	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
-	0x00, 0x12, 0x84, 0x01, 0x0a, 0x1d, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x41, 0x75, 0x74, 0x68,
-	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
-	0x69, 0x6f, 0x6e, 0x12, 0x2f, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e,
-	0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x1a, 0x30, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65,
+	0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x4f, 0x4b, 0x10, 0x01, 0x12,
+	0x37, 0x0a, 0x33, 0x56, 0x45, 0x52, 0x49, 0x46, 0x49, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f,
+	0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53,
+	0x5f, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54, 0x4f, 0x52, 0x59, 0x5f, 0x44, 0x45, 0x41, 0x43, 0x54,
+	0x49, 0x56, 0x41, 0x54, 0x45, 0x44, 0x10, 0x02, 0x12, 0x3a, 0x0a, 0x36, 0x56, 0x45, 0x52, 0x49,
+	0x46, 0x49, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x49,
+	0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x53, 0x49, 0x47, 0x4e, 0x41, 0x54,
+	0x4f, 0x52, 0x59, 0x5f, 0x49, 0x4e, 0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c, 0x5f, 0x45, 0x52, 0x52,
+	0x4f, 0x52, 0x10, 0x03, 0x12, 0x33, 0x0a, 0x2f, 0x56, 0x45, 0x52, 0x49, 0x46, 0x49, 0x43, 0x41,
+	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4f, 0x50, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53,
+	0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x4d, 0x41, 0x4c, 0x46, 0x4f, 0x52, 0x4d, 0x45, 0x44, 0x5f,
+	0x52, 0x45, 0x51, 0x55, 0x45, 0x53, 0x54, 0x10, 0x04, 0x32, 0xb5, 0x04, 0x0a, 0x10, 0x41, 0x64,
+	0x73, 0x43, 0x65, 0x72, 0x74, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x7c,
+	0x0a, 0x1b, 0x53, 0x69, 0x67, 0x6e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61,
+	0x74, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2c, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65,
+	0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2d, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x8b, 0x01, 0x0a,
+	0x20, 0x53, 0x69, 0x67, 0x6e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x12, 0x31, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69,
+	0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x32, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65,
 	0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x2f, 0x5a, 0x2d, 0x67, 0x69, 0x74, 0x68,
-	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x49, 0x41, 0x42, 0x54, 0x65, 0x63, 0x68, 0x4c, 0x61,
-	0x62, 0x2f, 0x61, 0x64, 0x73, 0x63, 0x65, 0x72, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x64,
-	0x73, 0x63, 0x65, 0x72, 0x74, 0x2f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x6f, 0x6e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x84, 0x01, 0x0a, 0x1d, 0x56,
+	0x65, 0x72, 0x69, 0x66, 0x79, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2f, 0x2e, 0x61,
+	0x70, 0x69, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65, 0x64,
+	0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69,
+	0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x30, 0x2e,
+	0x61, 0x70, 0x69, 0x2e, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x65,
+	0x64, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x56, 0x65, 0x72, 0x69, 0x66,
+	0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22,
+	0x00, 0x12, 0x43, 0x0a, 0x0a, 0x53, 0x69, 0x67, 0x6e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12,
+	0x16, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x69, 0x67, 0x6e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x69,
+	0x67, 0x6e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x12, 0x49, 0x0a, 0x0c, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x18, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x56, 0x65, 0x72,
+	0x69, 0x66, 0x79, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x19, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x28, 0x01, 0x30,
+	0x01, 0x32, 0xb7, 0x01, 0x0a, 0x15, 0x41, 0x64, 0x73, 0x43, 0x65, 0x72, 0x74, 0x53, 0x69, 0x67,
+	0x6e, 0x61, 0x74, 0x6f, 0x72, 0x79, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x4e, 0x0a, 0x0f, 0x53,
+	0x65, 0x74, 0x44, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x53, 0x65, 0x74, 0x44, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x53, 0x65, 0x74, 0x44, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x4e, 0x0a, 0x0f, 0x47,
+	0x65, 0x74, 0x44, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b,
+	0x2e, 0x61, 0x70, 0x69, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1c, 0x2e, 0x61, 0x70,
+	0x69, 0x2e, 0x47, 0x65, 0x74, 0x44, 0x65, 0x61, 0x63, 0x74, 0x69, 0x76, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x42, 0x2f, 0x5a, 0x2d, 0x67,
+	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x49, 0x41, 0x42, 0x54, 0x65, 0x63,
+	0x68, 0x4c, 0x61, 0x62, 0x2f, 0x61, 0x64, 0x73, 0x63, 0x65, 0x72, 0x74, 0x2f, 0x70, 0x6b, 0x67,
+	0x2f, 0x61, 0x64, 0x73, 0x63, 0x65, 0x72, 0x74, 0x2f, 0x61, 0x70, 0x69, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -826,37 +1814,70 @@ func file_api_adscert_proto_rawDescGZIP() []byte {
 }
 
 var file_api_adscert_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_api_adscert_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_api_adscert_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
 var file_api_adscert_proto_goTypes = []interface{}{
-	(SignatureDecodeStatus)(0),                          // 0: api.SignatureDecodeStatus
-	(SignatureOperationStatus)(0),                       // 1: api.SignatureOperationStatus
-	(VerificationOperationStatus)(0),                    // 2: api.VerificationOperationStatus
-	(*RequestInfo)(nil),                                 // 3: api.RequestInfo
-	(*SignatureInfo)(nil),                               // 4: api.SignatureInfo
-	(*RequestVerificationInfo)(nil),                     // 5: api.RequestVerificationInfo
-	(*AuthenticatedConnectionSignatureRequest)(nil),     // 6: api.AuthenticatedConnectionSignatureRequest
-	(*AuthenticatedConnectionSignatureResponse)(nil),    // 7: api.AuthenticatedConnectionSignatureResponse
-	(*AuthenticatedConnectionVerificationRequest)(nil),  // 8: api.AuthenticatedConnectionVerificationRequest
-	(*AuthenticatedConnectionVerificationResponse)(nil), // 9: api.AuthenticatedConnectionVerificationResponse
+	(SignatureDecodeStatus)(0),                            // 0: api.SignatureDecodeStatus
+	(SignatureOperationStatus)(0),                         // 1: api.SignatureOperationStatus
+	(VerificationOperationStatus)(0),                      // 2: api.VerificationOperationStatus
+	(*RequestInfo)(nil),                                   // 3: api.RequestInfo
+	(*SignatureInfo)(nil),                                 // 4: api.SignatureInfo
+	(*RequestVerificationInfo)(nil),                       // 5: api.RequestVerificationInfo
+	(*SignatureVerificationResult)(nil),                   // 6: api.SignatureVerificationResult
+	(*AuthenticatedConnectionSignatureRequest)(nil),       // 7: api.AuthenticatedConnectionSignatureRequest
+	(*AuthenticatedConnectionSignatureResponse)(nil),      // 8: api.AuthenticatedConnectionSignatureResponse
+	(*AuthenticatedConnectionBatchSignatureRequest)(nil),  // 9: api.AuthenticatedConnectionBatchSignatureRequest
+	(*AuthenticatedConnectionBatchSignatureResponse)(nil), // 10: api.AuthenticatedConnectionBatchSignatureResponse
+	(*SignStreamRequest)(nil),                             // 11: api.SignStreamRequest
+	(*SignStreamResponse)(nil),                            // 12: api.SignStreamResponse
+	(*VerifyStreamRequest)(nil),                           // 13: api.VerifyStreamRequest
+	(*VerifyStreamResponse)(nil),                          // 14: api.VerifyStreamResponse
+	(*AuthenticatedConnectionVerificationRequest)(nil),    // 15: api.AuthenticatedConnectionVerificationRequest
+	(*AuthenticatedConnectionVerificationResponse)(nil),   // 16: api.AuthenticatedConnectionVerificationResponse
+	(*DeactivationSettings)(nil),                          // 17: api.DeactivationSettings
+	(*SetDeactivationRequest)(nil),                        // 18: api.SetDeactivationRequest
+	(*SetDeactivationResponse)(nil),                       // 19: api.SetDeactivationResponse
+	(*GetDeactivationRequest)(nil),                        // 20: api.GetDeactivationRequest
+	(*GetDeactivationResponse)(nil),                       // 21: api.GetDeactivationResponse
 }
 var file_api_adscert_proto_depIdxs = []int32{
 	4,  // 0: api.RequestInfo.signature_info:type_name -> api.SignatureInfo
 	0,  // 1: api.RequestVerificationInfo.signature_decode_status:type_name -> api.SignatureDecodeStatus
-	3,  // 2: api.AuthenticatedConnectionSignatureRequest.request_info:type_name -> api.RequestInfo
-	1,  // 3: api.AuthenticatedConnectionSignatureResponse.signature_operation_status:type_name -> api.SignatureOperationStatus
-	3,  // 4: api.AuthenticatedConnectionSignatureResponse.request_info:type_name -> api.RequestInfo
-	3,  // 5: api.AuthenticatedConnectionVerificationRequest.request_info:type_name -> api.RequestInfo
-	2,  // 6: api.AuthenticatedConnectionVerificationResponse.verification_operation_status:type_name -> api.VerificationOperationStatus
-	5,  // 7: api.AuthenticatedConnectionVerificationResponse.verification_info:type_name -> api.RequestVerificationInfo
-	6,  // 8: api.AdsCertSignatory.SignAuthenticatedConnection:input_type -> api.AuthenticatedConnectionSignatureRequest
-	8,  // 9: api.AdsCertSignatory.VerifyAuthenticatedConnection:input_type -> api.AuthenticatedConnectionVerificationRequest
-	7,  // 10: api.AdsCertSignatory.SignAuthenticatedConnection:output_type -> api.AuthenticatedConnectionSignatureResponse
-	9,  // 11: api.AdsCertSignatory.VerifyAuthenticatedConnection:output_type -> api.AuthenticatedConnectionVerificationResponse
-	10, // [10:12] is the sub-list for method output_type
-	8,  // [8:10] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	6,  // 2: api.RequestVerificationInfo.signature_results:type_name -> api.SignatureVerificationResult
+	0,  // 3: api.SignatureVerificationResult.signature_decode_status:type_name -> api.SignatureDecodeStatus
+	3,  // 4: api.AuthenticatedConnectionSignatureRequest.request_info:type_name -> api.RequestInfo
+	1,  // 5: api.AuthenticatedConnectionSignatureResponse.signature_operation_status:type_name -> api.SignatureOperationStatus
+	3,  // 6: api.AuthenticatedConnectionSignatureResponse.request_info:type_name -> api.RequestInfo
+	3,  // 7: api.AuthenticatedConnectionBatchSignatureRequest.request_info:type_name -> api.RequestInfo
+	8,  // 8: api.AuthenticatedConnectionBatchSignatureResponse.responses:type_name -> api.AuthenticatedConnectionSignatureResponse
+	7,  // 9: api.SignStreamRequest.request:type_name -> api.AuthenticatedConnectionSignatureRequest
+	8,  // 10: api.SignStreamResponse.response:type_name -> api.AuthenticatedConnectionSignatureResponse
+	15, // 11: api.VerifyStreamRequest.request:type_name -> api.AuthenticatedConnectionVerificationRequest
+	16, // 12: api.VerifyStreamResponse.response:type_name -> api.AuthenticatedConnectionVerificationResponse
+	3,  // 13: api.AuthenticatedConnectionVerificationRequest.request_info:type_name -> api.RequestInfo
+	2,  // 14: api.AuthenticatedConnectionVerificationResponse.verification_operation_status:type_name -> api.VerificationOperationStatus
+	5,  // 15: api.AuthenticatedConnectionVerificationResponse.verification_info:type_name -> api.RequestVerificationInfo
+	17, // 16: api.SetDeactivationRequest.settings:type_name -> api.DeactivationSettings
+	17, // 17: api.SetDeactivationResponse.settings:type_name -> api.DeactivationSettings
+	17, // 18: api.GetDeactivationResponse.settings:type_name -> api.DeactivationSettings
+	7,  // 19: api.AdsCertSignatory.SignAuthenticatedConnection:input_type -> api.AuthenticatedConnectionSignatureRequest
+	9,  // 20: api.AdsCertSignatory.SignAuthenticatedConnectionBatch:input_type -> api.AuthenticatedConnectionBatchSignatureRequest
+	15, // 21: api.AdsCertSignatory.VerifyAuthenticatedConnection:input_type -> api.AuthenticatedConnectionVerificationRequest
+	11, // 22: api.AdsCertSignatory.SignStream:input_type -> api.SignStreamRequest
+	13, // 23: api.AdsCertSignatory.VerifyStream:input_type -> api.VerifyStreamRequest
+	18, // 24: api.AdsCertSignatoryAdmin.SetDeactivation:input_type -> api.SetDeactivationRequest
+	20, // 25: api.AdsCertSignatoryAdmin.GetDeactivation:input_type -> api.GetDeactivationRequest
+	8,  // 26: api.AdsCertSignatory.SignAuthenticatedConnection:output_type -> api.AuthenticatedConnectionSignatureResponse
+	10, // 27: api.AdsCertSignatory.SignAuthenticatedConnectionBatch:output_type -> api.AuthenticatedConnectionBatchSignatureResponse
+	16, // 28: api.AdsCertSignatory.VerifyAuthenticatedConnection:output_type -> api.AuthenticatedConnectionVerificationResponse
+	12, // 29: api.AdsCertSignatory.SignStream:output_type -> api.SignStreamResponse
+	14, // 30: api.AdsCertSignatory.VerifyStream:output_type -> api.VerifyStreamResponse
+	19, // 31: api.AdsCertSignatoryAdmin.SetDeactivation:output_type -> api.SetDeactivationResponse
+	21, // 32: api.AdsCertSignatoryAdmin.GetDeactivation:output_type -> api.GetDeactivationResponse
+	26, // [26:33] is the sub-list for method output_type
+	19, // [19:26] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
 }
 
 func init() { file_api_adscert_proto_init() }
@@ -902,7 +1923,7 @@ func file_api_adscert_proto_init() {
 			}
 		}
 		file_api_adscert_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AuthenticatedConnectionSignatureRequest); i {
+			switch v := v.(*SignatureVerificationResult); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -914,7 +1935,7 @@ func file_api_adscert_proto_init() {
 			}
 		}
 		file_api_adscert_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AuthenticatedConnectionSignatureResponse); i {
+			switch v := v.(*AuthenticatedConnectionSignatureRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -926,7 +1947,7 @@ func file_api_adscert_proto_init() {
 			}
 		}
 		file_api_adscert_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AuthenticatedConnectionVerificationRequest); i {
+			switch v := v.(*AuthenticatedConnectionSignatureResponse); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -938,6 +1959,90 @@ func file_api_adscert_proto_init() {
 			}
 		}
 		file_api_adscert_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthenticatedConnectionBatchSignatureRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_adscert_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthenticatedConnectionBatchSignatureResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_adscert_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignStreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_adscert_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SignStreamResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_adscert_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyStreamRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_adscert_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyStreamResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_adscert_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AuthenticatedConnectionVerificationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_adscert_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*AuthenticatedConnectionVerificationResponse); i {
 			case 0:
 				return &v.state
@@ -949,6 +2054,66 @@ func file_api_adscert_proto_init() {
 				return nil
 			}
 		}
+		file_api_adscert_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeactivationSettings); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_adscert_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetDeactivationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_adscert_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SetDeactivationResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_adscert_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDeactivationRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_api_adscert_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetDeactivationResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -956,9 +2121,9 @@ func file_api_adscert_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_api_adscert_proto_rawDesc,
 			NumEnums:      3,
-			NumMessages:   7,
+			NumMessages:   19,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_api_adscert_proto_goTypes,
 		DependencyIndexes: file_api_adscert_proto_depIdxs,
@@ -19,7 +19,13 @@ const _ = grpc.SupportPackageIsVersion7
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type AdsCertSignatoryClient interface {
 	SignAuthenticatedConnection(ctx context.Context, in *AuthenticatedConnectionSignatureRequest, opts ...grpc.CallOption) (*AuthenticatedConnectionSignatureResponse, error)
+	SignAuthenticatedConnectionBatch(ctx context.Context, in *AuthenticatedConnectionBatchSignatureRequest, opts ...grpc.CallOption) (*AuthenticatedConnectionBatchSignatureResponse, error)
 	VerifyAuthenticatedConnection(ctx context.Context, in *AuthenticatedConnectionVerificationRequest, opts ...grpc.CallOption) (*AuthenticatedConnectionVerificationResponse, error)
+	// SignStream and VerifyStream serve many requests over one long-lived
+	// stream, avoiding per-call overhead at high request rates.  Requests are
+	// processed concurrently and responses sent as they complete.
+	SignStream(ctx context.Context, opts ...grpc.CallOption) (AdsCertSignatory_SignStreamClient, error)
+	VerifyStream(ctx context.Context, opts ...grpc.CallOption) (AdsCertSignatory_VerifyStreamClient, error)
 }
 
 type adsCertSignatoryClient struct {
@@ -39,6 +45,15 @@ func (c *adsCertSignatoryClient) SignAuthenticatedConnection(ctx context.Context
 	return out, nil
 }
 
+func (c *adsCertSignatoryClient) SignAuthenticatedConnectionBatch(ctx context.Context, in *AuthenticatedConnectionBatchSignatureRequest, opts ...grpc.CallOption) (*AuthenticatedConnectionBatchSignatureResponse, error) {
+	out := new(AuthenticatedConnectionBatchSignatureResponse)
+	err := c.cc.Invoke(ctx, "/api.AdsCertSignatory/SignAuthenticatedConnectionBatch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *adsCertSignatoryClient) VerifyAuthenticatedConnection(ctx context.Context, in *AuthenticatedConnectionVerificationRequest, opts ...grpc.CallOption) (*AuthenticatedConnectionVerificationResponse, error) {
 	out := new(AuthenticatedConnectionVerificationResponse)
 	err := c.cc.Invoke(ctx, "/api.AdsCertSignatory/VerifyAuthenticatedConnection", in, out, opts...)
@@ -48,12 +63,80 @@ func (c *adsCertSignatoryClient) VerifyAuthenticatedConnection(ctx context.Conte
 	return out, nil
 }
 
+func (c *adsCertSignatoryClient) SignStream(ctx context.Context, opts ...grpc.CallOption) (AdsCertSignatory_SignStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AdsCertSignatory_ServiceDesc.Streams[0], "/api.AdsCertSignatory/SignStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &adsCertSignatorySignStreamClient{stream}
+	return x, nil
+}
+
+type AdsCertSignatory_SignStreamClient interface {
+	Send(*SignStreamRequest) error
+	Recv() (*SignStreamResponse, error)
+	grpc.ClientStream
+}
+
+type adsCertSignatorySignStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *adsCertSignatorySignStreamClient) Send(m *SignStreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *adsCertSignatorySignStreamClient) Recv() (*SignStreamResponse, error) {
+	m := new(SignStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *adsCertSignatoryClient) VerifyStream(ctx context.Context, opts ...grpc.CallOption) (AdsCertSignatory_VerifyStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &AdsCertSignatory_ServiceDesc.Streams[1], "/api.AdsCertSignatory/VerifyStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &adsCertSignatoryVerifyStreamClient{stream}
+	return x, nil
+}
+
+type AdsCertSignatory_VerifyStreamClient interface {
+	Send(*VerifyStreamRequest) error
+	Recv() (*VerifyStreamResponse, error)
+	grpc.ClientStream
+}
+
+type adsCertSignatoryVerifyStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *adsCertSignatoryVerifyStreamClient) Send(m *VerifyStreamRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *adsCertSignatoryVerifyStreamClient) Recv() (*VerifyStreamResponse, error) {
+	m := new(VerifyStreamResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // AdsCertSignatoryServer is the server API for AdsCertSignatory service.
 // All implementations must embed UnimplementedAdsCertSignatoryServer
 // for forward compatibility
 type AdsCertSignatoryServer interface {
 	SignAuthenticatedConnection(context.Context, *AuthenticatedConnectionSignatureRequest) (*AuthenticatedConnectionSignatureResponse, error)
+	SignAuthenticatedConnectionBatch(context.Context, *AuthenticatedConnectionBatchSignatureRequest) (*AuthenticatedConnectionBatchSignatureResponse, error)
 	VerifyAuthenticatedConnection(context.Context, *AuthenticatedConnectionVerificationRequest) (*AuthenticatedConnectionVerificationResponse, error)
+	// SignStream and VerifyStream serve many requests over one long-lived
+	// stream, avoiding per-call overhead at high request rates.  Requests are
+	// processed concurrently and responses sent as they complete.
+	SignStream(AdsCertSignatory_SignStreamServer) error
+	VerifyStream(AdsCertSignatory_VerifyStreamServer) error
 	mustEmbedUnimplementedAdsCertSignatoryServer()
 }
 
@@ -64,9 +147,18 @@ type UnimplementedAdsCertSignatoryServer struct {
 func (UnimplementedAdsCertSignatoryServer) SignAuthenticatedConnection(context.Context, *AuthenticatedConnectionSignatureRequest) (*AuthenticatedConnectionSignatureResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SignAuthenticatedConnection not implemented")
 }
+func (UnimplementedAdsCertSignatoryServer) SignAuthenticatedConnectionBatch(context.Context, *AuthenticatedConnectionBatchSignatureRequest) (*AuthenticatedConnectionBatchSignatureResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SignAuthenticatedConnectionBatch not implemented")
+}
 func (UnimplementedAdsCertSignatoryServer) VerifyAuthenticatedConnection(context.Context, *AuthenticatedConnectionVerificationRequest) (*AuthenticatedConnectionVerificationResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method VerifyAuthenticatedConnection not implemented")
 }
+func (UnimplementedAdsCertSignatoryServer) SignStream(AdsCertSignatory_SignStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method SignStream not implemented")
+}
+func (UnimplementedAdsCertSignatoryServer) VerifyStream(AdsCertSignatory_VerifyStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method VerifyStream not implemented")
+}
 func (UnimplementedAdsCertSignatoryServer) mustEmbedUnimplementedAdsCertSignatoryServer() {}
 
 // UnsafeAdsCertSignatoryServer may be embedded to opt out of forward compatibility for this service.
@@ -98,6 +190,24 @@ func _AdsCertSignatory_SignAuthenticatedConnection_Handler(srv interface{}, ctx
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AdsCertSignatory_SignAuthenticatedConnectionBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthenticatedConnectionBatchSignatureRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdsCertSignatoryServer).SignAuthenticatedConnectionBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.AdsCertSignatory/SignAuthenticatedConnectionBatch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdsCertSignatoryServer).SignAuthenticatedConnectionBatch(ctx, req.(*AuthenticatedConnectionBatchSignatureRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AdsCertSignatory_VerifyAuthenticatedConnection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(AuthenticatedConnectionVerificationRequest)
 	if err := dec(in); err != nil {
@@ -116,6 +226,58 @@ func _AdsCertSignatory_VerifyAuthenticatedConnection_Handler(srv interface{}, ct
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AdsCertSignatory_SignStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AdsCertSignatoryServer).SignStream(&adsCertSignatorySignStreamServer{stream})
+}
+
+type AdsCertSignatory_SignStreamServer interface {
+	Send(*SignStreamResponse) error
+	Recv() (*SignStreamRequest, error)
+	grpc.ServerStream
+}
+
+type adsCertSignatorySignStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *adsCertSignatorySignStreamServer) Send(m *SignStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *adsCertSignatorySignStreamServer) Recv() (*SignStreamRequest, error) {
+	m := new(SignStreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _AdsCertSignatory_VerifyStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(AdsCertSignatoryServer).VerifyStream(&adsCertSignatoryVerifyStreamServer{stream})
+}
+
+type AdsCertSignatory_VerifyStreamServer interface {
+	Send(*VerifyStreamResponse) error
+	Recv() (*VerifyStreamRequest, error)
+	grpc.ServerStream
+}
+
+type adsCertSignatoryVerifyStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *adsCertSignatoryVerifyStreamServer) Send(m *VerifyStreamResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *adsCertSignatoryVerifyStreamServer) Recv() (*VerifyStreamRequest, error) {
+	m := new(VerifyStreamRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // AdsCertSignatory_ServiceDesc is the grpc.ServiceDesc for AdsCertSignatory service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -127,11 +289,150 @@ var AdsCertSignatory_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SignAuthenticatedConnection",
 			Handler:    _AdsCertSignatory_SignAuthenticatedConnection_Handler,
 		},
+		{
+			MethodName: "SignAuthenticatedConnectionBatch",
+			Handler:    _AdsCertSignatory_SignAuthenticatedConnectionBatch_Handler,
+		},
 		{
 			MethodName: "VerifyAuthenticatedConnection",
 			Handler:    _AdsCertSignatory_VerifyAuthenticatedConnection_Handler,
 		},
 	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SignStream",
+			Handler:       _AdsCertSignatory_SignStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "VerifyStream",
+			Handler:       _AdsCertSignatory_VerifyStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "api/adscert.proto",
+}
+
+// AdsCertSignatoryAdminClient is the client API for AdsCertSignatoryAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AdsCertSignatoryAdminClient interface {
+	SetDeactivation(ctx context.Context, in *SetDeactivationRequest, opts ...grpc.CallOption) (*SetDeactivationResponse, error)
+	GetDeactivation(ctx context.Context, in *GetDeactivationRequest, opts ...grpc.CallOption) (*GetDeactivationResponse, error)
+}
+
+type adsCertSignatoryAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdsCertSignatoryAdminClient(cc grpc.ClientConnInterface) AdsCertSignatoryAdminClient {
+	return &adsCertSignatoryAdminClient{cc}
+}
+
+func (c *adsCertSignatoryAdminClient) SetDeactivation(ctx context.Context, in *SetDeactivationRequest, opts ...grpc.CallOption) (*SetDeactivationResponse, error) {
+	out := new(SetDeactivationResponse)
+	err := c.cc.Invoke(ctx, "/api.AdsCertSignatoryAdmin/SetDeactivation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adsCertSignatoryAdminClient) GetDeactivation(ctx context.Context, in *GetDeactivationRequest, opts ...grpc.CallOption) (*GetDeactivationResponse, error) {
+	out := new(GetDeactivationResponse)
+	err := c.cc.Invoke(ctx, "/api.AdsCertSignatoryAdmin/GetDeactivation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdsCertSignatoryAdminServer is the server API for AdsCertSignatoryAdmin service.
+// All implementations must embed UnimplementedAdsCertSignatoryAdminServer
+// for forward compatibility
+type AdsCertSignatoryAdminServer interface {
+	SetDeactivation(context.Context, *SetDeactivationRequest) (*SetDeactivationResponse, error)
+	GetDeactivation(context.Context, *GetDeactivationRequest) (*GetDeactivationResponse, error)
+	mustEmbedUnimplementedAdsCertSignatoryAdminServer()
+}
+
+// UnimplementedAdsCertSignatoryAdminServer must be embedded to have forward compatible implementations.
+type UnimplementedAdsCertSignatoryAdminServer struct {
+}
+
+func (UnimplementedAdsCertSignatoryAdminServer) SetDeactivation(context.Context, *SetDeactivationRequest) (*SetDeactivationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDeactivation not implemented")
+}
+func (UnimplementedAdsCertSignatoryAdminServer) GetDeactivation(context.Context, *GetDeactivationRequest) (*GetDeactivationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeactivation not implemented")
+}
+func (UnimplementedAdsCertSignatoryAdminServer) mustEmbedUnimplementedAdsCertSignatoryAdminServer() {}
+
+// UnsafeAdsCertSignatoryAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdsCertSignatoryAdminServer will
+// result in compilation errors.
+type UnsafeAdsCertSignatoryAdminServer interface {
+	mustEmbedUnimplementedAdsCertSignatoryAdminServer()
+}
+
+func RegisterAdsCertSignatoryAdminServer(s grpc.ServiceRegistrar, srv AdsCertSignatoryAdminServer) {
+	s.RegisterService(&AdsCertSignatoryAdmin_ServiceDesc, srv)
+}
+
+func _AdsCertSignatoryAdmin_SetDeactivation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDeactivationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdsCertSignatoryAdminServer).SetDeactivation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.AdsCertSignatoryAdmin/SetDeactivation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdsCertSignatoryAdminServer).SetDeactivation(ctx, req.(*SetDeactivationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdsCertSignatoryAdmin_GetDeactivation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeactivationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdsCertSignatoryAdminServer).GetDeactivation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/api.AdsCertSignatoryAdmin/GetDeactivation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdsCertSignatoryAdminServer).GetDeactivation(ctx, req.(*GetDeactivationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdsCertSignatoryAdmin_ServiceDesc is the grpc.ServiceDesc for AdsCertSignatoryAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdsCertSignatoryAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "api.AdsCertSignatoryAdmin",
+	HandlerType: (*AdsCertSignatoryAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SetDeactivation",
+			Handler:    _AdsCertSignatoryAdmin_SetDeactivation_Handler,
+		},
+		{
+			MethodName: "GetDeactivation",
+			Handler:    _AdsCertSignatoryAdmin_GetDeactivation_Handler,
+		},
+	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/adscert.proto",
 }
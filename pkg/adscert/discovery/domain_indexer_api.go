@@ -1,8 +1,26 @@
 package discovery
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type DomainIndexer interface {
 	LookupIdentitiesForDomain(domain string) ([]DomainInfo, error)
+	LookupIdentitiesForDomainContext(ctx context.Context, domain string) ([]DomainInfo, error)
 	GetLastRun() time.Time
 }
+
+// PrivateKeyRole describes how the domain indexer uses one of the local
+// private keys.
+type PrivateKeyRole string
+
+const (
+	// PrivateKeyRolePrimary keys are used for signing.  Each origin should
+	// have exactly one.
+	PrivateKeyRolePrimary PrivateKeyRole = "primary"
+
+	// PrivateKeyRoleSecondary keys are used only to verify signatures from
+	// counterparties which haven't yet picked up the primary key.
+	PrivateKeyRoleSecondary PrivateKeyRole = "secondary"
+)
@@ -3,6 +3,7 @@ package discovery
 import (
 	"context"
 	"errors"
+	"sort"
 	"sync"
 	"time"
 
@@ -11,26 +12,109 @@ import (
 	"github.com/IABTechLab/adscert/internal/utils"
 	"github.com/IABTechLab/adscert/pkg/adscert/logger"
 	"github.com/IABTechLab/adscert/pkg/adscert/metrics"
+	"golang.org/x/sync/singleflight"
 )
 
 func NewDefaultDomainIndexer(dnsResolver DNSResolver, domainStore DomainStore, domainCheckInterval time.Duration, domainRenewalInterval time.Duration, base64PrivateKeys []string) DomainIndexer {
+	return NewDomainIndexerWithOptions(dnsResolver, domainStore, base64PrivateKeys, &DomainIndexerOptions{
+		DomainCheckInterval:   domainCheckInterval,
+		DomainRenewalInterval: domainRenewalInterval,
+	})
+}
+
+// DomainIndexerOptions controls how often domains are checked and refreshed.
+// Zero values select the defaults.
+type DomainIndexerOptions struct {
+	// DomainCheckInterval is how often the indexer looks for domains which
+	// are due for a refresh.
+	DomainCheckInterval time.Duration
+
+	// DomainRenewalInterval is used as the refresh interval when the DNS
+	// resolver can't report record TTLs.
+	DomainRenewalInterval time.Duration
+
+	// MinRefreshInterval and MaxRefreshInterval clamp the record TTL used to
+	// schedule the next refresh of a domain.
+	MinRefreshInterval time.Duration
+	MaxRefreshInterval time.Duration
+
+	// NegativeCacheInterval is the delay before re-checking a domain which
+	// has no ads.cert records or has unparseable records.  The delay doubles
+	// with each consecutive failure, up to MaxNegativeCacheInterval.
+	NegativeCacheInterval    time.Duration
+	MaxNegativeCacheInterval time.Duration
+
+	// OriginPrivateKeys holds the private keys of additional origin
+	// callsigns, keyed by callsign.  Shared secrets for these origins are
+	// available from DomainInfo.GetSharedSecretForOrigin.
+	OriginPrivateKeys map[string][]string
+
+	// PrivateKeyRoles assigns roles to the local private keys, keyed by key
+	// alias (the first six characters of the base64 public key).  Each origin
+	// signs with its primary key.  When an origin has no primary key, the key
+	// with the greatest alias is used.
+	PrivateKeyRoles map[string]PrivateKeyRole
+
+	// SynchronousFirstLookup makes LookupIdentitiesForDomainContext resolve
+	// a domain it hasn't checked before inline, rather than returning no
+	// identities until the next update sweep.
+	SynchronousFirstLookup bool
+}
+
+func NewDomainIndexerWithOptions(dnsResolver DNSResolver, domainStore DomainStore, base64PrivateKeys []string, options *DomainIndexerOptions) DomainIndexer {
+	if options == nil {
+		options = &DomainIndexerOptions{}
+	}
 
 	// check domains every 30 seconds by default
+	domainCheckInterval := options.DomainCheckInterval
 	if domainCheckInterval <= 0 {
 		domainCheckInterval = 30 * time.Second
 	}
 
 	// renew domains after 5 minutes by default
+	domainRenewalInterval := options.DomainRenewalInterval
 	if domainRenewalInterval <= 0 {
 		domainRenewalInterval = 300 * time.Second
 	}
 
+	// honor record TTLs between 30 seconds and 1 hour by default
+	minRefreshInterval := options.MinRefreshInterval
+	if minRefreshInterval <= 0 {
+		minRefreshInterval = 30 * time.Second
+	}
+	maxRefreshInterval := options.MaxRefreshInterval
+	if maxRefreshInterval <= 0 {
+		maxRefreshInterval = time.Hour
+	}
+	if maxRefreshInterval < minRefreshInterval {
+		maxRefreshInterval = minRefreshInterval
+	}
+
+	// back off from 30 seconds up to 1 hour for missing or broken records by default
+	negativeCacheInterval := options.NegativeCacheInterval
+	if negativeCacheInterval <= 0 {
+		negativeCacheInterval = 30 * time.Second
+	}
+	maxNegativeCacheInterval := options.MaxNegativeCacheInterval
+	if maxNegativeCacheInterval <= 0 {
+		maxNegativeCacheInterval = time.Hour
+	}
+	if maxNegativeCacheInterval < negativeCacheInterval {
+		maxNegativeCacheInterval = negativeCacheInterval
+	}
+
 	di := &defaultDomainIndexer{
-		ticker:                time.NewTicker(domainCheckInterval),
-		wakeUp:                make(chan struct{}, 1),
-		domainRenewalInterval: domainRenewalInterval,
-		dnsResolver:           dnsResolver,
-		domainStore:           domainStore,
+		ticker:                   time.NewTicker(domainCheckInterval),
+		wakeUp:                   make(chan struct{}, 1),
+		domainRenewalInterval:    domainRenewalInterval,
+		minRefreshInterval:       minRefreshInterval,
+		maxRefreshInterval:       maxRefreshInterval,
+		negativeCacheInterval:    negativeCacheInterval,
+		maxNegativeCacheInterval: maxNegativeCacheInterval,
+		dnsResolver:              dnsResolver,
+		domainStore:              domainStore,
+		synchronousFirstLookup:   options.SynchronousFirstLookup,
 	}
 
 	myPrivateKeys, err := privateKeysToKeyMap(base64PrivateKeys)
@@ -38,16 +122,24 @@ func NewDefaultDomainIndexer(dnsResolver DNSResolver, domainStore DomainStore, d
 		logger.Fatalf("Error parsing private keys: %v", err)
 	}
 	di.myPrivateKeys = myPrivateKeys
+	di.currentPrivateKey = selectCurrentPrivateKey(myPrivateKeys, options.PrivateKeyRoles)
 
-	for _, privateKey := range di.myPrivateKeys {
-		// since iterating over a map is non-deterministic, we can make sure to set the key
-		// either if it is not already set or it is alphabetically less than current key at the index when
-		// iterating over the private keys map.
-		if di.currentPrivateKey == "" || di.currentPrivateKey < privateKey.alias {
-			di.currentPrivateKey = privateKey.alias
+	// keys for additional origins share the same pool, so shared secrets are
+	// calculated for every origin, and each origin tracks its own current key.
+	di.originPrivateKeys = map[string]keyAlias{}
+	for origin, originKeys := range options.OriginPrivateKeys {
+		originKeyMap, err := privateKeysToKeyMap(originKeys)
+		if err != nil {
+			logger.Fatalf("Error parsing private keys for origin %s: %v", origin, err)
+		}
+		for alias, privateKey := range originKeyMap {
+			di.myPrivateKeys[alias] = privateKey
 		}
+		di.originPrivateKeys[origin] = selectCurrentPrivateKey(originKeyMap, options.PrivateKeyRoles)
 	}
 
+	di.ctx, di.cancel = context.WithCancel(context.Background())
+	di.warmStart(di.ctx)
 	di.startAutoUpdate()
 	di.UpdateNow()
 	return di
@@ -55,18 +147,28 @@ func NewDefaultDomainIndexer(dnsResolver DNSResolver, domainStore DomainStore, d
 
 type defaultDomainIndexer struct {
 	ticker                *time.Ticker
+	ctx                   context.Context
 	cancel                context.CancelFunc
 	wakeUp                chan struct{}
 	domainRenewalInterval time.Duration
 
+	minRefreshInterval       time.Duration
+	maxRefreshInterval       time.Duration
+	negativeCacheInterval    time.Duration
+	maxNegativeCacheInterval time.Duration
+
 	lastRun     time.Time
 	lastRunLock sync.RWMutex
 
 	myPrivateKeys     keyMap
 	currentPrivateKey keyAlias
+	originPrivateKeys map[string]keyAlias // current private key for each additional origin
 
 	dnsResolver DNSResolver
 	domainStore DomainStore
+
+	synchronousFirstLookup bool
+	inflight               singleflight.Group
 }
 
 func (di *defaultDomainIndexer) GetLastRun() time.Time {
@@ -83,17 +185,30 @@ func (di *defaultDomainIndexer) updateLastRun() {
 }
 
 func (di *defaultDomainIndexer) LookupIdentitiesForDomain(invokingDomain string) ([]DomainInfo, error) {
+	return di.LookupIdentitiesForDomainContext(context.Background(), invokingDomain)
+}
+
+// LookupIdentitiesForDomainContext returns the identity domains for an invoking
+// domain.  When synchronous first lookups are enabled, a domain which has never
+// been checked is resolved inline, waiting until ctx is done at the latest.
+// Otherwise the domain is queued for the next update sweep.
+func (di *defaultDomainIndexer) LookupIdentitiesForDomainContext(ctx context.Context, invokingDomain string) ([]DomainInfo, error) {
 
-	domainInfo, ok, err := di.domainStore.LookupDomainInfo(context.Background(), invokingDomain)
+	domainInfo, ok, err := di.domainStore.LookupDomainInfo(ctx, invokingDomain)
 	if err != nil {
 		// lookup operation had a problem (depends on domain store implementation, for example network failure)
 		// return empty list
 		return []DomainInfo{}, err
 	}
+	if di.synchronousFirstLookup && (!ok || domainInfo.nextRefreshTime.IsZero()) {
+		domainInfo, ok = di.resolveDomainInline(ctx, invokingDomain)
+	}
 	if !ok {
 		// domain was not found in domain store
 		// store a new entry so it can be processed and queue an update
-		di.domainStore.StoreDomainInfo(context.Background(), initializeDomainInfo(invokingDomain))
+		if _, stored, _ := di.domainStore.LookupDomainInfo(ctx, invokingDomain); !stored {
+			di.domainStore.StoreDomainInfo(ctx, initializeDomainInfo(invokingDomain))
+		}
 		di.UpdateNow()
 		// return empty list
 		return []DomainInfo{}, nil
@@ -110,7 +225,16 @@ func (di *defaultDomainIndexer) LookupIdentitiesForDomain(invokingDomain string)
 	if len(domainInfo.IdentityDomains) > 0 {
 		var domains []DomainInfo
 		for _, d := range domainInfo.IdentityDomains {
-			if info, ok, err := di.domainStore.LookupDomainInfo(context.Background(), d); err == nil && ok {
+			info, ok, err := di.domainStore.LookupDomainInfo(ctx, d)
+			if err != nil {
+				continue
+			}
+			// the invoking domain may have been checked moments ago while its
+			// identity domains are still being fetched, so join that fetch
+			if di.synchronousFirstLookup && (!ok || info.nextRefreshTime.IsZero()) {
+				info, ok = di.resolveDomainInline(ctx, d)
+			}
+			if ok {
 				domains = append(domains, info)
 			}
 		}
@@ -121,9 +245,64 @@ func (di *defaultDomainIndexer) LookupIdentitiesForDomain(invokingDomain string)
 	return nil, errors.New("failed to lookup identity domains for invoking domain")
 }
 
+// resolveDomainInline checks a domain and any identity domains it delegates to
+// which haven't been checked yet.  Concurrent callers asking for the same domain
+// share a single fetch.  The fetch runs under the indexer's own context, so a
+// caller giving up when ctx is done doesn't cancel it for the others.
+func (di *defaultDomainIndexer) resolveDomainInline(ctx context.Context, domain string) (DomainInfo, bool) {
+	resultChan := di.inflight.DoChan(domain, func() (interface{}, error) {
+		domainInfo := di.refreshDomain(di.ctx, domain)
+		for _, identityDomain := range domainInfo.IdentityDomains {
+			if identityDomain == domain {
+				continue
+			}
+			if info, ok, _ := di.domainStore.LookupDomainInfo(di.ctx, identityDomain); !ok || info.nextRefreshTime.IsZero() {
+				di.inflight.Do(identityDomain, func() (interface{}, error) {
+					return di.refreshDomain(di.ctx, identityDomain), nil
+				})
+			}
+		}
+		return domainInfo, nil
+	})
+
+	select {
+	case result := <-resultChan:
+		return result.Val.(DomainInfo), true
+	case <-ctx.Done():
+		logger.Warningf("gave up waiting for lookup of domain %s: %v", domain, ctx.Err())
+		return DomainInfo{}, false
+	}
+}
+
+// refreshDomain checks the records for a single domain and stores the result.
+func (di *defaultDomainIndexer) refreshDomain(ctx context.Context, domain string) DomainInfo {
+	currentDomainInfo, ok, err := di.domainStore.LookupDomainInfo(ctx, domain)
+	if err != nil || !ok {
+		currentDomainInfo = initializeDomainInfo(domain)
+	}
+
+	// The checks only record errors, so that an error found by one, such as
+	// the policy record failing DNSSEC validation, isn't cleared when the
+	// other succeeds.  Without errors the domain is usable once its keys are
+	// found, and otherwise keeps its previous status.
+	previousStatus := currentDomainInfo.domainStatus
+	currentDomainInfo.domainStatus = DomainStatusUnspecified
+	policyResult := di.checkDomainForPolicyRecords(ctx, &currentDomainInfo)
+	keyResult := di.checkDomainForKeyRecords(ctx, &currentDomainInfo)
+	if currentDomainInfo.domainStatus == DomainStatusUnspecified {
+		if keyResult.outcome == lookupFound {
+			currentDomainInfo.domainStatus = DomainStatusOK
+		} else {
+			currentDomainInfo.domainStatus = previousStatus
+		}
+	}
+	di.scheduleNextRefresh(&currentDomainInfo, time.Now(), policyResult, keyResult)
+	di.domainStore.StoreDomainInfo(ctx, currentDomainInfo)
+	return currentDomainInfo
+}
+
 func (di *defaultDomainIndexer) startAutoUpdate() {
-	var ctx context.Context
-	ctx, di.cancel = context.WithCancel(context.Background())
+	ctx := di.ctx
 	go func() {
 		for {
 			select {
@@ -155,11 +334,11 @@ func (di *defaultDomainIndexer) performUpdateSweep(ctx context.Context) {
 		if err != nil {
 			logger.Infof("unable to retrieve domain info for domain %s, skipping update until next loop", domain)
 
-		} else if currentDomainInfo.lastUpdateTime.Before(time.Now().Add(di.domainRenewalInterval)) {
+		} else if !time.Now().Before(currentDomainInfo.nextRefreshTime) {
 			logger.Infof("Trying to do an update for domain %s", domain)
-			di.checkDomainForPolicyRecords(ctx, &currentDomainInfo)
-			di.checkDomainForKeyRecords(ctx, &currentDomainInfo)
-			di.domainStore.StoreDomainInfo(ctx, currentDomainInfo)
+			di.inflight.Do(domain, func() (interface{}, error) {
+				return di.refreshDomain(ctx, domain), nil
+			})
 
 		} else {
 			logger.Infof("skipping update for domain %s which is already up to date.", domain)
@@ -167,27 +346,29 @@ func (di *defaultDomainIndexer) performUpdateSweep(ctx context.Context) {
 	}
 }
 
-func (di *defaultDomainIndexer) checkDomainForPolicyRecords(ctx context.Context, currentDomainInfo *DomainInfo) {
+func (di *defaultDomainIndexer) checkDomainForPolicyRecords(ctx context.Context, currentDomainInfo *DomainInfo) lookupResult {
 
 	startTime := time.Now()
 	baseSubdomain := "_adscert." + currentDomainInfo.Domain
-	baseSubdomainRecords, err := di.dnsResolver.LookupTXT(ctx, baseSubdomain)
+	baseSubdomainRecords, ttl, err := di.dnsResolver.LookupTXTWithTTL(ctx, baseSubdomain)
+	result := lookupResult{ttl: ttl, outcome: lookupFound}
 
 	if err != nil {
 		logger.Warningf("No record found for %s in %v: %v", baseSubdomain, time.Since(startTime), err)
-		return
+		recordLookupError(currentDomainInfo, err)
+		return lookupResult{outcome: lookupOutcomeForError(err)}
 
 	} else {
 		logger.Infof("Found records for %s in %v: %v", baseSubdomain, time.Since(startTime), baseSubdomainRecords)
 		metrics.RecordDNSLookupTime(time.Since(startTime))
 
-		if foundDomains, parseError := parsePolicyRecords(baseSubdomain, baseSubdomainRecords); parseError {
+		if foundDomains, parseError := parsePolicyRecords(currentDomainInfo.Domain, baseSubdomain, baseSubdomainRecords); parseError {
 			currentDomainInfo.domainStatus = DomainStatusADPFParseError
+			result.outcome = lookupParseError
 		} else {
 			// replace current domain info with new identity domains (and filter to keep uniques)
 			currentDomainInfo.IdentityDomains = foundDomains
 			currentDomainInfo.IdentityDomains = utils.MergeUniques(currentDomainInfo.IdentityDomains)
-			currentDomainInfo.domainStatus = DomainStatusOK
 		}
 	}
 
@@ -200,33 +381,133 @@ func (di *defaultDomainIndexer) checkDomainForPolicyRecords(ctx context.Context,
 	}
 
 	currentDomainInfo.lastUpdateTime = time.Now()
+	return result
 }
 
-func (di *defaultDomainIndexer) checkDomainForKeyRecords(ctx context.Context, currentDomainInfo *DomainInfo) {
+func (di *defaultDomainIndexer) checkDomainForKeyRecords(ctx context.Context, currentDomainInfo *DomainInfo) lookupResult {
 
 	startTime := time.Now()
 	deliverySubdomain := "_delivery._adscert." + currentDomainInfo.Domain
-	deliverySubdomainRecords, err := di.dnsResolver.LookupTXT(ctx, deliverySubdomain)
+	deliverySubdomainRecords, ttl, err := di.dnsResolver.LookupTXTWithTTL(ctx, deliverySubdomain)
+	result := lookupResult{ttl: ttl, outcome: lookupFound}
 
 	if err != nil {
 		logger.Warningf("No record found for %s in %v: %v", deliverySubdomain, time.Since(startTime), err)
-		return
+		recordLookupError(currentDomainInfo, err)
+		return lookupResult{outcome: lookupOutcomeForError(err)}
 
 	} else {
 		logger.Infof("Found records for %s in %v: %v", deliverySubdomain, time.Since(startTime), deliverySubdomainRecords)
 		metrics.RecordDNSLookupTime(time.Since(startTime))
 
-		if foundKeys, parseError := parseKeyRecords(deliverySubdomain, deliverySubdomainRecords); parseError {
+		if foundKeys, primaryKey, parseError := parseKeyRecords(currentDomainInfo.Domain, deliverySubdomain, deliverySubdomainRecords); parseError {
 			currentDomainInfo.domainStatus = DomainStatusADCRTDParseError
+			result.outcome = lookupParseError
 		} else {
 			// replace current domain info with new public keys
 			currentDomainInfo.allPublicKeys = asKeyMap(formats.AdsCertKeys{PublicKeys: foundKeys})
-			currentDomainInfo.currentPublicKeyId = keyAlias(foundKeys[0].KeyAlias)
-			currentDomainInfo.domainStatus = DomainStatusOK
+			currentDomainInfo.currentPublicKeyId = primaryKey
 		}
 	}
 
-	// create shared secrets for each private key + public key combination
+	di.calculateSharedSecrets(currentDomainInfo)
+	currentDomainInfo.lastUpdateTime = time.Now()
+	return result
+}
+
+// lookupOutcome classifies the result of looking up one of a domain's ads.cert records.
+type lookupOutcome int
+
+const (
+	lookupFound lookupOutcome = iota
+	lookupNotFound
+	lookupParseError
+	lookupFailed
+)
+
+type lookupResult struct {
+	ttl     time.Duration
+	outcome lookupOutcome
+}
+
+func lookupOutcomeForError(err error) lookupOutcome {
+	if isRecordNotFound(err) {
+		return lookupNotFound
+	}
+	return lookupFailed
+}
+
+// scheduleNextRefresh decides when a domain should be checked again.  Domains
+// with valid records are refreshed when the shortest record TTL expires,
+// clamped to the configured bounds.  Domains with no records at all, or with
+// records that can't be parsed, are cached negatively and re-checked with
+// exponential backoff.  Other lookup failures are retried after the minimum
+// refresh interval.
+func (di *defaultDomainIndexer) scheduleNextRefresh(currentDomainInfo *DomainInfo, now time.Time, results ...lookupResult) {
+	var found, parseError, failed bool
+	var ttl time.Duration
+	for _, result := range results {
+		switch result.outcome {
+		case lookupFound:
+			found = true
+			if result.ttl > 0 && (ttl == 0 || result.ttl < ttl) {
+				ttl = result.ttl
+			}
+		case lookupParseError:
+			parseError = true
+		case lookupFailed:
+			failed = true
+		}
+	}
+
+	switch {
+	case parseError || (!found && !failed):
+		currentDomainInfo.consecutiveFailures++
+		currentDomainInfo.nextRefreshTime = now.Add(di.negativeCacheDelay(currentDomainInfo.consecutiveFailures))
+
+	case found:
+		currentDomainInfo.consecutiveFailures = 0
+		if ttl == 0 {
+			ttl = di.domainRenewalInterval
+		}
+		if ttl < di.minRefreshInterval {
+			ttl = di.minRefreshInterval
+		}
+		if ttl > di.maxRefreshInterval {
+			ttl = di.maxRefreshInterval
+		}
+		currentDomainInfo.nextRefreshTime = now.Add(ttl)
+
+	default:
+		currentDomainInfo.nextRefreshTime = now.Add(di.minRefreshInterval)
+	}
+}
+
+// negativeCacheDelay doubles the negative cache interval for each consecutive
+// failure, capped at the maximum interval.
+func (di *defaultDomainIndexer) negativeCacheDelay(consecutiveFailures int) time.Duration {
+	delay := di.negativeCacheInterval
+	for i := 1; i < consecutiveFailures && delay < di.maxNegativeCacheInterval; i++ {
+		delay *= 2
+	}
+	if delay > di.maxNegativeCacheInterval {
+		delay = di.maxNegativeCacheInterval
+	}
+	return delay
+}
+
+// calculateSharedSecrets creates shared secrets for each private key + public key combination
+// that doesn't have one yet, drops those for keys which are no longer published
+// or held, and selects the current shared secret.
+func (di *defaultDomainIndexer) calculateSharedSecrets(currentDomainInfo *DomainInfo) {
+	var err error
+	for pairAlias := range currentDomainInfo.allSharedSecrets {
+		_, hasPrivateKey := di.myPrivateKeys[pairAlias.originKeyAlias]
+		_, hasPublicKey := currentDomainInfo.allPublicKeys[pairAlias.remoteKeyAlias]
+		if !hasPrivateKey || !hasPublicKey {
+			delete(currentDomainInfo.allSharedSecrets, pairAlias)
+		}
+	}
 	for _, myKey := range di.myPrivateKeys {
 		for _, theirKey := range currentDomainInfo.allPublicKeys {
 			keyPairAlias := newKeyPairAlias(myKey.alias, theirKey.alias)
@@ -241,10 +522,70 @@ func (di *defaultDomainIndexer) checkDomainForKeyRecords(ctx context.Context, cu
 	}
 
 	currentDomainInfo.currentSharedSecretId = newKeyPairAlias(di.currentPrivateKey, currentDomainInfo.currentPublicKeyId)
-	currentDomainInfo.lastUpdateTime = time.Now()
+	if len(di.originPrivateKeys) > 0 {
+		originSharedSecretIds := map[string]keyPairAlias{}
+		for origin, privateKey := range di.originPrivateKeys {
+			originSharedSecretIds[origin] = newKeyPairAlias(privateKey, currentDomainInfo.currentPublicKeyId)
+		}
+		currentDomainInfo.originSharedSecretIds = originSharedSecretIds
+	}
+}
+
+// selectCurrentPrivateKey picks the key to sign with: the key with the primary
+// role, or when there isn't one, the key with the greatest alias.  Ties are
+// broken by alias too, so the choice doesn't depend on map iteration order.
+func selectCurrentPrivateKey(privateKeys keyMap, roles map[string]PrivateKeyRole) keyAlias {
+	var currentPrivateKey keyAlias
+	var currentIsPrimary bool
+	for _, privateKey := range privateKeys {
+		isPrimary := roles[string(privateKey.alias)] == PrivateKeyRolePrimary
+		if currentPrivateKey == "" || (isPrimary && !currentIsPrimary) ||
+			(isPrimary == currentIsPrimary && currentPrivateKey < privateKey.alias) {
+			currentPrivateKey = privateKey.alias
+			currentIsPrimary = isPrimary
+		}
+	}
+	return currentPrivateKey
+}
+
+// warmStart recomputes the shared secrets for domains which were loaded from a
+// persistent store, so that they can be used before the first DNS refresh.
+// Shared secrets are never persisted, so they are derived again here from the
+// stored public keys and our private keys.
+func (di *defaultDomainIndexer) warmStart(ctx context.Context) {
+	domains, err := di.domainStore.GetAllDomains(ctx)
+	if err != nil {
+		logger.Warningf("unable to list stored domains: %v", err)
+		return
+	}
+
+	for _, domain := range domains {
+		domainInfo, ok, err := di.domainStore.LookupDomainInfo(ctx, domain)
+		if err != nil || !ok || len(domainInfo.allPublicKeys) == 0 {
+			continue
+		}
+		if _, ok := domainInfo.GetSharedSecret(); ok {
+			continue
+		}
+
+		di.calculateSharedSecrets(&domainInfo)
+		if err := di.domainStore.StoreDomainInfo(ctx, domainInfo); err != nil {
+			logger.Warningf("unable to store domain %s: %v", domain, err)
+		}
+	}
+}
+
+// recordLookupError flags the domain when a lookup failed because the response
+// could not be authenticated against the DNSSEC chain of trust.
+func recordLookupError(currentDomainInfo *DomainInfo, err error) {
+	var validationErr *DNSSECValidationError
+	if errors.As(err, &validationErr) {
+		metrics.RecordDNSLookup(currentDomainInfo.Domain, adscerterrors.ErrDNSSECValidation)
+		currentDomainInfo.domainStatus = DomainStatusErrorOnDNSSEC
+	}
 }
 
-func parsePolicyRecords(baseSubdomain string, baseSubdomainRecords []string) (foundDomains []string, parseError bool) {
+func parsePolicyRecords(domain string, baseSubdomain string, baseSubdomainRecords []string) (foundDomains []string, parseError bool) {
 
 	// log warning if there are multiple policy records found because there should only be a single authoritative identity domain
 	// however this is not an error because there may be multiple records during a ownerhsip change
@@ -255,19 +596,24 @@ func parsePolicyRecords(baseSubdomain string, baseSubdomainRecords []string) (fo
 	for _, v := range baseSubdomainRecords {
 		if adsCertPolicy, err := formats.DecodeAdsCertPolicyRecord(v); err != nil {
 			logger.Warningf("Error parsing ads.cert policy record for %s: %v", baseSubdomain, err)
-			metrics.RecordDNSLookup(adscerterrors.ErrDNSDecodePolicy)
+			metrics.RecordDNSLookup(domain, adscerterrors.ErrDNSDecodePolicy)
 			parseError = true
 
 		} else {
 			foundDomains = append(foundDomains, adsCertPolicy.CanonicalCallsignDomain)
-			metrics.RecordDNSLookup(nil)
+			metrics.RecordDNSLookup(domain, nil)
 		}
 	}
 
 	return foundDomains, parseError
 }
 
-func parseKeyRecords(deliverySubdomain string, deliverySubdomainRecords []string) (foundKeys []formats.ParsedPublicKey, parseError bool) {
+// parseKeyRecords collects the keys from every key record found for a domain,
+// along with the counterparty's primary key.  Records are considered in sorted
+// order so that the primary key doesn't depend on the order the DNS server
+// returned them in: it is the key marked primary by the first record to mark
+// one, or else the first key of the first record.
+func parseKeyRecords(domain string, deliverySubdomain string, deliverySubdomainRecords []string) (foundKeys []formats.ParsedPublicKey, primaryKey keyAlias, parseError bool) {
 
 	// log warning if there are multiple key records found
 	// however this is not an error because there may be multiple records as keys are aged out and/or records become large
@@ -275,20 +621,30 @@ func parseKeyRecords(deliverySubdomain string, deliverySubdomainRecords []string
 		logger.Warningf("Found multiple key records for %s: %v", deliverySubdomain, deliverySubdomainRecords)
 	}
 
-	for _, v := range deliverySubdomainRecords {
+	sortedRecords := append([]string(nil), deliverySubdomainRecords...)
+	sort.Strings(sortedRecords)
+
+	var primaryMarked bool
+	for _, v := range sortedRecords {
 		adsCertKeys, err := formats.DecodeAdsCertKeysRecord(v)
 		if err != nil {
 			logger.Warningf("Error parsing ads.cert key record for %s: %v", deliverySubdomain, err)
-			metrics.RecordDNSLookup(adscerterrors.ErrDNSDecodeKeys)
+			metrics.RecordDNSLookup(domain, adscerterrors.ErrDNSDecodeKeys)
 			parseError = true
 
 		} else if len(adsCertKeys.PublicKeys) > 0 {
 			foundKeys = append(foundKeys, adsCertKeys.PublicKeys...)
-			metrics.RecordDNSLookup(nil)
+			if adsCertKeys.PrimaryKeyAlias != "" && !primaryMarked {
+				primaryKey = keyAlias(adsCertKeys.PrimaryKeyAlias)
+				primaryMarked = true
+			} else if primaryKey == "" {
+				primaryKey = keyAlias(adsCertKeys.PublicKeys[0].KeyAlias)
+			}
+			metrics.RecordDNSLookup(domain, nil)
 		}
 	}
 
-	return foundKeys, parseError
+	return foundKeys, primaryKey, parseError
 }
 
 func (di *defaultDomainIndexer) StopAutoUpdate() {
@@ -315,6 +671,7 @@ func initializeDomainInfo(domain string) DomainInfo {
 		currentSharedSecretId: keyPairAlias{},
 		allPublicKeys:         map[keyAlias]*x25519Key{},
 		allSharedSecrets:      keyPairMap{},
+		originSharedSecretIds: map[string]keyPairAlias{},
 		domainStatus:          DomainStatusNotYetChecked,
 		lastUpdateTime:        time.Time{},
 	}
@@ -0,0 +1,363 @@
+package discovery
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestScheduleNextRefresh(t *testing.T) {
+	now := time.Date(2022, 11, 8, 21, 2, 36, 0, time.UTC)
+	di := &defaultDomainIndexer{
+		domainRenewalInterval:    5 * time.Minute,
+		minRefreshInterval:       time.Minute,
+		maxRefreshInterval:       time.Hour,
+		negativeCacheInterval:    30 * time.Second,
+		maxNegativeCacheInterval: 4 * time.Minute,
+	}
+
+	testCases := []struct {
+		desc                string
+		consecutiveFailures int
+		results             []lookupResult
+
+		wantDelay    time.Duration
+		wantFailures int
+	}{
+		{
+			desc:                "shortest TTL of found records",
+			consecutiveFailures: 3,
+			results:             []lookupResult{{ttl: 20 * time.Minute, outcome: lookupFound}, {ttl: 10 * time.Minute, outcome: lookupFound}},
+			wantDelay:           10 * time.Minute,
+		},
+		{
+			desc:      "TTL below minimum",
+			results:   []lookupResult{{ttl: time.Second, outcome: lookupFound}, {outcome: lookupNotFound}},
+			wantDelay: time.Minute,
+		},
+		{
+			desc:      "TTL above maximum",
+			results:   []lookupResult{{ttl: 24 * time.Hour, outcome: lookupFound}},
+			wantDelay: time.Hour,
+		},
+		{
+			desc:      "unknown TTL",
+			results:   []lookupResult{{outcome: lookupFound}},
+			wantDelay: 5 * time.Minute,
+		},
+		{
+			desc:         "first negative lookup",
+			results:      []lookupResult{{outcome: lookupNotFound}, {outcome: lookupNotFound}},
+			wantDelay:    30 * time.Second,
+			wantFailures: 1,
+		},
+		{
+			desc:                "repeated negative lookup",
+			consecutiveFailures: 2,
+			results:             []lookupResult{{outcome: lookupNotFound}, {outcome: lookupNotFound}},
+			wantDelay:           2 * time.Minute,
+			wantFailures:        3,
+		},
+		{
+			desc:                "negative lookup backoff capped",
+			consecutiveFailures: 10,
+			results:             []lookupResult{{ttl: time.Hour, outcome: lookupFound}, {outcome: lookupParseError}},
+			wantDelay:           4 * time.Minute,
+			wantFailures:        11,
+		},
+		{
+			desc:                "transient failure",
+			consecutiveFailures: 2,
+			results:             []lookupResult{{outcome: lookupNotFound}, {outcome: lookupFailed}},
+			wantDelay:           time.Minute,
+			wantFailures:        2,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			domainInfo := initializeDomainInfo(exampleDomainName)
+			domainInfo.consecutiveFailures = tC.consecutiveFailures
+
+			di.scheduleNextRefresh(&domainInfo, now, tC.results...)
+
+			if got := domainInfo.nextRefreshTime.Sub(now); got != tC.wantDelay {
+				t.Errorf("scheduleNextRefresh() %s: got delay %v, want %v", tC.desc, got, tC.wantDelay)
+			}
+			if domainInfo.consecutiveFailures != tC.wantFailures {
+				t.Errorf("scheduleNextRefresh() %s: got failures %d, want %d", tC.desc, domainInfo.consecutiveFailures, tC.wantFailures)
+			}
+		})
+	}
+}
+
+// blockingResolver serves fixed TXT records once released, counting lookups.
+type blockingResolver struct {
+	records map[string][]string
+	release chan struct{}
+
+	mutex   sync.Mutex
+	lookups map[string]int
+}
+
+func (r *blockingResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	records, _, err := r.LookupTXTWithTTL(ctx, name)
+	return records, err
+}
+
+func (r *blockingResolver) LookupTXTWithTTL(ctx context.Context, name string) ([]string, time.Duration, error) {
+	r.mutex.Lock()
+	r.lookups[name]++
+	r.mutex.Unlock()
+
+	<-r.release
+	if records, ok := r.records[name]; ok {
+		return records, time.Minute, nil
+	}
+	return nil, 0, ErrDNSRecordNotFound
+}
+
+func newSynchronousTestIndexer(t *testing.T, resolver DNSResolver) *defaultDomainIndexer {
+	myPrivateKeys, err := privateKeysToKeyMap([]string{examplePrivateKey})
+	if err != nil {
+		t.Fatalf("privateKeysToKeyMap() unexpected error: %v", err)
+	}
+	di := &defaultDomainIndexer{
+		ctx:                      context.Background(),
+		wakeUp:                   make(chan struct{}, 1),
+		minRefreshInterval:       time.Minute,
+		maxRefreshInterval:       time.Hour,
+		negativeCacheInterval:    time.Minute,
+		maxNegativeCacheInterval: time.Hour,
+		myPrivateKeys:            myPrivateKeys,
+		dnsResolver:              resolver,
+		domainStore:              NewDefaultDomainStore(),
+		synchronousFirstLookup:   true,
+	}
+	for alias := range myPrivateKeys {
+		di.currentPrivateKey = alias
+	}
+	return di
+}
+
+func TestLookupIdentitiesForDomainContext_Synchronous(t *testing.T) {
+	resolver := &blockingResolver{
+		records: map[string][]string{
+			"_adscert.invoking.com":           {"v=adpf a=identity.com"},
+			"_delivery._adscert.identity.com": {testKeyRecord},
+		},
+		release: make(chan struct{}),
+		lookups: map[string]int{},
+	}
+	di := newSynchronousTestIndexer(t, resolver)
+
+	// Callers either join the lookup in flight or find its result stored, so
+	// the resolver is released as soon as they have all started.
+	const callers = 10
+	var started, wg sync.WaitGroup
+	results := make([][]DomainInfo, callers)
+	for i := 0; i < callers; i++ {
+		started.Add(1)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			started.Done()
+			results[i], _ = di.LookupIdentitiesForDomainContext(context.Background(), "invoking.com")
+		}(i)
+	}
+	started.Wait()
+	close(resolver.release)
+	wg.Wait()
+
+	for i, domainInfos := range results {
+		if len(domainInfos) != 1 || domainInfos[0].Domain != "identity.com" {
+			t.Errorf("LookupIdentitiesForDomainContext() caller %d: got %v, want identity.com", i, domainInfos)
+			continue
+		}
+		if _, ok := domainInfos[0].GetSharedSecret(); !ok {
+			t.Errorf("LookupIdentitiesForDomainContext() caller %d: got no shared secret", i)
+		}
+	}
+	for name, count := range resolver.lookups {
+		if count != 1 {
+			t.Errorf("LookupIdentitiesForDomainContext() lookups for %s: got %d, want 1", name, count)
+		}
+	}
+}
+
+func TestLookupIdentitiesForDomainContext_Deadline(t *testing.T) {
+	resolver := &blockingResolver{release: make(chan struct{}), lookups: map[string]int{}}
+	defer close(resolver.release)
+	di := newSynchronousTestIndexer(t, resolver)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	domainInfos, err := di.LookupIdentitiesForDomainContext(ctx, "invoking.com")
+	if err != nil || len(domainInfos) != 0 {
+		t.Errorf("LookupIdentitiesForDomainContext() past deadline: got %v, %v, want no identities", domainInfos, err)
+	}
+}
+
+func TestParseKeyRecords_PrimaryKey(t *testing.T) {
+	const (
+		firstKey  = "Bm8J1RW3RxHp_-mx3lE7eAuYObfALvwurVjXtcaYFVA"
+		secondKey = "VfLEG883mudlLgxEA3RJvXm32PowzMgTZGOGCT72zWw"
+	)
+
+	testCases := []struct {
+		desc    string
+		records []string
+
+		wantPrimaryKey keyAlias
+	}{
+		{
+			desc:           "first listed key without marker",
+			records:        []string{"v=adcrtd k=x25519 h=sha256 p=" + secondKey + " p=" + firstKey},
+			wantPrimaryKey: "VfLEG8",
+		},
+		{
+			desc:           "marked primary key",
+			records:        []string{"v=adcrtd k=x25519 h=sha256 primary=VfLEG8 p=" + firstKey + " p=" + secondKey},
+			wantPrimaryKey: "VfLEG8",
+		},
+		{
+			desc: "marker in a later record",
+			records: []string{
+				"v=adcrtd k=x25519 h=sha256 primary=VfLEG8 p=" + secondKey,
+				"v=adcrtd k=x25519 h=sha256 p=" + firstKey,
+			},
+			wantPrimaryKey: "VfLEG8",
+		},
+		{
+			desc: "unmarked records in either order",
+			records: []string{
+				"v=adcrtd k=x25519 h=sha256 p=" + secondKey,
+				"v=adcrtd k=x25519 h=sha256 p=" + firstKey,
+			},
+			wantPrimaryKey: "Bm8J1R",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			for _, records := range [][]string{tC.records, reversed(tC.records)} {
+				foundKeys, primaryKey, parseError := parseKeyRecords(exampleDomainName, "_delivery._adscert."+exampleDomainName, records)
+				if parseError || len(foundKeys) != 2 {
+					t.Fatalf("parseKeyRecords() %s: got %d keys, parse error %v", tC.desc, len(foundKeys), parseError)
+				}
+				if primaryKey != tC.wantPrimaryKey {
+					t.Errorf("parseKeyRecords() %s: got primary key %v, want %v", tC.desc, primaryKey, tC.wantPrimaryKey)
+				}
+			}
+		})
+	}
+}
+
+func reversed(records []string) []string {
+	result := make([]string, 0, len(records))
+	for i := len(records) - 1; i >= 0; i-- {
+		result = append(result, records[i])
+	}
+	return result
+}
+
+func TestSelectCurrentPrivateKey(t *testing.T) {
+	privateKeys := keyMap{"AAAAAA": &x25519Key{alias: "AAAAAA"}, "BBBBBB": &x25519Key{alias: "BBBBBB"}, "CCCCCC": &x25519Key{alias: "CCCCCC"}}
+
+	testCases := []struct {
+		desc  string
+		roles map[string]PrivateKeyRole
+
+		want keyAlias
+	}{
+		{
+			desc: "no roles",
+			want: "CCCCCC",
+		},
+		{
+			desc:  "primary role",
+			roles: map[string]PrivateKeyRole{"AAAAAA": PrivateKeyRolePrimary, "CCCCCC": PrivateKeyRoleSecondary},
+			want:  "AAAAAA",
+		},
+		{
+			desc:  "several primary roles",
+			roles: map[string]PrivateKeyRole{"AAAAAA": PrivateKeyRolePrimary, "BBBBBB": PrivateKeyRolePrimary},
+			want:  "BBBBBB",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			if got := selectCurrentPrivateKey(privateKeys, tC.roles); got != tC.want {
+				t.Errorf("selectCurrentPrivateKey() %s: got %v, want %v", tC.desc, got, tC.want)
+			}
+		})
+	}
+}
+
+// fixedResolver serves fixed TXT records, failing lookups of the names in
+// failures.
+type fixedResolver struct {
+	records  map[string][]string
+	failures map[string]error
+}
+
+func (r *fixedResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	records, _, err := r.LookupTXTWithTTL(ctx, name)
+	return records, err
+}
+
+func (r *fixedResolver) LookupTXTWithTTL(ctx context.Context, name string) ([]string, time.Duration, error) {
+	if err, ok := r.failures[name]; ok {
+		return nil, 0, err
+	}
+	if records, ok := r.records[name]; ok {
+		return records, time.Minute, nil
+	}
+	return nil, 0, ErrDNSRecordNotFound
+}
+
+func TestRefreshDomain_DNSSECStatus(t *testing.T) {
+	validationErr := func(name string) error {
+		return &DNSSECValidationError{Name: name, Err: ErrDNSSECNoSignature}
+	}
+	testCases := []struct {
+		desc     string
+		failures map[string]error
+		want     DomainStatus
+	}{
+		{
+			desc: "validated",
+			want: DomainStatusOK,
+		},
+		{
+			desc:     "policy record fails validation",
+			failures: map[string]error{"_adscert.identity.com": validationErr("_adscert.identity.com")},
+			want:     DomainStatusErrorOnDNSSEC,
+		},
+		{
+			desc:     "keys record fails validation",
+			failures: map[string]error{"_delivery._adscert.identity.com": validationErr("_delivery._adscert.identity.com")},
+			want:     DomainStatusErrorOnDNSSEC,
+		},
+		{
+			desc:     "policy record not found",
+			failures: map[string]error{"_adscert.identity.com": ErrDNSRecordNotFound},
+			want:     DomainStatusOK,
+		},
+	}
+	for _, tC := range testCases {
+		resolver := &fixedResolver{records: map[string][]string{"_delivery._adscert.identity.com": {testKeyRecord}}}
+		di := newSynchronousTestIndexer(t, resolver)
+
+		// The first refresh validates, caching the keys.
+		domainInfo := di.refreshDomain(context.Background(), "identity.com")
+		if got := domainInfo.GetStatus(); got != DomainStatusOK {
+			t.Fatalf("refreshDomain() %s before failures: got %v, want %v", tC.desc, got, DomainStatusOK)
+		}
+
+		resolver.failures = tC.failures
+		domainInfo = di.refreshDomain(context.Background(), "identity.com")
+		if got := domainInfo.GetStatus(); got != tC.want {
+			t.Errorf("refreshDomain() %s: got %v, want %v", tC.desc, got, tC.want)
+		}
+	}
+}
@@ -12,8 +12,15 @@ type DomainInfo struct {
 	allPublicKeys         keyMap
 	allSharedSecrets      keyPairMap
 
+	// originSharedSecretIds holds the current shared secret for each
+	// additional origin callsign hosted alongside the default one.
+	originSharedSecretIds map[string]keyPairAlias
+
 	domainStatus   DomainStatus
 	lastUpdateTime time.Time
+
+	nextRefreshTime     time.Time // when the indexer should next check this domain's records
+	consecutiveFailures int       // consecutive negative lookups, used for backoff
 }
 
 type SharedSecret interface {
@@ -30,7 +37,57 @@ func (c *DomainInfo) GetStatus() DomainStatus {
 	return c.domainStatus
 }
 
+// GetLastUpdateTime returns when the domain's records were last found, or the
+// zero time if they never were.
+func (c *DomainInfo) GetLastUpdateTime() time.Time {
+	return c.lastUpdateTime
+}
+
 func (c *DomainInfo) GetSharedSecret() (SharedSecret, bool) {
 	sharedSecret, ok := c.allSharedSecrets[c.currentSharedSecretId]
 	return sharedSecret, ok
 }
+
+// GetCurrentPublicKeyID returns the alias of the domain's primary public key.
+func (c *DomainInfo) GetCurrentPublicKeyID() string {
+	return string(c.currentPublicKeyId)
+}
+
+// GetCurrentKeyPair returns the aliases of the local private key and the
+// domain's public key whose shared secret is used to sign requests to it.
+func (c *DomainInfo) GetCurrentKeyPair() (localKeyID string, remoteKeyID string) {
+	return string(c.currentSharedSecretId.originKeyAlias), string(c.currentSharedSecretId.remoteKeyAlias)
+}
+
+// GetCurrentKeyPairForOrigin returns the key pair used to sign requests from
+// one of the additional origin callsigns configured on the indexer.
+func (c *DomainInfo) GetCurrentKeyPairForOrigin(origin string) (localKeyID string, remoteKeyID string, ok bool) {
+	sharedSecretId, ok := c.originSharedSecretIds[origin]
+	return string(sharedSecretId.originKeyAlias), string(sharedSecretId.remoteKeyAlias), ok
+}
+
+// GetSharedSecretForKeyPair returns the shared secret between one of our
+// private keys and one of this domain's published public keys, as named by the
+// to_key and from_key attributes of a signature the domain sent us.
+func (c *DomainInfo) GetSharedSecretForKeyPair(localKeyID string, remoteKeyID string) (SharedSecret, bool) {
+	sharedSecret, ok := c.allSharedSecrets[newKeyPairAlias(keyAlias(localKeyID), keyAlias(remoteKeyID))]
+	return sharedSecret, ok
+}
+
+// HasPublicKey reports whether the domain currently publishes a public key
+// with the given alias.
+func (c *DomainInfo) HasPublicKey(keyID string) bool {
+	_, ok := c.allPublicKeys[keyAlias(keyID)]
+	return ok
+}
+
+// GetSharedSecretForOrigin returns the current shared secret between this
+// domain and one of the additional origin callsigns configured on the indexer.
+func (c *DomainInfo) GetSharedSecretForOrigin(origin string) (SharedSecret, bool) {
+	sharedSecretId, ok := c.originSharedSecretIds[origin]
+	if !ok {
+		return nil, false
+	}
+	sharedSecret, ok := c.allSharedSecrets[sharedSecretId]
+	return sharedSecret, ok
+}
@@ -14,3 +14,23 @@ const (
 	DomainStatusADPFParseError
 	DomainStatusADCRTDParseError
 )
+
+var domainStatusNames = map[DomainStatus]string{
+	DomainStatusUnspecified:                    "unspecified",
+	DomainStatusOK:                             "ok",
+	DomainStatusUnavailable:                    "unavailable",
+	DomainStatusNotYetChecked:                  "not_yet_checked",
+	DomainStatusKeyFetchPending:                "key_fetch_pending",
+	DomainStatusErrorOnDNS:                     "error_on_dns",
+	DomainStatusErrorOnDNSSEC:                  "error_on_dnssec",
+	DomainStatusErrorOnSharedSecretCalculation: "error_on_shared_secret_calculation",
+	DomainStatusADPFParseError:                 "adpf_parse_error",
+	DomainStatusADCRTDParseError:               "adcrtd_parse_error",
+}
+
+func (s DomainStatus) String() string {
+	if name, ok := domainStatusNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
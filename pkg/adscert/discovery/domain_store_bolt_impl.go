@@ -0,0 +1,141 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/IABTechLab/adscert/internal/formats"
+	bolt "go.etcd.io/bbolt"
+)
+
+var domainInfoBucket = []byte("domain_info")
+
+// NewBoltDomainStore returns a DomainStore which serves lookups from memory and
+// writes every update through to a bbolt database file at path, so that domain
+// records survive a restart.  Records already in the file are loaded on open.
+//
+// Shared secrets are never written to disk.  Domains loaded from the file have
+// their secrets recomputed by the domain indexer when it starts.
+func NewBoltDomainStore(path string) (*BoltDomainStore, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open domain store %s: %v", path, err)
+	}
+
+	store := &BoltDomainStore{db: db, cache: &defaultDomainStore{}}
+	loaded := domainMap{}
+	err = db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(domainInfoBucket)
+		if err != nil {
+			return err
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			domainInfo, err := decodeDomainInfo(v)
+			if err != nil {
+				return fmt.Errorf("unable to decode stored domain %s: %v", k, err)
+			}
+			loaded[domainInfo.Domain] = domainInfo
+			return nil
+		})
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	store.cache.domainMap.Store(loaded)
+
+	return store, nil
+}
+
+// BoltDomainStore is a DomainStore persisted to a bbolt database file.
+type BoltDomainStore struct {
+	db    *bolt.DB
+	cache *defaultDomainStore
+}
+
+// GetAllDomains returns a list of all stored domain names
+func (ds *BoltDomainStore) GetAllDomains(ctx context.Context) ([]string, error) {
+	return ds.cache.GetAllDomains(ctx)
+}
+
+// LookupDomainInfo retrives the invoking or identity details for a domain name
+func (ds *BoltDomainStore) LookupDomainInfo(ctx context.Context, domain string) (DomainInfo, bool, error) {
+	return ds.cache.LookupDomainInfo(ctx, domain)
+}
+
+// StoreDomainInfo persists the invoking or identity details for a domain and
+// makes them available for lookup.
+func (ds *BoltDomainStore) StoreDomainInfo(ctx context.Context, domainInfo DomainInfo) error {
+	data, err := encodeDomainInfo(domainInfo)
+	if err != nil {
+		return err
+	}
+	err = ds.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(domainInfoBucket).Put([]byte(domainInfo.Domain), data)
+	})
+	if err != nil {
+		return fmt.Errorf("unable to persist domain %s: %v", domainInfo.Domain, err)
+	}
+	return ds.cache.StoreDomainInfo(ctx, domainInfo)
+}
+
+// Close releases the underlying database file.
+func (ds *BoltDomainStore) Close() error {
+	return ds.db.Close()
+}
+
+// persistedDomainInfo is the on-disk representation of DomainInfo.  It
+// deliberately has no field for shared secrets.
+type persistedDomainInfo struct {
+	Domain              string            `json:"domain"`
+	IdentityDomains     []string          `json:"identity_domains"`
+	CurrentPublicKeyID  string            `json:"current_public_key_id"`
+	PublicKeys          map[string]string `json:"public_keys"`
+	Status              DomainStatus      `json:"status"`
+	LastUpdateTime      time.Time         `json:"last_update_time"`
+	NextRefreshTime     time.Time         `json:"next_refresh_time"`
+	ConsecutiveFailures int               `json:"consecutive_failures"`
+}
+
+func encodeDomainInfo(domainInfo DomainInfo) ([]byte, error) {
+	p := persistedDomainInfo{
+		Domain:              domainInfo.Domain,
+		IdentityDomains:     domainInfo.IdentityDomains,
+		CurrentPublicKeyID:  string(domainInfo.currentPublicKeyId),
+		PublicKeys:          map[string]string{},
+		Status:              domainInfo.domainStatus,
+		LastUpdateTime:      domainInfo.lastUpdateTime,
+		NextRefreshTime:     domainInfo.nextRefreshTime,
+		ConsecutiveFailures: domainInfo.consecutiveFailures,
+	}
+	for alias, key := range domainInfo.allPublicKeys {
+		p.PublicKeys[string(alias)] = formats.EncodeKeyBase64(key.keyBytes[:])
+	}
+	return json.Marshal(p)
+}
+
+func decodeDomainInfo(data []byte) (DomainInfo, error) {
+	var p persistedDomainInfo
+	if err := json.Unmarshal(data, &p); err != nil {
+		return DomainInfo{}, err
+	}
+
+	domainInfo := initializeDomainInfo(p.Domain)
+	domainInfo.IdentityDomains = append(domainInfo.IdentityDomains, p.IdentityDomains...)
+	domainInfo.currentPublicKeyId = keyAlias(p.CurrentPublicKeyID)
+	domainInfo.domainStatus = p.Status
+	domainInfo.lastUpdateTime = p.LastUpdateTime
+	domainInfo.nextRefreshTime = p.NextRefreshTime
+	domainInfo.consecutiveFailures = p.ConsecutiveFailures
+	for alias, publicKeyBase64 := range p.PublicKeys {
+		key, err := parseKeyFromString(publicKeyBase64)
+		if err != nil {
+			return DomainInfo{}, err
+		}
+		key.alias = keyAlias(alias)
+		domainInfo.allPublicKeys[key.alias] = key
+	}
+	return domainInfo, nil
+}
@@ -0,0 +1,92 @@
+package discovery
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	exampleKeyAlias   = "Bm8J1RW3"
+	examplePrivateKey = "Bm8J1RW3RxHp_-mx3lE7eAuYObfALvwurVjXtcaYFVA"
+)
+
+func TestBoltDomainStore_Reopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "domains.db")
+	lastUpdateTime := time.Date(2022, 11, 8, 21, 2, 36, 0, time.UTC)
+
+	domainStore, err := NewBoltDomainStore(path)
+	if err != nil {
+		t.Fatalf("NewBoltDomainStore() unexpected error: %v", err)
+	}
+
+	publicKey, err := parseKeyFromString(examplePrivateKey)
+	if err != nil {
+		t.Fatalf("parseKeyFromString() unexpected error: %v", err)
+	}
+	publicKey.alias = exampleKeyAlias
+	sharedSecretId := newKeyPairAlias(exampleKeyAlias, exampleKeyAlias)
+
+	domainInfo := initializeDomainInfo(exampleDomainName)
+	domainInfo.IdentityDomains = []string{"identity.com"}
+	domainInfo.allPublicKeys[publicKey.alias] = publicKey
+	domainInfo.currentPublicKeyId = publicKey.alias
+	domainInfo.allSharedSecrets[sharedSecretId] = publicKey
+	domainInfo.currentSharedSecretId = sharedSecretId
+	domainInfo.domainStatus = DomainStatusOK
+	domainInfo.lastUpdateTime = lastUpdateTime
+
+	if err := domainStore.StoreDomainInfo(ctx, domainInfo); err != nil {
+		t.Fatalf("StoreDomainInfo() unexpected error: %v", err)
+	}
+	if err := domainStore.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	domainStore, err = NewBoltDomainStore(path)
+	if err != nil {
+		t.Fatalf("NewBoltDomainStore() reopen unexpected error: %v", err)
+	}
+	defer domainStore.Close()
+
+	got, ok, err := domainStore.LookupDomainInfo(ctx, exampleDomainName)
+	if err != nil || !ok {
+		t.Fatalf("LookupDomainInfo() after reopen: got ok %v, err %v", ok, err)
+	}
+	if len(got.IdentityDomains) != 1 || got.IdentityDomains[0] != "identity.com" {
+		t.Errorf("LookupDomainInfo() identity domains: got %v, want [identity.com]", got.IdentityDomains)
+	}
+	if got.GetStatus() != DomainStatusOK {
+		t.Errorf("LookupDomainInfo() status: got %v, want %v", got.GetStatus(), DomainStatusOK)
+	}
+	if !got.lastUpdateTime.Equal(lastUpdateTime) {
+		t.Errorf("LookupDomainInfo() last update time: got %v, want %v", got.lastUpdateTime, lastUpdateTime)
+	}
+	if got.currentPublicKeyId != exampleKeyAlias {
+		t.Errorf("LookupDomainInfo() current public key: got %v, want %v", got.currentPublicKeyId, exampleKeyAlias)
+	}
+	if gotKey := got.allPublicKeys[exampleKeyAlias]; gotKey == nil || gotKey.keyBytes != publicKey.keyBytes {
+		t.Errorf("LookupDomainInfo() public key: got %v, want %v", gotKey, publicKey)
+	}
+	if len(got.allSharedSecrets) != 0 {
+		t.Errorf("LookupDomainInfo() shared secrets: got %d, want none persisted", len(got.allSharedSecrets))
+	}
+
+	// A warm start derives the shared secrets again from the stored public keys.
+	myPrivateKeys, err := privateKeysToKeyMap([]string{examplePrivateKey})
+	if err != nil {
+		t.Fatalf("privateKeysToKeyMap() unexpected error: %v", err)
+	}
+	di := &defaultDomainIndexer{myPrivateKeys: myPrivateKeys, domainStore: domainStore}
+	for alias := range myPrivateKeys {
+		di.currentPrivateKey = alias
+	}
+	di.warmStart(ctx)
+
+	got, _, _ = domainStore.LookupDomainInfo(ctx, exampleDomainName)
+	if _, ok := got.GetSharedSecret(); !ok {
+		t.Errorf("GetSharedSecret() after warm start: got no shared secret")
+	}
+}
@@ -0,0 +1,38 @@
+package discovery
+
+import (
+	"context"
+
+	"github.com/IABTechLab/adscert/pkg/adscert/logger"
+	"github.com/IABTechLab/adscert/pkg/adscert/metrics"
+)
+
+// RegisterDomainStoreMetrics exports the status and refresh age of every
+// domain held by domainStore through the metrics package's domain gauges.
+func RegisterDomainStoreMetrics(domainStore DomainStore) {
+	metrics.SetDomainStateSource(func() []metrics.DomainState {
+		return domainStates(context.Background(), domainStore)
+	})
+}
+
+func domainStates(ctx context.Context, domainStore DomainStore) []metrics.DomainState {
+	domains, err := domainStore.GetAllDomains(ctx)
+	if err != nil {
+		logger.Warningf("unable to list domains for metrics: %v", err)
+		return nil
+	}
+
+	var states []metrics.DomainState
+	for _, domain := range domains {
+		domainInfo, ok, err := domainStore.LookupDomainInfo(ctx, domain)
+		if err != nil || !ok {
+			continue
+		}
+		states = append(states, metrics.DomainState{
+			Domain:      domain,
+			Status:      domainInfo.GetStatus().String(),
+			LastRefresh: domainInfo.GetLastUpdateTime(),
+		})
+	}
+	return states
+}
@@ -0,0 +1,36 @@
+package discovery
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/IABTechLab/adscert/pkg/adscert/metrics"
+)
+
+func TestDomainStates(t *testing.T) {
+	ctx := context.Background()
+	domainStore := NewDefaultDomainStore()
+	lastUpdateTime := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	refreshed := initializeDomainInfo(exampleDomainName)
+	refreshed.domainStatus = DomainStatusOK
+	refreshed.lastUpdateTime = lastUpdateTime
+	domainStore.StoreDomainInfo(ctx, refreshed)
+	domainStore.StoreDomainInfo(ctx, initializeDomainInfo("example2.com"))
+
+	got := domainStates(ctx, domainStore)
+	want := map[string]metrics.DomainState{
+		exampleDomainName: {Domain: exampleDomainName, Status: "ok", LastRefresh: lastUpdateTime},
+		"example2.com":    {Domain: "example2.com", Status: "not_yet_checked"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("domainStates(): got %d domains, want %d", len(got), len(want))
+	}
+	for _, state := range got {
+		if !reflect.DeepEqual(state, want[state.Domain]) {
+			t.Errorf("domainStates() %s: got %+v, want %+v", state.Domain, state, want[state.Domain])
+		}
+	}
+}
@@ -107,3 +107,17 @@ func parseKeyFromString(base64EncodedKey string) (*x25519Key, error) {
 	}
 	return &key, nil
 }
+
+// PrivateKeyAliases returns the aliases of the public keys matching the given
+// base64 encoded private keys, as named by signature to_key attributes.
+func PrivateKeyAliases(base64PrivateKeys []string) ([]string, error) {
+	privateKeys, err := privateKeysToKeyMap(base64PrivateKeys)
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for alias := range privateKeys {
+		result = append(result, string(alias))
+	}
+	return result, nil
+}
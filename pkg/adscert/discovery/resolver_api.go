@@ -1,7 +1,14 @@
 package discovery
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type DNSResolver interface {
 	LookupTXT(ctx context.Context, name string) ([]string, error)
+
+	// LookupTXTWithTTL behaves like LookupTXT and also returns the smallest
+	// TTL of the records found, or zero when the TTL isn't known.
+	LookupTXTWithTTL(ctx context.Context, name string) ([]string, time.Duration, error)
 }
@@ -0,0 +1,376 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// RootTrustAnchor is the DS record for the root zone key signing key
+// (KSK-2017), as published by IANA.
+const RootTrustAnchor = ". IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D"
+
+// maxChainDepth bounds the number of delegations followed when building a
+// chain of trust, protecting against malicious or looping responses.
+const maxChainDepth = 16
+
+// defaultMaxCachedZones bounds the number of zones whose validated DNSKEY sets
+// are cached.
+const defaultMaxCachedZones = 10000
+
+var (
+	ErrDNSSECNoSignature      = errors.New("no valid RRSIG covering the record set")
+	ErrDNSSECNoMatchingKey    = errors.New("no DNSKEY matching the delegation signer records")
+	ErrDNSSECInsecureZone     = errors.New("zone has no delegation signer records")
+	ErrDNSSECChainTooLong     = errors.New("chain of trust exceeds maximum depth")
+	ErrDNSSECSignerOutOfZone  = errors.New("signer name is not an ancestor of the record owner")
+	ErrDNSSECNoTrustAnchors   = errors.New("no trust anchors configured")
+	ErrDNSSECMalformedAnchor  = errors.New("trust anchor is not a DS record")
+	ErrDNSRecordNotFound      = errors.New("no such DNS record")
+	ErrDNSUnexpectedRcode     = errors.New("unexpected DNS response code")
+	ErrNoNameserverConfigured = errors.New("no nameserver configured")
+)
+
+// DNSSECValidationError reports that a DNS response could not be
+// authenticated against the configured trust anchors.
+type DNSSECValidationError struct {
+	Name string
+	Err  error
+}
+
+func (e *DNSSECValidationError) Error() string {
+	return fmt.Sprintf("DNSSEC validation failed for %s: %v", e.Name, e.Err)
+}
+
+func (e *DNSSECValidationError) Unwrap() error {
+	return e.Err
+}
+
+// NewDNSSECResolver returns a DNSResolver that queries nameserver (host:port)
+// with the DNSSEC OK bit set and validates the chain of trust for every
+// answer, starting from the trust anchors and following DS records down to
+// the queried name.  Trust anchors are DS records in zone file presentation
+// format, such as RootTrustAnchor.  When nameserver is empty, the first
+// server listed in /etc/resolv.conf is used.
+//
+// Responses which deny the existence of a name are not authenticated, so a
+// failed lookup is never treated as proof that a record is absent.
+func NewDNSSECResolver(nameserver string, trustAnchors []string) (DNSResolver, error) {
+	if nameserver == "" {
+		config, err := dns.ClientConfigFromFile("/etc/resolv.conf")
+		if err != nil {
+			return nil, fmt.Errorf("unable to read resolver configuration: %v", err)
+		}
+		if len(config.Servers) == 0 {
+			return nil, ErrNoNameserverConfigured
+		}
+		nameserver = config.Servers[0] + ":" + config.Port
+	}
+
+	anchors := map[string][]*dns.DS{}
+	for _, s := range trustAnchors {
+		rr, err := dns.NewRR(s)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse trust anchor %q: %v", s, err)
+		}
+		ds, ok := rr.(*dns.DS)
+		if !ok {
+			return nil, ErrDNSSECMalformedAnchor
+		}
+		zone := dns.CanonicalName(ds.Hdr.Name)
+		anchors[zone] = append(anchors[zone], ds)
+	}
+	if len(anchors) == 0 {
+		return nil, ErrDNSSECNoTrustAnchors
+	}
+
+	return &dnssecResolver{
+		nameserver:   nameserver,
+		client:       &dns.Client{Net: "udp"},
+		tcpClient:    &dns.Client{Net: "tcp"},
+		trustAnchors: anchors,
+		zoneKeys:     map[string]validatedZoneKeys{},
+		maxZoneKeys:  defaultMaxCachedZones,
+	}, nil
+}
+
+type dnssecResolver struct {
+	nameserver   string
+	client       *dns.Client
+	tcpClient    *dns.Client
+	trustAnchors map[string][]*dns.DS
+
+	// zoneKeys caches DNSKEY sets which have already been validated, keyed by
+	// canonical zone name, until their TTL or signature expires.  At most
+	// maxZoneKeys zones are cached.
+	zoneKeys     map[string]validatedZoneKeys
+	zoneKeysLock sync.RWMutex
+	maxZoneKeys  int
+}
+
+type validatedZoneKeys struct {
+	keys    []*dns.DNSKEY
+	expires time.Time
+}
+
+func (r *dnssecResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	records, _, err := r.LookupTXTWithTTL(ctx, name)
+	return records, err
+}
+
+func (r *dnssecResolver) LookupTXTWithTTL(ctx context.Context, name string) ([]string, time.Duration, error) {
+	answer, err := r.queryValidated(ctx, dns.Fqdn(name), dns.TypeTXT, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	return txtRecordsWithTTL(answer)
+}
+
+// queryValidated performs a query and returns the answer section once every
+// record set in it has been authenticated.
+func (r *dnssecResolver) queryValidated(ctx context.Context, name string, qtype uint16, depth int) ([]dns.RR, error) {
+	if depth > maxChainDepth {
+		return nil, &DNSSECValidationError{Name: name, Err: ErrDNSSECChainTooLong}
+	}
+
+	response, err := r.exchange(ctx, name, qtype)
+	if err != nil {
+		return nil, err
+	}
+
+	rrsets, signatures := groupRRsets(response.Answer)
+	for key, rrset := range rrsets {
+		if err := r.verifyRRset(ctx, rrset, signatures[key], depth); err != nil {
+			return nil, &DNSSECValidationError{Name: key.name, Err: err}
+		}
+	}
+
+	var answer []dns.RR
+	for _, rr := range response.Answer {
+		if rr.Header().Rrtype != dns.TypeRRSIG {
+			answer = append(answer, rr)
+		}
+	}
+	return answer, nil
+}
+
+func (r *dnssecResolver) exchange(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(name, qtype)
+	m.SetEdns0(4096, true)
+	// Ask the upstream resolver for the data even if it can't validate it
+	// itself, since validation happens here.
+	m.CheckingDisabled = true
+
+	response, _, err := r.client.ExchangeContext(ctx, m, r.nameserver)
+	if err == nil && response.Truncated {
+		response, _, err = r.tcpClient.ExchangeContext(ctx, m, r.nameserver)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch response.Rcode {
+	case dns.RcodeSuccess:
+		return response, nil
+	case dns.RcodeNameError:
+		return nil, ErrDNSRecordNotFound
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrDNSUnexpectedRcode, dns.RcodeToString[response.Rcode])
+	}
+}
+
+// verifyRRset checks that at least one signature over the record set was made
+// by an authenticated key of the signing zone and is currently valid.
+func (r *dnssecResolver) verifyRRset(ctx context.Context, rrset []dns.RR, signatures []*dns.RRSIG, depth int) error {
+	owner := rrset[0].Header().Name
+	for _, sig := range signatures {
+		if !dns.IsSubDomain(sig.SignerName, owner) {
+			return ErrDNSSECSignerOutOfZone
+		}
+		keys, err := r.lookupZoneKeys(ctx, dns.CanonicalName(sig.SignerName), depth+1)
+		if err != nil {
+			return err
+		}
+		if verifyWithAnyKey(sig, keys, rrset) {
+			return nil
+		}
+	}
+	return ErrDNSSECNoSignature
+}
+
+// lookupZoneKeys returns the authenticated DNSKEY set for a zone, validating
+// it either directly against a trust anchor or against DS records published
+// (and authenticated) in the parent zone.
+func (r *dnssecResolver) lookupZoneKeys(ctx context.Context, zone string, depth int) ([]*dns.DNSKEY, error) {
+	r.zoneKeysLock.RLock()
+	cached, ok := r.zoneKeys[zone]
+	r.zoneKeysLock.RUnlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.keys, nil
+	}
+
+	if depth > maxChainDepth {
+		return nil, ErrDNSSECChainTooLong
+	}
+
+	delegationSigners, ok := r.trustAnchors[zone]
+	if !ok {
+		if zone == "." {
+			return nil, ErrDNSSECNoMatchingKey
+		}
+		dsRecords, err := r.queryValidated(ctx, zone, dns.TypeDS, depth)
+		if err != nil {
+			if errors.Is(err, ErrDNSRecordNotFound) {
+				return nil, ErrDNSSECInsecureZone
+			}
+			return nil, err
+		}
+		for _, rr := range dsRecords {
+			if ds, ok := rr.(*dns.DS); ok && dns.CanonicalName(ds.Hdr.Name) == zone {
+				delegationSigners = append(delegationSigners, ds)
+			}
+		}
+		if len(delegationSigners) == 0 {
+			return nil, ErrDNSSECInsecureZone
+		}
+	}
+
+	response, err := r.exchange(ctx, zone, dns.TypeDNSKEY)
+	if err != nil {
+		return nil, err
+	}
+	rrsets, signatures := groupRRsets(response.Answer)
+	keySetID := rrsetID{name: zone, rrtype: dns.TypeDNSKEY}
+	keySet := rrsets[keySetID]
+
+	var keys []*dns.DNSKEY
+	for _, rr := range keySet {
+		keys = append(keys, rr.(*dns.DNSKEY))
+	}
+
+	// The key set must be signed by a key that one of the DS records vouches for.
+	var signingKeys []*dns.DNSKEY
+	for _, key := range keys {
+		if matchesAnyDS(key, delegationSigners) {
+			signingKeys = append(signingKeys, key)
+		}
+	}
+	if len(signingKeys) == 0 {
+		return nil, ErrDNSSECNoMatchingKey
+	}
+
+	var validatingSig *dns.RRSIG
+	for _, sig := range signatures[keySetID] {
+		if verifyWithAnyKey(sig, signingKeys, keySet) {
+			validatingSig = sig
+			break
+		}
+	}
+	if validatingSig == nil {
+		return nil, ErrDNSSECNoSignature
+	}
+
+	now := time.Now()
+	r.cacheZoneKeys(zone, keys, zoneKeysExpiry(keySet, validatingSig, now), now)
+
+	return keys, nil
+}
+
+// zoneKeysExpiry returns when a validated DNSKEY set must be looked up again:
+// after its TTL, capped by the original TTL and the expiration of the
+// signature which validated it, so a forged TTL can't extend its life.
+func zoneKeysExpiry(keySet []dns.RR, sig *dns.RRSIG, now time.Time) time.Time {
+	ttl := keySet[0].Header().Ttl
+	if sig.OrigTtl < ttl {
+		ttl = sig.OrigTtl
+	}
+	expires := now.Add(time.Duration(ttl) * time.Second)
+	if sigExpires := rrsigExpiration(sig, now); sigExpires.Before(expires) {
+		expires = sigExpires
+	}
+	return expires
+}
+
+// rrsigExpiration converts the expiration of a signature, a 32-bit serial
+// number of seconds, to the time closest to now, as RFC 4034 section 3.1.5
+// requires.
+func rrsigExpiration(sig *dns.RRSIG, now time.Time) time.Time {
+	const year68 = 1 << 31
+	cycles := (int64(sig.Expiration) - now.Unix()) / year68
+	return time.Unix(int64(sig.Expiration)+cycles*year68, 0)
+}
+
+// cacheZoneKeys caches a validated DNSKEY set.  When the cache is full,
+// expired sets are dropped first, then the set expiring soonest.
+func (r *dnssecResolver) cacheZoneKeys(zone string, keys []*dns.DNSKEY, expires time.Time, now time.Time) {
+	r.zoneKeysLock.Lock()
+	defer r.zoneKeysLock.Unlock()
+
+	if _, ok := r.zoneKeys[zone]; !ok && len(r.zoneKeys) >= r.maxZoneKeys {
+		var soonest string
+		for cachedZone, cached := range r.zoneKeys {
+			if !cached.expires.After(now) {
+				delete(r.zoneKeys, cachedZone)
+			} else if soonest == "" || cached.expires.Before(r.zoneKeys[soonest].expires) {
+				soonest = cachedZone
+			}
+		}
+		if len(r.zoneKeys) >= r.maxZoneKeys {
+			delete(r.zoneKeys, soonest)
+		}
+	}
+	r.zoneKeys[zone] = validatedZoneKeys{keys: keys, expires: expires}
+}
+
+type rrsetID struct {
+	name   string
+	rrtype uint16
+}
+
+// groupRRsets splits a response section into record sets and the signatures
+// covering each of them.
+func groupRRsets(section []dns.RR) (map[rrsetID][]dns.RR, map[rrsetID][]*dns.RRSIG) {
+	rrsets := map[rrsetID][]dns.RR{}
+	signatures := map[rrsetID][]*dns.RRSIG{}
+	for _, rr := range section {
+		name := dns.CanonicalName(rr.Header().Name)
+		if sig, ok := rr.(*dns.RRSIG); ok {
+			id := rrsetID{name: name, rrtype: sig.TypeCovered}
+			signatures[id] = append(signatures[id], sig)
+		} else {
+			id := rrsetID{name: name, rrtype: rr.Header().Rrtype}
+			rrsets[id] = append(rrsets[id], rr)
+		}
+	}
+	return rrsets, signatures
+}
+
+func verifyWithAnyKey(sig *dns.RRSIG, keys []*dns.DNSKEY, rrset []dns.RR) bool {
+	if !sig.ValidityPeriod(time.Now()) {
+		return false
+	}
+	for _, key := range keys {
+		if key.KeyTag() == sig.KeyTag && sig.Verify(key, rrset) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAnyDS(key *dns.DNSKEY, delegationSigners []*dns.DS) bool {
+	for _, ds := range delegationSigners {
+		if ds.KeyTag != key.KeyTag() || ds.Algorithm != key.Algorithm {
+			continue
+		}
+		if computed := key.ToDS(ds.DigestType); computed != nil && strings.EqualFold(computed.Digest, ds.Digest) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,277 @@
+package discovery
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	testKeyRecord = "v=adcrtd k=x25519 h=sha256 p=Bm8J1RW3RxHp_-mx3lE7eAuYObfALvwurVjXtcaYFVA"
+)
+
+// testZone holds the DNSSEC signing key for one zone.
+type testZone struct {
+	name string
+	key  *dns.DNSKEY
+	priv crypto.Signer
+}
+
+func newTestZone(t *testing.T, name string) *testZone {
+	key := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: name, Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+		Flags:     dns.ZONE | dns.SEP,
+		Protocol:  3,
+		Algorithm: dns.ECDSAP256SHA256,
+	}
+	priv, err := key.Generate(256)
+	if err != nil {
+		t.Fatalf("unable to generate zone key: %v", err)
+	}
+	return &testZone{name: name, key: key, priv: priv.(crypto.Signer)}
+}
+
+func (z *testZone) sign(t *testing.T, rrset []dns.RR) *dns.RRSIG {
+	sig := &dns.RRSIG{
+		Algorithm:  z.key.Algorithm,
+		KeyTag:     z.key.KeyTag(),
+		SignerName: z.name,
+		Inception:  uint32(time.Now().Add(-time.Hour).Unix()),
+		Expiration: uint32(time.Now().Add(time.Hour).Unix()),
+	}
+	if err := sig.Sign(z.priv, rrset); err != nil {
+		t.Fatalf("unable to sign record set: %v", err)
+	}
+	return sig
+}
+
+func (z *testZone) ds() *dns.DS {
+	return z.key.ToDS(dns.SHA256)
+}
+
+// testNameserver serves a fixed set of record sets over UDP on localhost.
+type testNameserver struct {
+	records map[rrsetID][]dns.RR
+	names   map[string]bool
+}
+
+func (ns *testNameserver) add(rrs ...dns.RR) {
+	for _, rr := range rrs {
+		name := dns.CanonicalName(rr.Header().Name)
+		rrtype := rr.Header().Rrtype
+		if sig, ok := rr.(*dns.RRSIG); ok {
+			rrtype = sig.TypeCovered
+		}
+		id := rrsetID{name: name, rrtype: rrtype}
+		ns.records[id] = append(ns.records[id], rr)
+		ns.names[name] = true
+	}
+}
+
+func (ns *testNameserver) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	m := new(dns.Msg)
+	m.SetReply(req)
+	q := req.Question[0]
+	name := dns.CanonicalName(q.Name)
+	if !ns.names[name] {
+		m.Rcode = dns.RcodeNameError
+	}
+	m.Answer = ns.records[rrsetID{name: name, rrtype: q.Qtype}]
+	w.WriteMsg(m)
+}
+
+func startTestNameserver(t *testing.T, ns *testNameserver) string {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	started := make(chan struct{})
+	server := &dns.Server{PacketConn: pc, Handler: ns, NotifyStartedFunc: func() { close(started) }}
+	go server.ActivateAndServe()
+	<-started
+	t.Cleanup(func() { server.Shutdown() })
+	return pc.LocalAddr().String()
+}
+
+func txtRecord(name string, value string) *dns.TXT {
+	return &dns.TXT{
+		Hdr: dns.RR_Header{Name: name, Rrtype: dns.TypeTXT, Class: dns.ClassINET, Ttl: 300},
+		Txt: []string{value},
+	}
+}
+
+// setUpSignedZones serves a "test." parent zone delegating securely to
+// "example.test." and insecurely to "insecure.test.".
+func setUpSignedZones(t *testing.T) (string, *testZone) {
+	parent := newTestZone(t, "test.")
+	child := newTestZone(t, "example.test.")
+	ns := &testNameserver{records: map[rrsetID][]dns.RR{}, names: map[string]bool{}}
+
+	ns.add(parent.key, parent.sign(t, []dns.RR{parent.key}))
+
+	ds := child.ds()
+	ds.Hdr.Ttl = 3600
+	ns.add(ds, parent.sign(t, []dns.RR{ds}))
+	ns.add(child.key, child.sign(t, []dns.RR{child.key}))
+
+	valid := txtRecord("_delivery._adscert.example.test.", testKeyRecord)
+	ns.add(valid, child.sign(t, []dns.RR{valid}))
+
+	// The signature covers a different value than the one served.
+	tampered := txtRecord("_delivery._adscert.tampered.example.test.", testKeyRecord)
+	tamperedSig := child.sign(t, []dns.RR{txtRecord("_delivery._adscert.tampered.example.test.", "v=adcrtd k=x25519 h=sha256 p=other")})
+	ns.add(tampered, tamperedSig)
+
+	unsigned := txtRecord("_delivery._adscert.insecure.test.", testKeyRecord)
+	ns.add(unsigned)
+
+	return startTestNameserver(t, ns), parent
+}
+
+func TestDNSSECResolver_LookupTXT(t *testing.T) {
+	nameserver, parent := setUpSignedZones(t)
+	trustAnchor := parent.ds().String()
+	otherAnchor := newTestZone(t, "test.").ds().String()
+
+	testCases := []struct {
+		desc         string
+		trustAnchors []string
+		name         string
+
+		wantRecords       []string
+		wantDNSSECError   bool
+		wantNotFoundError bool
+		wantUnderlyingErr error
+	}{
+		{
+			desc:         "valid chain of trust",
+			trustAnchors: []string{trustAnchor},
+			name:         "_delivery._adscert.example.test",
+			wantRecords:  []string{testKeyRecord},
+		},
+		{
+			desc:              "tampered record",
+			trustAnchors:      []string{trustAnchor},
+			name:              "_delivery._adscert.tampered.example.test",
+			wantDNSSECError:   true,
+			wantUnderlyingErr: ErrDNSSECNoSignature,
+		},
+		{
+			desc:              "unsigned record",
+			trustAnchors:      []string{trustAnchor},
+			name:              "_delivery._adscert.insecure.test",
+			wantDNSSECError:   true,
+			wantUnderlyingErr: ErrDNSSECNoSignature,
+		},
+		{
+			desc:              "untrusted anchor",
+			trustAnchors:      []string{otherAnchor},
+			name:              "_delivery._adscert.example.test",
+			wantDNSSECError:   true,
+			wantUnderlyingErr: ErrDNSSECNoMatchingKey,
+		},
+		{
+			desc:              "missing record",
+			trustAnchors:      []string{trustAnchor},
+			name:              "_delivery._adscert.missing.example.test",
+			wantNotFoundError: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			resolver, err := NewDNSSECResolver(nameserver, tC.trustAnchors)
+			if err != nil {
+				t.Fatalf("NewDNSSECResolver() unexpected error: %v", err)
+			}
+
+			gotRecords, err := resolver.LookupTXT(context.Background(), tC.name)
+
+			var validationErr *DNSSECValidationError
+			if gotDNSSECError := errors.As(err, &validationErr); gotDNSSECError != tC.wantDNSSECError {
+				t.Errorf("LookupTXT() %s: got error %v, want DNSSEC error %v", tC.desc, err, tC.wantDNSSECError)
+			}
+			if tC.wantUnderlyingErr != nil && !errors.Is(err, tC.wantUnderlyingErr) {
+				t.Errorf("LookupTXT() %s: got error %v, want %v", tC.desc, err, tC.wantUnderlyingErr)
+			}
+			if gotNotFound := errors.Is(err, ErrDNSRecordNotFound); gotNotFound != tC.wantNotFoundError {
+				t.Errorf("LookupTXT() %s: got error %v, want not found error %v", tC.desc, err, tC.wantNotFoundError)
+			}
+			if len(gotRecords) != len(tC.wantRecords) || (len(gotRecords) > 0 && gotRecords[0] != tC.wantRecords[0]) {
+				t.Errorf("LookupTXT() %s: got records %v, want %v", tC.desc, gotRecords, tC.wantRecords)
+			}
+		})
+	}
+}
+
+func TestZoneKeysExpiry(t *testing.T) {
+	now := time.Date(2022, 11, 8, 21, 2, 36, 0, time.UTC)
+	keySet := func(ttl uint32) []dns.RR {
+		return []dns.RR{&dns.DNSKEY{Hdr: dns.RR_Header{Name: "example.test.", Rrtype: dns.TypeDNSKEY, Ttl: ttl}}}
+	}
+	testCases := []struct {
+		desc   string
+		keySet []dns.RR
+		sig    *dns.RRSIG
+		want   time.Time
+	}{
+		{
+			desc:   "record TTL",
+			keySet: keySet(300),
+			sig:    &dns.RRSIG{OrigTtl: 3600, Expiration: uint32(now.Add(24 * time.Hour).Unix())},
+			want:   now.Add(300 * time.Second),
+		},
+		{
+			desc:   "capped by original TTL",
+			keySet: keySet(86400),
+			sig:    &dns.RRSIG{OrigTtl: 3600, Expiration: uint32(now.Add(24 * time.Hour).Unix())},
+			want:   now.Add(time.Hour),
+		},
+		{
+			desc:   "capped by signature expiration",
+			keySet: keySet(3600),
+			sig:    &dns.RRSIG{OrigTtl: 3600, Expiration: uint32(now.Add(10 * time.Minute).Unix())},
+			want:   now.Add(10 * time.Minute),
+		},
+	}
+	for _, tC := range testCases {
+		if got := zoneKeysExpiry(tC.keySet, tC.sig, now); !got.Equal(tC.want) {
+			t.Errorf("zoneKeysExpiry() %s: got %v, want %v", tC.desc, got, tC.want)
+		}
+	}
+}
+
+func TestCacheZoneKeys(t *testing.T) {
+	now := time.Date(2022, 11, 8, 21, 2, 36, 0, time.UTC)
+	r := &dnssecResolver{zoneKeys: map[string]validatedZoneKeys{}, maxZoneKeys: 2}
+
+	r.cacheZoneKeys("expired.test.", nil, now.Add(-time.Minute), now)
+	r.cacheZoneKeys("soon.test.", nil, now.Add(time.Minute), now)
+	r.cacheZoneKeys("later.test.", nil, now.Add(time.Hour), now)
+	if _, ok := r.zoneKeys["expired.test."]; ok || len(r.zoneKeys) != 2 {
+		t.Errorf("cacheZoneKeys() when full: got %d zones including the expired one %v, want 2 live zones", len(r.zoneKeys), ok)
+	}
+
+	r.cacheZoneKeys("new.test.", nil, now.Add(time.Hour), now)
+	if _, ok := r.zoneKeys["soon.test."]; ok || len(r.zoneKeys) != 2 {
+		t.Errorf("cacheZoneKeys() when full of live zones: got %d zones including the soonest expiring %v, want 2 zones without it", len(r.zoneKeys), ok)
+	}
+}
+
+func TestRecordLookupError(t *testing.T) {
+	domainInfo := initializeDomainInfo(exampleDomainName)
+
+	recordLookupError(&domainInfo, ErrDNSRecordNotFound)
+	if got := domainInfo.GetStatus(); got != DomainStatusNotYetChecked {
+		t.Errorf("recordLookupError() with lookup failure: got status %v, want %v", got, DomainStatusNotYetChecked)
+	}
+
+	recordLookupError(&domainInfo, &DNSSECValidationError{Name: exampleDomainName, Err: ErrDNSSECNoSignature})
+	if got := domainInfo.GetStatus(); got != DomainStatusErrorOnDNSSEC {
+		t.Errorf("recordLookupError() with validation failure: got status %v, want %v", got, DomainStatusErrorOnDNSSEC)
+	}
+}
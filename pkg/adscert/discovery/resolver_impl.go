@@ -2,15 +2,104 @@ package discovery
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
 )
 
+// NewDefaultDnsResolver returns a DNSResolver whose LookupTXTWithTTL queries
+// the nameservers listed in /etc/resolv.conf directly, so that record TTLs are
+// available.  Those queries bypass the net package: search domains and other
+// resolv.conf options, GODEBUG netdns settings and the cgo resolver don't
+// apply, and names are always looked up as fully qualified.  If no resolver
+// configuration can be read, lookups go through the net package and TTLs are
+// reported as unknown.
+//
+// LookupTXT, which doesn't report TTLs, always goes through net.DefaultResolver.
 func NewDefaultDnsResolver() DNSResolver {
-	return &defaultDnsResolver{}
+	r := &defaultDnsResolver{
+		client:    &dns.Client{Net: "udp"},
+		tcpClient: &dns.Client{Net: "tcp"},
+	}
+	if config, err := dns.ClientConfigFromFile("/etc/resolv.conf"); err == nil {
+		for _, server := range config.Servers {
+			r.nameservers = append(r.nameservers, net.JoinHostPort(server, config.Port))
+		}
+	}
+	return r
 }
 
-type defaultDnsResolver struct{}
+type defaultDnsResolver struct {
+	nameservers []string
+	client      *dns.Client
+	tcpClient   *dns.Client
+}
 
 func (r *defaultDnsResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
-	return net.LookupTXT(name)
+	return net.DefaultResolver.LookupTXT(ctx, name)
+}
+
+func (r *defaultDnsResolver) LookupTXTWithTTL(ctx context.Context, name string) ([]string, time.Duration, error) {
+	if len(r.nameservers) == 0 {
+		records, err := net.DefaultResolver.LookupTXT(ctx, name)
+		return records, 0, err
+	}
+
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(name), dns.TypeTXT)
+
+	var lastErr error
+	for _, nameserver := range r.nameservers {
+		response, _, err := r.client.ExchangeContext(ctx, m, nameserver)
+		if err == nil && response.Truncated {
+			response, _, err = r.tcpClient.ExchangeContext(ctx, m, nameserver)
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		switch response.Rcode {
+		case dns.RcodeSuccess:
+			return txtRecordsWithTTL(response.Answer)
+		case dns.RcodeNameError:
+			return nil, 0, ErrDNSRecordNotFound
+		default:
+			lastErr = fmt.Errorf("%w: %s", ErrDNSUnexpectedRcode, dns.RcodeToString[response.Rcode])
+		}
+	}
+	return nil, 0, lastErr
+}
+
+// txtRecordsWithTTL extracts the TXT record values from an answer section
+// along with the smallest TTL among them.
+func txtRecordsWithTTL(answer []dns.RR) ([]string, time.Duration, error) {
+	var records []string
+	var ttl time.Duration
+	for _, rr := range answer {
+		if txt, ok := rr.(*dns.TXT); ok {
+			records = append(records, strings.Join(txt.Txt, ""))
+			if rrTTL := time.Duration(txt.Hdr.Ttl) * time.Second; ttl == 0 || rrTTL < ttl {
+				ttl = rrTTL
+			}
+		}
+	}
+	if len(records) == 0 {
+		return nil, 0, ErrDNSRecordNotFound
+	}
+	return records, ttl, nil
+}
+
+// isRecordNotFound reports whether a lookup failed because the name or record
+// doesn't exist, as opposed to a transient failure.
+func isRecordNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsNotFound
+	}
+	return errors.Is(err, ErrDNSRecordNotFound)
 }
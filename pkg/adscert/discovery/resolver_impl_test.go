@@ -0,0 +1,34 @@
+package discovery
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+func TestDefaultDnsResolver_LookupTXTWithTTL(t *testing.T) {
+	nameserver, _ := setUpSignedZones(t)
+	resolver := &defaultDnsResolver{
+		nameservers: []string{nameserver},
+		client:      &dns.Client{Net: "udp"},
+		tcpClient:   &dns.Client{Net: "tcp"},
+	}
+
+	gotRecords, gotTTL, err := resolver.LookupTXTWithTTL(context.Background(), "_delivery._adscert.insecure.test")
+	if err != nil {
+		t.Fatalf("LookupTXTWithTTL() unexpected error: %v", err)
+	}
+	if len(gotRecords) != 1 || gotRecords[0] != testKeyRecord {
+		t.Errorf("LookupTXTWithTTL() records: got %v, want [%s]", gotRecords, testKeyRecord)
+	}
+	if gotTTL != 300*time.Second {
+		t.Errorf("LookupTXTWithTTL() TTL: got %v, want %v", gotTTL, 300*time.Second)
+	}
+
+	_, _, err = resolver.LookupTXTWithTTL(context.Background(), "_delivery._adscert.missing.test")
+	if !isRecordNotFound(err) {
+		t.Errorf("LookupTXTWithTTL() missing record: got error %v, want not found", err)
+	}
+}
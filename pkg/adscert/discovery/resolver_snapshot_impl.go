@@ -0,0 +1,57 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// NewSnapshotDNSResolver returns a DNSResolver serving TXT records from a fixed
+// snapshot, keyed by record name, such as one captured alongside logs so that
+// they can be verified later against the records of the time.  TTLs are
+// reported as unknown.
+func NewSnapshotDNSResolver(records map[string][]string) DNSResolver {
+	r := &snapshotDnsResolver{records: map[string][]string{}}
+	for name, values := range records {
+		r.records[normalizeRecordName(name)] = values
+	}
+	return r
+}
+
+// LoadSnapshotDNSResolver reads a snapshot for NewSnapshotDNSResolver from a
+// JSON file mapping record names to lists of TXT record values.
+func LoadSnapshotDNSResolver(path string) (DNSResolver, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read DNS snapshot: %v", err)
+	}
+	var records map[string][]string
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("unable to parse DNS snapshot: %v", err)
+	}
+	return NewSnapshotDNSResolver(records), nil
+}
+
+type snapshotDnsResolver struct {
+	records map[string][]string
+}
+
+func (r *snapshotDnsResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	records, _, err := r.LookupTXTWithTTL(ctx, name)
+	return records, err
+}
+
+func (r *snapshotDnsResolver) LookupTXTWithTTL(ctx context.Context, name string) ([]string, time.Duration, error) {
+	records := r.records[normalizeRecordName(name)]
+	if len(records) == 0 {
+		return nil, 0, ErrDNSRecordNotFound
+	}
+	return records, 0, nil
+}
+
+func normalizeRecordName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
@@ -0,0 +1,51 @@
+package discovery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSnapshotDNSResolver(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	if err := os.WriteFile(path, []byte(`{"_delivery._adscert.Example.com.": ["v=adcrtd k=x25519 h=sha256 p=key"]}`), 0644); err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+	resolver, err := LoadSnapshotDNSResolver(path)
+	if err != nil {
+		t.Fatalf("LoadSnapshotDNSResolver() unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		desc      string
+		name      string
+		wantFound bool
+	}{
+		{
+			desc:      "normalized name",
+			name:      "_delivery._adscert.example.com",
+			wantFound: true,
+		},
+		{
+			desc:      "fully qualified name",
+			name:      "_delivery._adscert.example.com.",
+			wantFound: true,
+		},
+		{
+			desc: "missing name",
+			name: "_delivery._adscert.other.com",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			records, err := resolver.LookupTXT(context.Background(), tC.name)
+			if gotFound := err == nil && len(records) == 1; gotFound != tC.wantFound {
+				t.Errorf("LookupTXT() %s: got records %v, err %v, want found %v", tC.desc, records, err, tC.wantFound)
+			}
+			if !tC.wantFound && !isRecordNotFound(err) {
+				t.Errorf("LookupTXT() %s: got err %v, want record not found", tC.desc, err)
+			}
+		})
+	}
+}
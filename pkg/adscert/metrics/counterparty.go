@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"sync"
+)
+
+// OtherCounterparty is the label value shared by counterparties beyond the
+// cardinality cap.
+const OtherCounterparty = "other"
+
+// LabelOptions enables the optional counterparty and key_alias labels on the
+// sign, verify and DNS lookup counters.  Disabled labels are left empty, which
+// Prometheus treats the same as an absent label.
+type LabelOptions struct {
+	// Counterparty labels metrics with the counterparty domain: the signature
+	// recipient when signing, the signer of an authenticated signature when
+	// verifying, and the domain looked up for DNS lookups.  Signatures which
+	// fail verification are labeled with an empty counterparty, since their
+	// signer is whatever the sender claims.
+	Counterparty bool
+
+	// KeyAlias additionally labels sign and verify metrics with the alias of
+	// the counterparty's public key, as published in its records.  It has no
+	// effect without Counterparty.
+	KeyAlias bool
+
+	// Allowlist names counterparties which always get their own label value.
+	Allowlist []string
+
+	// MaxCounterparties caps the number of other counterparties given their
+	// own label value, admitted in the order they are first seen.  Only
+	// domains we sign for, look up, or authenticate are admitted.  Once the cap
+	// is reached, further counterparties are labeled OtherCounterparty.  Zero
+	// admits none, so that only the allowlist is labeled; a negative value
+	// admits every counterparty.
+	MaxCounterparties int
+}
+
+// counterpartyGuard maps counterparty domains to label values, keeping the
+// number of distinct values bounded.
+type counterpartyGuard struct {
+	mutex    sync.RWMutex
+	options  LabelOptions
+	allowed  map[string]bool
+	admitted map[string]bool
+}
+
+var counterpartyLabels = &counterpartyGuard{}
+
+// SetLabelOptions configures the optional counterparty labels.  It should be
+// called at startup, before any metrics are recorded, since counterparties
+// admitted so far are forgotten.
+func SetLabelOptions(options LabelOptions) {
+	counterpartyLabels.setOptions(options)
+}
+
+func (g *counterpartyGuard) setOptions(options LabelOptions) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.options = options
+	g.allowed = map[string]bool{}
+	g.admitted = map[string]bool{}
+	for _, domain := range options.Allowlist {
+		g.allowed[domain] = true
+	}
+}
+
+// domain returns the label value for a domain, admitting it if the cap allows.
+func (g *counterpartyGuard) domain(domain string) string {
+	if domain == "" {
+		return domain
+	}
+
+	g.mutex.RLock()
+	ok := g.options.MaxCounterparties < 0 || g.allowed[domain] || g.admitted[domain]
+	g.mutex.RUnlock()
+	if ok {
+		return domain
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if g.admitted[domain] {
+		return domain
+	}
+	if len(g.admitted) < g.options.MaxCounterparties {
+		g.admitted[domain] = true
+		return domain
+	}
+	return OtherCounterparty
+}
+
+// counterparty returns the counterparty label value for metrics, or an empty
+// value when the label is disabled.
+func (g *counterpartyGuard) counterparty(domain string) string {
+	g.mutex.RLock()
+	enabled := g.options.Counterparty
+	g.mutex.RUnlock()
+	if !enabled {
+		return ""
+	}
+	return g.domain(domain)
+}
+
+// keyAlias returns the key_alias label value for a key of a counterparty
+// whose label value is counterpartyLabel.
+func (g *counterpartyGuard) keyAlias(counterpartyLabel string, keyAlias string) string {
+	g.mutex.RLock()
+	enabled := g.options.Counterparty && g.options.KeyAlias
+	g.mutex.RUnlock()
+	if !enabled {
+		return ""
+	}
+	if counterpartyLabel == OtherCounterparty {
+		return OtherCounterparty
+	}
+	return keyAlias
+}
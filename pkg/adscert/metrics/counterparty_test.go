@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCounterpartyGuard(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		options LabelOptions
+		domains []string
+		want    []string
+	}{
+		{
+			desc:    "disabled",
+			options: LabelOptions{MaxCounterparties: 10},
+			domains: []string{"a.com", "b.com"},
+			want:    []string{"", ""},
+		},
+		{
+			desc:    "allowlist only",
+			options: LabelOptions{Counterparty: true, Allowlist: []string{"b.com"}},
+			domains: []string{"a.com", "b.com"},
+			want:    []string{OtherCounterparty, "b.com"},
+		},
+		{
+			desc:    "first seen up to cap",
+			options: LabelOptions{Counterparty: true, Allowlist: []string{"c.com"}, MaxCounterparties: 1},
+			domains: []string{"a.com", "b.com", "c.com", "a.com"},
+			want:    []string{"a.com", OtherCounterparty, "c.com", "a.com"},
+		},
+		{
+			desc:    "uncapped",
+			options: LabelOptions{Counterparty: true, MaxCounterparties: -1},
+			domains: []string{"a.com", "b.com"},
+			want:    []string{"a.com", "b.com"},
+		},
+		{
+			desc:    "empty domain",
+			options: LabelOptions{Counterparty: true, MaxCounterparties: 1},
+			domains: []string{"", "a.com"},
+			want:    []string{"", "a.com"},
+		},
+	}
+	for _, tC := range testCases {
+		g := &counterpartyGuard{}
+		g.setOptions(tC.options)
+		for i, domain := range tC.domains {
+			if got := g.counterparty(domain); got != tC.want[i] {
+				t.Errorf("counterparty(%q) %s: got %q, want %q", domain, tC.desc, got, tC.want[i])
+			}
+		}
+	}
+}
+
+func TestCounterpartyGuard_KeyAlias(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		options LabelOptions
+		domain  string
+		want    string
+	}{
+		{"disabled", LabelOptions{Counterparty: true, MaxCounterparties: -1}, "a.com", ""},
+		{"without counterparty", LabelOptions{KeyAlias: true, MaxCounterparties: -1}, "a.com", ""},
+		{"enabled", LabelOptions{Counterparty: true, KeyAlias: true, MaxCounterparties: -1}, "a.com", "key1"},
+		{"other counterparty", LabelOptions{Counterparty: true, KeyAlias: true}, "a.com", OtherCounterparty},
+	}
+	for _, tC := range testCases {
+		g := &counterpartyGuard{}
+		g.setOptions(tC.options)
+		if got := g.keyAlias(g.counterparty(tC.domain), "key1"); got != tC.want {
+			t.Errorf("keyAlias() %s: got %q, want %q", tC.desc, got, tC.want)
+		}
+	}
+}
+
+func TestDomainCollector(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := &domainCollector{now: func() time.Time { return now }}
+	SetLabelOptions(LabelOptions{Allowlist: []string{"a.com"}})
+	defer SetLabelOptions(LabelOptions{})
+
+	c.source = func() []DomainState {
+		return []DomainState{
+			{Domain: "a.com", Status: "ok", LastRefresh: now.Add(-10 * time.Second)},
+			{Domain: "b.com", Status: "ok", LastRefresh: now.Add(-20 * time.Second)},
+			{Domain: "c.com", Status: "ok", LastRefresh: now.Add(-30 * time.Second)},
+			{Domain: "d.com", Status: "not_yet_checked"},
+		}
+	}
+
+	want := `
+# HELP adscert_domain_refresh_age_seconds Seconds since the records of a domain were last refreshed successfully, the oldest across domains sharing a label.
+# TYPE adscert_domain_refresh_age_seconds gauge
+adscert_domain_refresh_age_seconds{domain="a.com"} 10
+adscert_domain_refresh_age_seconds{domain="other"} 30
+# HELP adscert_domain_status The number of known domains in each status, per domain.
+# TYPE adscert_domain_status gauge
+adscert_domain_status{domain="a.com",status="ok"} 1
+adscert_domain_status{domain="other",status="not_yet_checked"} 1
+adscert_domain_status{domain="other",status="ok"} 2
+`
+	if err := testutil.CollectAndCompare(c, strings.NewReader(want)); err != nil {
+		t.Errorf("Collect(): %v", err)
+	}
+}
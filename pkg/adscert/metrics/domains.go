@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DomainState is the state of one counterparty domain known to the signatory.
+type DomainState struct {
+	Domain string
+	Status string
+
+	// LastRefresh is when the domain's records were last found, or zero if
+	// they never were.
+	LastRefresh time.Time
+}
+
+// DomainStateSource lists the state of every known domain.  It is called on
+// every scrape.
+type DomainStateSource func() []DomainState
+
+var (
+	domainStatusDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "domain_status"),
+		"The number of known domains in each status, per domain.",
+		[]string{domainLabel, domainStatusLabel}, nil)
+	domainRefreshAgeDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "domain_refresh_age_seconds"),
+		"Seconds since the records of a domain were last refreshed successfully, the oldest across domains sharing a label.",
+		[]string{domainLabel}, nil)
+)
+
+// domainCollector exports gauges for the domains listed by a DomainStateSource.
+// Domains are labeled subject to the same cardinality cap as counterparties,
+// so the domains sharing the OtherCounterparty label are aggregated.
+type domainCollector struct {
+	mutex  sync.RWMutex
+	source DomainStateSource
+	now    func() time.Time
+}
+
+var domainStates = &domainCollector{now: time.Now}
+
+// SetDomainStateSource sets the source of the domain_status and
+// domain_refresh_age_seconds gauges, which are empty until it's called.
+func SetDomainStateSource(source DomainStateSource) {
+	domainStates.mutex.Lock()
+	defer domainStates.mutex.Unlock()
+	domainStates.source = source
+}
+
+func (c *domainCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- domainStatusDesc
+	ch <- domainRefreshAgeDesc
+}
+
+func (c *domainCollector) Collect(ch chan<- prometheus.Metric) {
+	c.mutex.RLock()
+	source := c.source
+	c.mutex.RUnlock()
+	if source == nil {
+		return
+	}
+
+	type statusKey struct{ domain, status string }
+	statusCounts := map[statusKey]int{}
+	refreshAges := map[string]time.Duration{}
+	now := c.now()
+	for _, state := range source() {
+		domain := counterpartyLabels.domain(state.Domain)
+		statusCounts[statusKey{domain, state.Status}]++
+		if state.LastRefresh.IsZero() {
+			continue
+		}
+		if age := now.Sub(state.LastRefresh); age > refreshAges[domain] {
+			refreshAges[domain] = age
+		}
+	}
+
+	for key, count := range statusCounts {
+		ch <- prometheus.MustNewConstMetric(domainStatusDesc, prometheus.GaugeValue, float64(count), key.domain, key.status)
+	}
+	for domain, age := range refreshAges {
+		ch <- prometheus.MustNewConstMetric(domainRefreshAgeDesc, prometheus.GaugeValue, age.Seconds(), domain)
+	}
+}
@@ -12,9 +12,13 @@ const namespace string = "adscert"
 
 var standardMillisecondBuckets []float64 = []float64{1, 2, 5, 10, 25, 50, 100, 250, 500, 1000}
 var standardMicrosecondBuckets []float64 = []float64{10, 25, 50, 100, 250, 500, 1000, 2000, 5000, 10000}
+var signatureCountBuckets []float64 = []float64{0, 1, 2, 3, 4, 5, 10}
 
 // Labels
 const (
+	counterpartyLabel string = "counterparty"
+	keyAliasLabel     string = "key_alias"
+
 	dnsLookupErrorLabel string = "error"
 
 	signErrorLabel string = "error"
@@ -23,6 +27,15 @@ const (
 
 	verifyOutcomeTypeLabel  string = "type"
 	verifyOutcomeValidLabel string = "valid"
+
+	verifyDecodeStatusLabel string = "status"
+
+	auditOperationLabel string = "operation"
+	auditSampledLabel   string = "sampled"
+	auditFileStepLabel  string = "step"
+
+	domainLabel       string = "domain"
+	domainStatusLabel string = "status"
 )
 
 // Verification Outcome Type
@@ -42,7 +55,7 @@ var (
 		Namespace: namespace,
 		Name:      "dns_lookup_count",
 		Help:      "The total number of requests verified",
-	}, []string{dnsLookupErrorLabel})
+	}, []string{dnsLookupErrorLabel, counterpartyLabel})
 	DNSLookupTimeHistogram = prometheus.NewHistogram(prometheus.HistogramOpts{
 		Namespace: namespace,
 		Name:      "dns_lookup_ms",
@@ -53,7 +66,7 @@ var (
 		Namespace: namespace,
 		Name:      "sign_count",
 		Help:      "The total number of requests signed.",
-	}, []string{signErrorLabel})
+	}, []string{signErrorLabel, counterpartyLabel, keyAliasLabel})
 	SignTimeHistogram = prometheus.NewHistogram(prometheus.HistogramOpts{
 		Namespace: namespace,
 		Name:      "sign_time_us",
@@ -64,18 +77,54 @@ var (
 		Namespace: namespace,
 		Name:      "verify_count",
 		Help:      "The total number of requests verified.",
-	}, []string{verifyErrorLabel})
+	}, []string{verifyErrorLabel, counterpartyLabel, keyAliasLabel})
 	VerifyOutcomeCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Namespace: namespace,
 		Name:      "verify_outcome_count",
-		Help:      "The total number of valid signed requests.",
+		Help:      "The total number of signatures verified, by whether their body or URL was valid.",
 	}, []string{verifyOutcomeTypeLabel, verifyOutcomeValidLabel})
+	VerifyDecodeStatusCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "verify_decode_status_count",
+		Help:      "The total number of signatures verified, by decode status.",
+	}, []string{verifyDecodeStatusLabel, counterpartyLabel, keyAliasLabel})
+	VerifySignaturesHistogram = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "verify_signatures_per_request",
+		Help:      "Number of signatures carried by each request verified.",
+		Buckets:   signatureCountBuckets,
+	})
+	VerifyUnsignedCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "verify_unsigned_request_count",
+		Help:      "The total number of requests verified which carried no signature.",
+	})
+	ReplayCacheFullCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "replay_cache_full_count",
+		Help:      "The total number of signatures rejected because the replay cache was full of unexpired nonces.",
+	})
 	VerifyTimeHistogram = prometheus.NewHistogram(prometheus.HistogramOpts{
 		Namespace: namespace,
 		Name:      "verify_time_us",
 		Help:      "Microseconds to verify a request.",
 		Buckets:   standardMicrosecondBuckets,
 	})
+	AuditSamplingCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "audit_sampling_count",
+		Help:      "The total number of audit sampling decisions, by whether the event was kept.",
+	}, []string{auditOperationLabel, auditSampledLabel})
+	AuditDroppedCounter = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "audit_dropped_count",
+		Help:      "The total number of audit events dropped because the audit buffer was full.",
+	})
+	AuditFileErrorCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "audit_file_error_count",
+		Help:      "The total number of audit log file errors, by the failing step (open, rotate or write).",
+	}, []string{auditFileStepLabel})
 )
 
 // All metric collectors
@@ -86,7 +135,15 @@ var collectors []prometheus.Collector = []prometheus.Collector{
 	SignTimeHistogram,
 	VerifyCounter,
 	VerifyOutcomeCounter,
+	VerifyDecodeStatusCounter,
+	VerifySignaturesHistogram,
+	VerifyUnsignedCounter,
+	ReplayCacheFullCounter,
 	VerifyTimeHistogram,
+	AuditSamplingCounter,
+	AuditDroppedCounter,
+	AuditFileErrorCounter,
+	domainStates,
 }
 
 func init() {
@@ -100,7 +157,7 @@ func GetAdscertMetricsRegistry() *prometheus.Registry {
 	return adscertMetricsRegistry
 }
 
-func RecordDNSLookup(err adscerterrors.DiscoveryErrorCode) {
+func RecordDNSLookup(domain string, err adscerterrors.DiscoveryErrorCode) {
 	var DNSLookupErr string
 
 	if err != nil {
@@ -109,6 +166,7 @@ func RecordDNSLookup(err adscerterrors.DiscoveryErrorCode) {
 
 	DNSLookupCounter.With(prometheus.Labels{
 		dnsLookupErrorLabel: DNSLookupErr,
+		counterpartyLabel:   counterpartyLabels.counterparty(domain),
 	}).Inc()
 }
 
@@ -116,15 +174,18 @@ func RecordDNSLookupTime(observeTime time.Duration) {
 	DNSLookupTimeHistogram.Observe(float64(observeTime.Milliseconds()))
 }
 
-func RecordSigning(err adscerterrors.SigningErrorCode) {
+func RecordSigning(err adscerterrors.SigningErrorCode, counterparty string, keyAlias string) {
 	var signError string
 
 	if err != nil {
 		signError = err.Code
 	}
 
+	counterparty = counterpartyLabels.counterparty(counterparty)
 	SignCounter.With(prometheus.Labels{
-		signErrorLabel: signError,
+		signErrorLabel:    signError,
+		counterpartyLabel: counterparty,
+		keyAliasLabel:     counterpartyLabels.keyAlias(counterparty, keyAlias),
 	}).Inc()
 }
 
@@ -132,15 +193,20 @@ func RecordSigningTime(observeTime time.Duration) {
 	SignTimeHistogram.Observe(float64(observeTime.Microseconds()))
 }
 
-func RecordVerify(err adscerterrors.VerifyErrorCode) {
+// RecordVerify records the verification of a signature.  The counterparty and
+// key alias must come from an authenticated signature, or be empty.
+func RecordVerify(err adscerterrors.VerifyErrorCode, counterparty string, keyAlias string) {
 	var verifyError string
 
 	if err != nil {
 		verifyError = err.Code
 	}
 
+	counterparty = counterpartyLabels.counterparty(counterparty)
 	VerifyCounter.With(prometheus.Labels{
-		verifyErrorLabel: verifyError,
+		verifyErrorLabel:  verifyError,
+		counterpartyLabel: counterparty,
+		keyAliasLabel:     counterpartyLabels.keyAlias(counterparty, keyAlias),
 	}).Inc()
 }
 
@@ -151,6 +217,46 @@ func RecordVerifyOutcome(t verifyOutcomeType, valid bool) {
 	}).Inc()
 }
 
+// RecordVerifyDecodeStatus records the decode status of a verified signature.
+// The counterparty and key alias must come from an authenticated signature,
+// or be empty.
+func RecordVerifyDecodeStatus(status string, counterparty string, keyAlias string) {
+	counterparty = counterpartyLabels.counterparty(counterparty)
+	VerifyDecodeStatusCounter.With(prometheus.Labels{
+		verifyDecodeStatusLabel: status,
+		counterpartyLabel:       counterparty,
+		keyAliasLabel:           counterpartyLabels.keyAlias(counterparty, keyAlias),
+	}).Inc()
+}
+
+// RecordVerifySignatureCount records how many signatures a verified request
+// carried, counting requests carrying none as unsigned.
+func RecordVerifySignatureCount(count int) {
+	VerifySignaturesHistogram.Observe(float64(count))
+	if count == 0 {
+		VerifyUnsignedCounter.Inc()
+	}
+}
+
+func RecordReplayCacheFull() {
+	ReplayCacheFullCounter.Inc()
+}
+
 func RecordVerifyTime(observeTime time.Duration) {
 	VerifyTimeHistogram.Observe(float64(observeTime.Microseconds()))
 }
+
+func RecordAuditSampling(operation string, sampled bool) {
+	AuditSamplingCounter.With(prometheus.Labels{
+		auditOperationLabel: operation,
+		auditSampledLabel:   strconv.FormatBool(sampled),
+	}).Inc()
+}
+
+func RecordAuditDropped() {
+	AuditDroppedCounter.Inc()
+}
+
+func RecordAuditFileError(step string) {
+	AuditFileErrorCounter.With(prometheus.Labels{auditFileStepLabel: step}).Inc()
+}
@@ -2,23 +2,213 @@ package server
 
 import (
 	"context"
+	"io"
+	"sync"
 
 	"github.com/IABTechLab/adscert/pkg/adscert/api"
 	"github.com/IABTechLab/adscert/pkg/adscert/signatory"
 )
 
+// DefaultMaxStreamConcurrency is the number of requests of one stream served
+// at once when AdsCertSignatoryServer.MaxStreamConcurrency isn't set.
+const DefaultMaxStreamConcurrency = 64
+
 type AdsCertSignatoryServer struct {
 	api.UnimplementedAdsCertSignatoryServer
 
 	SignatoryAPI *signatory.LocalAuthenticatedConnectionsSignatory
+
+	// MaxStreamConcurrency bounds the number of requests of one SignStream
+	// or VerifyStream call served at once.  Further requests aren't read
+	// from the stream until one completes.  Defaults to
+	// DefaultMaxStreamConcurrency.
+	MaxStreamConcurrency int
 }
 
 func (s *AdsCertSignatoryServer) SignAuthenticatedConnection(ctx context.Context, req *api.AuthenticatedConnectionSignatureRequest) (*api.AuthenticatedConnectionSignatureResponse, error) {
-	response, err := s.SignatoryAPI.SignAuthenticatedConnection(req)
+	response, err := s.SignatoryAPI.SignAuthenticatedConnectionContext(ctx, req)
+	return response, err
+}
+
+func (s *AdsCertSignatoryServer) SignAuthenticatedConnectionBatch(ctx context.Context, req *api.AuthenticatedConnectionBatchSignatureRequest) (*api.AuthenticatedConnectionBatchSignatureResponse, error) {
+	response, err := s.SignatoryAPI.SignAuthenticatedConnectionBatchContext(ctx, req)
 	return response, err
 }
 
 func (s *AdsCertSignatoryServer) VerifyAuthenticatedConnection(ctx context.Context, req *api.AuthenticatedConnectionVerificationRequest) (*api.AuthenticatedConnectionVerificationResponse, error) {
-	response, err := s.SignatoryAPI.VerifyAuthenticatedConnection(req)
+	response, err := s.SignatoryAPI.VerifyAuthenticatedConnectionContext(ctx, req)
 	return response, err
 }
+
+// SignStream signs the requests received on the stream concurrently, up to
+// MaxStreamConcurrency at a time, sending responses as they complete, tagged
+// with the request's ID.  It stops reading requests once a response fails to
+// send and returns that error.
+func (s *AdsCertSignatoryServer) SignStream(stream api.AdsCertSignatory_SignStreamServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	workers := newStreamWorkers(s.maxStreamConcurrency(), cancel)
+
+	for {
+		streamRequest, err := stream.Recv()
+		if err == io.EOF {
+			return workers.wait()
+		} else if err != nil {
+			if sendErr := workers.wait(); sendErr != nil {
+				return sendErr
+			}
+			return err
+		}
+
+		started := workers.start(func() {
+			req := streamRequest.Request
+			if req == nil {
+				req = &api.AuthenticatedConnectionSignatureRequest{}
+			}
+			response, err := s.SignatoryAPI.SignAuthenticatedConnectionContext(ctx, req)
+			streamResponse := &api.SignStreamResponse{RequestId: streamRequest.RequestId, Response: response}
+			if err != nil {
+				streamResponse.Error = err.Error()
+			}
+			workers.send(func() error { return stream.Send(streamResponse) })
+		})
+		if !started {
+			return workers.wait()
+		}
+	}
+}
+
+// VerifyStream verifies the requests received on the stream concurrently, up
+// to MaxStreamConcurrency at a time, sending responses as they complete,
+// tagged with the request's ID.  It stops reading requests once a response
+// fails to send and returns that error.
+func (s *AdsCertSignatoryServer) VerifyStream(stream api.AdsCertSignatory_VerifyStreamServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	workers := newStreamWorkers(s.maxStreamConcurrency(), cancel)
+
+	for {
+		streamRequest, err := stream.Recv()
+		if err == io.EOF {
+			return workers.wait()
+		} else if err != nil {
+			if sendErr := workers.wait(); sendErr != nil {
+				return sendErr
+			}
+			return err
+		}
+
+		started := workers.start(func() {
+			req := streamRequest.Request
+			if req == nil {
+				req = &api.AuthenticatedConnectionVerificationRequest{}
+			}
+			response, err := s.SignatoryAPI.VerifyAuthenticatedConnectionContext(ctx, req)
+			streamResponse := &api.VerifyStreamResponse{RequestId: streamRequest.RequestId, Response: response}
+			if err != nil {
+				streamResponse.Error = err.Error()
+			}
+			workers.send(func() error { return stream.Send(streamResponse) })
+		})
+		if !started {
+			return workers.wait()
+		}
+	}
+}
+
+func (s *AdsCertSignatoryServer) maxStreamConcurrency() int {
+	if s.MaxStreamConcurrency > 0 {
+		return s.MaxStreamConcurrency
+	}
+	return DefaultMaxStreamConcurrency
+}
+
+// streamWorkers serves the requests of one stream on a bounded number of
+// goroutines, serializing their responses and keeping the first error sending
+// one.
+type streamWorkers struct {
+	slots  chan struct{}
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+
+	sendMutex sync.Mutex
+	sendErr   error
+	failed    chan struct{} // closed once sendErr is set
+}
+
+func newStreamWorkers(maxConcurrency int, cancel context.CancelFunc) *streamWorkers {
+	return &streamWorkers{
+		slots:  make(chan struct{}, maxConcurrency),
+		cancel: cancel,
+		failed: make(chan struct{}),
+	}
+}
+
+// start runs serve on its own goroutine once fewer than the maximum number of
+// requests are being served.  It returns false without running serve if a
+// response has failed to send.
+func (w *streamWorkers) start(serve func()) bool {
+	select {
+	case <-w.failed:
+		return false
+	default:
+	}
+	select {
+	case <-w.failed:
+		return false
+	case w.slots <- struct{}{}:
+	}
+
+	w.wg.Add(1)
+	go func() {
+		defer func() {
+			<-w.slots
+			w.wg.Done()
+		}()
+		serve()
+	}()
+	return true
+}
+
+// send sends a response unless an earlier one failed to send.  A failure
+// cancels the requests still being served.
+func (w *streamWorkers) send(send func() error) {
+	w.sendMutex.Lock()
+	defer w.sendMutex.Unlock()
+	if w.sendErr != nil {
+		return
+	}
+	if err := send(); err != nil {
+		w.sendErr = err
+		close(w.failed)
+		w.cancel()
+	}
+}
+
+// wait waits for the requests being served and returns the first error
+// sending a response, if any.
+func (w *streamWorkers) wait() error {
+	w.wg.Wait()
+	w.sendMutex.Lock()
+	defer w.sendMutex.Unlock()
+	return w.sendErr
+}
+
+// AdsCertSignatoryAdminServer serves the administrative RPCs, which change the
+// signatory's behavior at runtime.  The RPCs are unauthenticated, so only
+// register it on servers reachable by trusted clients.  Deactivation settings
+// set here last until the deactivation file, if any, is next reloaded.
+type AdsCertSignatoryAdminServer struct {
+	api.UnimplementedAdsCertSignatoryAdminServer
+
+	SignatoryAPI *signatory.LocalAuthenticatedConnectionsSignatory
+}
+
+func (s *AdsCertSignatoryAdminServer) SetDeactivation(ctx context.Context, req *api.SetDeactivationRequest) (*api.SetDeactivationResponse, error) {
+	s.SignatoryAPI.SetDeactivation(req.Settings)
+	return &api.SetDeactivationResponse{Settings: s.SignatoryAPI.GetDeactivation()}, nil
+}
+
+func (s *AdsCertSignatoryAdminServer) GetDeactivation(ctx context.Context, req *api.GetDeactivationRequest) (*api.GetDeactivationResponse, error) {
+	return &api.GetDeactivationResponse{Settings: s.SignatoryAPI.GetDeactivation()}, nil
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/IABTechLab/adscert/pkg/adscert/api"
+	"github.com/IABTechLab/adscert/pkg/adscert/discovery"
+	"github.com/IABTechLab/adscert/pkg/adscert/signatory"
+	"github.com/benbjohnson/clock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testCallsign = "signer.com"
+
+type fakeDNSResolver struct{}
+
+func (r fakeDNSResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	records, _, err := r.LookupTXTWithTTL(ctx, name)
+	return records, err
+}
+
+func (r fakeDNSResolver) LookupTXTWithTTL(ctx context.Context, name string) ([]string, time.Duration, error) {
+	if name == "_delivery._adscert."+testCallsign {
+		return []string{signatory.GenerateFakeAdsCertRecordForTesting(testCallsign)}, time.Minute, nil
+	}
+	return nil, 0, discovery.ErrDNSRecordNotFound
+}
+
+func newStreamingTestClient(t *testing.T) signatory.ContextAuthenticatedConnectionsSignatory {
+	_, privateKey := signatory.GenerateFakeKeyPairFromDomainNameForTesting(testCallsign)
+	signatoryApi := signatory.NewLocalAuthenticatedConnectionsSignatoryWithOptions(
+		testCallsign, rand.Reader, clock.New(), fakeDNSResolver{}, discovery.NewDefaultDomainStore(),
+		[]string{base64.RawURLEncoding.EncodeToString(privateKey[:])},
+		&signatory.LocalAuthenticatedConnectionsSignatoryOptions{SynchronousLookupTimeout: time.Second})
+
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	api.RegisterAdsCertSignatoryServer(grpcServer, &AdsCertSignatoryServer{SignatoryAPI: signatoryApi})
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.Dial() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	client := signatory.NewAuthenticatedConnectionsSignatoryClient(conn, &signatory.AuthenticatedConnectionsSignatoryClientOptions{
+		Timeout:   5 * time.Second,
+		Streaming: true,
+	})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestStreams(t *testing.T) {
+	client := newStreamingTestClient(t)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			requestInfo := &api.RequestInfo{}
+			signatory.SetRequestInfo(requestInfo, fmt.Sprintf("https://%s/bid?id=%d", testCallsign, i), []byte(fmt.Sprintf("body %d", i)))
+
+			signResponse, err := client.SignAuthenticatedConnectionContext(ctx, &api.AuthenticatedConnectionSignatureRequest{RequestInfo: requestInfo})
+			if err != nil {
+				t.Errorf("SignAuthenticatedConnectionContext() %d: unexpected error: %v", i, err)
+				return
+			}
+			if signResponse.SignatureOperationStatus != api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK {
+				t.Errorf("SignAuthenticatedConnectionContext() %d: got status %v, want OK", i, signResponse.SignatureOperationStatus)
+				return
+			}
+
+			verifyResponse, err := client.VerifyAuthenticatedConnectionContext(ctx, &api.AuthenticatedConnectionVerificationRequest{
+				RequestInfo: []*api.RequestInfo{signResponse.RequestInfo},
+			})
+			if err != nil {
+				t.Errorf("VerifyAuthenticatedConnectionContext() %d: unexpected error: %v", i, err)
+				return
+			}
+			want := api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID
+			if got := verifyResponse.VerificationInfo[0].SignatureDecodeStatus[0]; got != want {
+				t.Errorf("VerifyAuthenticatedConnectionContext() %d: got %v, want %v", i, got, want)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	// Requests failing as a whole fail the call, as unary calls would.
+	if _, err := client.SignAuthenticatedConnectionContext(ctx, &api.AuthenticatedConnectionSignatureRequest{RequestInfo: &api.RequestInfo{}}); err == nil {
+		t.Errorf("SignAuthenticatedConnectionContext() malformed request: got no error, want error")
+	}
+}
+
+// failingSignStream serves an endless stream of signing requests whose
+// responses fail to send.
+type failingSignStream struct {
+	grpc.ServerStream
+	ctx context.Context
+
+	received int32
+}
+
+func (s *failingSignStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *failingSignStream) Recv() (*api.SignStreamRequest, error) {
+	atomic.AddInt32(&s.received, 1)
+	return &api.SignStreamRequest{Request: &api.AuthenticatedConnectionSignatureRequest{RequestInfo: &api.RequestInfo{}}}, nil
+}
+
+func (s *failingSignStream) Send(*api.SignStreamResponse) error {
+	return errSendFailed
+}
+
+var errSendFailed = errors.New("send failed")
+
+func TestSignStream_SendError(t *testing.T) {
+	_, privateKey := signatory.GenerateFakeKeyPairFromDomainNameForTesting(testCallsign)
+	signatoryApi := signatory.NewLocalAuthenticatedConnectionsSignatoryWithOptions(
+		testCallsign, rand.Reader, clock.New(), fakeDNSResolver{}, discovery.NewDefaultDomainStore(),
+		[]string{base64.RawURLEncoding.EncodeToString(privateKey[:])},
+		&signatory.LocalAuthenticatedConnectionsSignatoryOptions{})
+	server := &AdsCertSignatoryServer{SignatoryAPI: signatoryApi, MaxStreamConcurrency: 2}
+
+	stream := &failingSignStream{ctx: context.Background()}
+	done := make(chan error, 1)
+	go func() { done <- server.SignStream(stream) }()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, errSendFailed) {
+			t.Errorf("SignStream(): got %v, want %v", err, errSendFailed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("SignStream(): still reading requests after a send failed")
+	}
+}
+
+func TestStreamWorkers(t *testing.T) {
+	const maxConcurrency = 2
+	workers := newStreamWorkers(maxConcurrency, func() {})
+
+	var running, maxRunning int32
+	release := make(chan struct{})
+	serve := func() {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		<-release
+		atomic.AddInt32(&running, -1)
+	}
+
+	for i := 0; i < maxConcurrency; i++ {
+		if !workers.start(serve) {
+			t.Fatalf("start() %d: got false, want true", i)
+		}
+	}
+
+	// A further request waits for a worker to be free.
+	started := make(chan bool, 1)
+	go func() { started <- workers.start(serve) }()
+	select {
+	case <-started:
+		t.Fatalf("start() beyond the maximum: didn't wait for a free worker")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	if !<-started {
+		t.Errorf("start() once a worker is free: got false, want true")
+	}
+	if err := workers.wait(); err != nil {
+		t.Errorf("wait(): unexpected error: %v", err)
+	}
+	if got := atomic.LoadInt32(&maxRunning); got > maxConcurrency {
+		t.Errorf("start(): got %d requests served at once, want at most %d", got, maxConcurrency)
+	}
+
+	// Once a response fails to send, no more requests are started.
+	workers.send(func() error { return errSendFailed })
+	workers.send(func() error {
+		t.Errorf("send() after a failure: sent another response")
+		return nil
+	})
+	if workers.start(serve) {
+		t.Errorf("start() after a send failure: got true, want false")
+	}
+	if err := workers.wait(); !errors.Is(err, errSendFailed) {
+		t.Errorf("wait() after a send failure: got %v, want %v", err, errSendFailed)
+	}
+}
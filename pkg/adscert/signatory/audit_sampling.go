@@ -0,0 +1,124 @@
+package signatory
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/IABTechLab/adscert/pkg/adscert/api"
+)
+
+// Outcome classes matched by AuditSamplingRule.Outcome besides specific
+// outcome names.
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditSampler returns the probability, between 0 and 1, with which an event
+// is audited.
+type AuditSampler func(event *AuditEvent) float64
+
+// AuditSamplingRule sets the sampling rate of the events it matches.
+type AuditSamplingRule struct {
+	// Counterparty matches the event's counterparty.  Empty matches any.
+	Counterparty string
+
+	// Outcome matches AuditOutcomeSuccess, AuditOutcomeFailure or the name of
+	// the event's outcome.  Empty matches any.
+	Outcome string
+
+	Rate float64
+}
+
+func (r AuditSamplingRule) matches(event *AuditEvent) bool {
+	if r.Counterparty != "" && r.Counterparty != event.Counterparty {
+		return false
+	}
+	switch r.Outcome {
+	case "":
+		return true
+	case AuditOutcomeSuccess:
+		return event.Succeeded()
+	case AuditOutcomeFailure:
+		return !event.Succeeded()
+	}
+	return r.Outcome == event.Outcome
+}
+
+// NewAuditSampler returns an AuditSampler applying the rate of the first
+// matching rule, or defaultRate when none match.
+func NewAuditSampler(rules []AuditSamplingRule, defaultRate float64) AuditSampler {
+	return func(event *AuditEvent) float64 {
+		for _, rule := range rules {
+			if rule.matches(event) {
+				return rule.Rate
+			}
+		}
+		return defaultRate
+	}
+}
+
+// ParseAuditSamplingRules parses comma-separated rules of the form
+// [counterparty:]outcome=rate, where outcome is success, failure, an outcome
+// name or * for any, such as "failure=1,partner.com:success=0.1,success=0.001".
+func ParseAuditSamplingRules(spec string) ([]AuditSamplingRule, error) {
+	var rules []AuditSamplingRule
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("malformed audit sampling rule %q, expected [counterparty:]outcome=rate", entry)
+		}
+		rate, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("invalid rate in audit sampling rule %q, expected a number from 0 to 1", entry)
+		}
+
+		rule := AuditSamplingRule{Outcome: parts[0], Rate: rate}
+		if matcher := strings.SplitN(parts[0], ":", 2); len(matcher) == 2 {
+			rule.Counterparty, rule.Outcome = matcher[0], matcher[1]
+		}
+		if rule.Outcome == "*" {
+			rule.Outcome = ""
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Succeeded reports whether the event records a signature produced, or one
+// verified as valid.
+func (e *AuditEvent) Succeeded() bool {
+	if e.Operation == AuditOperationSign {
+		return e.Outcome == api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK.String()
+	}
+	return e.ErrorCode == "" && e.Outcome != api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_UNDEFINED.String()
+}
+
+// shouldAudit makes the sampling decision for an event.
+func shouldAudit(sampler AuditSampler, event *AuditEvent) bool {
+	if sampler == nil {
+		return true
+	}
+	rate := sampler(event)
+	return rate >= 1 || (rate > 0 && rand.Float64() < rate)
+}
+
+// redactSignature replaces a signature by its HMAC under key, which still
+// allows audit events for the same signature to be correlated.
+func redactSignature(key []byte, signature string) string {
+	if signature == "" {
+		return ""
+	}
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(signature))
+	return RedactedSignaturePrefix + base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
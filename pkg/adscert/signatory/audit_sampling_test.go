@@ -0,0 +1,90 @@
+package signatory
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/IABTechLab/adscert/pkg/adscert/api"
+)
+
+func TestParseAuditSamplingRules(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		spec    string
+		want    []AuditSamplingRule
+		wantErr bool
+	}{
+		{
+			desc: "empty",
+			spec: "",
+		},
+		{
+			desc: "outcome classes",
+			spec: "failure=1, success=0.001",
+			want: []AuditSamplingRule{
+				{Outcome: AuditOutcomeFailure, Rate: 1},
+				{Outcome: AuditOutcomeSuccess, Rate: 0.001},
+			},
+		},
+		{
+			desc: "counterparty and any outcome",
+			spec: "partner.com:*=0.5,other.com:SIGNATURE_DECODE_STATUS_REPLAYED=1",
+			want: []AuditSamplingRule{
+				{Counterparty: "partner.com", Rate: 0.5},
+				{Counterparty: "other.com", Outcome: "SIGNATURE_DECODE_STATUS_REPLAYED", Rate: 1},
+			},
+		},
+		{
+			desc:    "missing rate",
+			spec:    "failure",
+			wantErr: true,
+		},
+		{
+			desc:    "rate out of range",
+			spec:    "success=2",
+			wantErr: true,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got, err := ParseAuditSamplingRules(tC.spec)
+			if (err != nil) != tC.wantErr {
+				t.Fatalf("ParseAuditSamplingRules(%q): got error %v, want error %v", tC.spec, err, tC.wantErr)
+			}
+			if !reflect.DeepEqual(got, tC.want) {
+				t.Errorf("ParseAuditSamplingRules(%q): got %+v, want %+v", tC.spec, got, tC.want)
+			}
+		})
+	}
+}
+
+func TestNewAuditSampler(t *testing.T) {
+	sampler := NewAuditSampler([]AuditSamplingRule{
+		{Counterparty: "partner.com", Rate: 0.5},
+		{Outcome: AuditOutcomeFailure, Rate: 1},
+		{Outcome: AuditOutcomeSuccess, Rate: 0.001},
+	}, 0.25)
+
+	signOK := api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK.String()
+	valid := api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID.String()
+	testCases := []struct {
+		desc  string
+		event *AuditEvent
+		want  float64
+	}{
+		{"counterparty rule first", &AuditEvent{Operation: AuditOperationVerify, Counterparty: "partner.com", Outcome: valid}, 0.5},
+		{"verify success", &AuditEvent{Operation: AuditOperationVerify, Counterparty: "other.com", Outcome: valid}, 0.001},
+		{"verify failure", &AuditEvent{Operation: AuditOperationVerify, Counterparty: "other.com", Outcome: api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_UNDEFINED.String(), ErrorCode: "replayed"}, 1},
+		{"sign success", &AuditEvent{Operation: AuditOperationSign, Counterparty: "other.com", Outcome: signOK}, 0.001},
+		{"sign failure", &AuditEvent{Operation: AuditOperationSign, Outcome: api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_SIGNATORY_INTERNAL_ERROR.String()}, 1},
+	}
+	for _, tC := range testCases {
+		if got := sampler(tC.event); got != tC.want {
+			t.Errorf("AuditSampler() %s: got %v, want %v", tC.desc, got, tC.want)
+		}
+	}
+
+	if got := NewAuditSampler(nil, 0.25)(&AuditEvent{}); got != 0.25 {
+		t.Errorf("AuditSampler() without rules: got %v, want %v", got, 0.25)
+	}
+}
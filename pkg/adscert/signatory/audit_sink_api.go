@@ -0,0 +1,64 @@
+package signatory
+
+import "time"
+
+// Operations recorded in AuditEvent.Operation.
+const (
+	AuditOperationSign   = "sign"
+	AuditOperationVerify = "verify"
+)
+
+// RedactedSignaturePrefix starts the signatures of audit events redacted with
+// an AuditRedactionKey.
+const RedactedSignaturePrefix = "hmac-sha256:"
+
+// AuditEvent records one signature produced or checked by the signatory.  A
+// signing request yields one event per signature, or a single event without a
+// signature when none was produced, and a verification request yields one
+// event per signature checked.
+//
+// Events are written as JSON objects with the field names below, one per
+// line.  Hashes are standard base64 encoded SHA-256 hashes, so that a log of
+// verify events can be replayed with "adscert verifylog --input_format jsonl".
+// Redaction disables replay: with an AuditRedactionKey, signatures are
+// replaced by an HMAC starting with RedactedSignaturePrefix, and with
+// AuditOmitHashes the hashes are left out.  verifylog skips and counts
+// redacted events.
+type AuditEvent struct {
+	Time time.Time `json:"time"`
+
+	// Operation is AuditOperationSign or AuditOperationVerify.
+	Operation string `json:"operation"`
+
+	InvokingDomain string `json:"invoking_domain"`
+
+	// Counterparty is the other party: the recipient when signing, and the
+	// signer when verifying.  It is empty if no signature could be decoded.
+	Counterparty string `json:"counterparty"`
+
+	FromDomain string `json:"from_domain,omitempty"`
+	FromKey    string `json:"from_key,omitempty"`
+	ToDomain   string `json:"to_domain,omitempty"`
+	ToKey      string `json:"to_key,omitempty"`
+
+	BodyHash  string `json:"body_hash"`
+	URLHash   string `json:"url_hash"`
+	Signature string `json:"signature,omitempty"`
+
+	// Outcome is the SignatureOperationStatus of a signing request or the
+	// SignatureDecodeStatus of a verified signature, by name.  ErrorCode
+	// explains failed verifications.
+	Outcome   string `json:"outcome"`
+	ErrorCode string `json:"error_code,omitempty"`
+
+	// LatencyMicros is how long the operation took, in microseconds.
+	LatencyMicros int64 `json:"latency_us"`
+}
+
+// AuditSink receives an AuditEvent after every signature signed or verified,
+// unless sampled out by the signatory's AuditSampler.
+// It is called on the request path, so implementations that do I/O should be
+// wrapped in an AsyncAuditSink.  Events must not be modified.
+type AuditSink interface {
+	Audit(event *AuditEvent)
+}
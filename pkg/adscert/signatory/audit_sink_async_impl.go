@@ -0,0 +1,63 @@
+package signatory
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/IABTechLab/adscert/pkg/adscert/metrics"
+)
+
+// NewAsyncAuditSink returns an AuditSink which queues events for next in a
+// buffer of bufferSize events, delivering them from a background goroutine.
+// Events arriving while the buffer is full are dropped rather than delaying
+// requests, and counted.
+func NewAsyncAuditSink(next AuditSink, bufferSize int) *AsyncAuditSink {
+	s := &AsyncAuditSink{
+		next:   next,
+		events: make(chan *AuditEvent, bufferSize),
+		done:   make(chan struct{}),
+	}
+	go s.deliver()
+	return s
+}
+
+// AsyncAuditSink is an AuditSink delivering events to another in the
+// background.
+type AsyncAuditSink struct {
+	next    AuditSink
+	events  chan *AuditEvent
+	done    chan struct{}
+	dropped uint64
+
+	closeOnce sync.Once
+}
+
+func (s *AsyncAuditSink) Audit(event *AuditEvent) {
+	select {
+	case s.events <- event:
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+		metrics.RecordAuditDropped()
+	}
+}
+
+// Dropped returns the number of events dropped because the buffer was full.
+func (s *AsyncAuditSink) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Close delivers the events already queued and stops the sink.  No events may
+// be audited after Close.
+func (s *AsyncAuditSink) Close() {
+	s.closeOnce.Do(func() {
+		close(s.events)
+		<-s.done
+	})
+}
+
+func (s *AsyncAuditSink) deliver() {
+	defer close(s.done)
+	for event := range s.events {
+		s.next.Audit(event)
+	}
+}
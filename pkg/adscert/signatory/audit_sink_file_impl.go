@@ -0,0 +1,125 @@
+package signatory
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/IABTechLab/adscert/pkg/adscert/logger"
+	"github.com/IABTechLab/adscert/pkg/adscert/metrics"
+)
+
+// NewRotatingFileAuditSink returns an AuditSink appending events as JSON lines
+// to the file at path.  Once the file reaches maxBytes it is renamed to
+// path.1, shifting older files up to path.<maxBackups> and removing the
+// oldest, and a new file is started.  A maxBytes of zero never rotates.
+//
+// If rotating or reopening the file fails, events are dropped until the file
+// can be opened again, which is retried on each event.  Failures are counted
+// in the audit_file_error_count metric.
+//
+// Writes are synchronous, so the sink is usually wrapped in an AsyncAuditSink.
+func NewRotatingFileAuditSink(path string, maxBytes int64, maxBackups int) (*RotatingFileAuditSink, error) {
+	s := &RotatingFileAuditSink{path: path, maxBytes: maxBytes, maxBackups: maxBackups}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// RotatingFileAuditSink is an AuditSink writing JSONL files.
+type RotatingFileAuditSink struct {
+	path       string
+	maxBytes   int64
+	maxBackups int
+
+	mutex  sync.Mutex
+	file   *os.File // nil after a failed rotation or reopening
+	size   int64
+	closed bool
+}
+
+func (s *RotatingFileAuditSink) Audit(event *AuditEvent) {
+	line, err := json.Marshal(event)
+	if err != nil {
+		logger.Errorf("unable to encode audit event: %v", err)
+		return
+	}
+	line = append(line, '\n')
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return
+	}
+	if s.file != nil && s.maxBytes > 0 && s.size > 0 && s.size+int64(len(line)) > s.maxBytes {
+		if err := s.rotate(); err != nil {
+			logger.Errorf("unable to rotate audit log %s: %v", s.path, err)
+			metrics.RecordAuditFileError("rotate")
+		}
+	}
+	if s.file == nil {
+		if err := s.open(); err != nil {
+			logger.Errorf("dropping audit event: %v", err)
+			metrics.RecordAuditFileError("open")
+			return
+		}
+	}
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	if err != nil {
+		logger.Errorf("unable to write audit log %s: %v", s.path, err)
+		metrics.RecordAuditFileError("write")
+	}
+}
+
+// Close closes the current file.  Events audited afterwards are discarded.
+func (s *RotatingFileAuditSink) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.closed = true
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+func (s *RotatingFileAuditSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("unable to open audit log: %v", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("unable to open audit log: %v", err)
+	}
+	s.file = file
+	s.size = info.Size()
+	return nil
+}
+
+func (s *RotatingFileAuditSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	s.file = nil
+
+	if s.maxBackups > 0 {
+		os.Remove(fmt.Sprintf("%s.%d", s.path, s.maxBackups))
+		for i := s.maxBackups - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", s.path, i), fmt.Sprintf("%s.%d", s.path, i+1))
+		}
+		if err := os.Rename(s.path, s.path+".1"); err != nil {
+			return err
+		}
+	} else if err := os.Remove(s.path); err != nil {
+		return err
+	}
+	return s.open()
+}
@@ -0,0 +1,147 @@
+package signatory
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/IABTechLab/adscert/pkg/adscert/metrics"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func countAuditLines(t *testing.T, path string) int {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open(%s) unexpected error: %v", path, err)
+	}
+	defer file.Close()
+
+	var lines int
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event AuditEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Errorf("Unmarshal() %s line %d: unexpected error: %v", path, lines+1, err)
+		}
+		lines++
+	}
+	return lines
+}
+
+func TestRotatingFileAuditSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	event := &AuditEvent{Operation: AuditOperationVerify, InvokingDomain: "verifier.com", Outcome: "SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID"}
+	line, _ := json.Marshal(event)
+
+	// Room for two events per file.
+	sink, err := NewRotatingFileAuditSink(path, int64(2*(len(line)+1)), 2)
+	if err != nil {
+		t.Fatalf("NewRotatingFileAuditSink() unexpected error: %v", err)
+	}
+	async := NewAsyncAuditSink(sink, 10)
+	for i := 0; i < 7; i++ {
+		async.Audit(event)
+	}
+	async.Close()
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+
+	testCases := []struct {
+		path      string
+		wantLines int
+	}{
+		{path, 1},
+		{path + ".1", 2},
+		{path + ".2", 2},
+	}
+	for _, tC := range testCases {
+		if got := countAuditLines(t, tC.path); got != tC.wantLines {
+			t.Errorf("RotatingFileAuditSink %s: got %d lines, want %d", tC.path, got, tC.wantLines)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("RotatingFileAuditSink: got %s.3, want at most 2 backups", path)
+	}
+}
+
+func TestRotatingFileAuditSink_Reopens(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "audit")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatalf("Mkdir() unexpected error: %v", err)
+	}
+	path := filepath.Join(dir, "audit.jsonl")
+	event := &AuditEvent{Operation: AuditOperationVerify, InvokingDomain: "verifier.com"}
+	line, _ := json.Marshal(event)
+
+	// Room for one event per file.
+	sink, err := NewRotatingFileAuditSink(path, int64(len(line)+1), 1)
+	if err != nil {
+		t.Fatalf("NewRotatingFileAuditSink() unexpected error: %v", err)
+	}
+	defer sink.Close()
+	sink.Audit(event)
+
+	rotateErrors := metrics.AuditFileErrorCounter.WithLabelValues("rotate")
+	openErrors := metrics.AuditFileErrorCounter.WithLabelValues("open")
+	rotateBefore, openBefore := testutil.ToFloat64(rotateErrors), testutil.ToFloat64(openErrors)
+
+	// With the directory gone, neither rotating nor reopening the file works,
+	// so the event is dropped.
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatalf("RemoveAll() unexpected error: %v", err)
+	}
+	sink.Audit(event)
+	if got := testutil.ToFloat64(rotateErrors) - rotateBefore; got != 1 {
+		t.Errorf("Audit() after failed rotation: got %v rotate errors, want 1", got)
+	}
+	if got := testutil.ToFloat64(openErrors) - openBefore; got != 1 {
+		t.Errorf("Audit() after failed rotation: got %v open errors, want 1", got)
+	}
+
+	// Once the directory is back, the next events reopen and rotate the file.
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatalf("Mkdir() unexpected error: %v", err)
+	}
+	sink.Audit(event)
+	sink.Audit(event)
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+	sink.Audit(event)
+
+	for _, p := range []string{path, path + ".1"} {
+		if got := countAuditLines(t, p); got != 1 {
+			t.Errorf("RotatingFileAuditSink %s: got %d lines, want 1", p, got)
+		}
+	}
+	if got := testutil.ToFloat64(openErrors) - openBefore; got != 1 {
+		t.Errorf("Audit() after reopening: got %v open errors, want 1", got)
+	}
+}
+
+type blockingAuditSink struct {
+	release chan struct{}
+}
+
+func (s *blockingAuditSink) Audit(event *AuditEvent) {
+	<-s.release
+}
+
+func TestAsyncAuditSink_Drops(t *testing.T) {
+	next := &blockingAuditSink{release: make(chan struct{})}
+	async := NewAsyncAuditSink(next, 2)
+
+	// One event may be taken by the delivery goroutine and two buffered, so
+	// at least two of five are dropped.
+	for i := 0; i < 5; i++ {
+		async.Audit(&AuditEvent{})
+	}
+	if got := async.Dropped(); got < 2 || got > 3 {
+		t.Errorf("Dropped(): got %d, want 2 or 3", got)
+	}
+	close(next.release)
+	async.Close()
+}
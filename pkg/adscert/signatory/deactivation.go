@@ -0,0 +1,64 @@
+package signatory
+
+import (
+	"github.com/IABTechLab/adscert/pkg/adscert/api"
+	"google.golang.org/protobuf/proto"
+)
+
+// deactivationState is an immutable snapshot of api.DeactivationSettings,
+// indexed for lookups on every request.  It is swapped whole when the settings
+// change.
+type deactivationState struct {
+	settings       *api.DeactivationSettings
+	origins        map[string]bool
+	counterparties map[string]bool
+}
+
+func newDeactivationState(settings *api.DeactivationSettings) *deactivationState {
+	if settings == nil {
+		settings = &api.DeactivationSettings{}
+	}
+	state := &deactivationState{
+		settings:       proto.Clone(settings).(*api.DeactivationSettings),
+		origins:        map[string]bool{},
+		counterparties: map[string]bool{},
+	}
+	for _, origin := range settings.Origins {
+		state.origins[origin] = true
+	}
+	for _, counterparty := range settings.Counterparties {
+		state.counterparties[counterparty] = true
+	}
+	return state
+}
+
+// isDeactivated reports whether traffic between the origin and any of the
+// counterparty domains is deactivated.
+func (d *deactivationState) isDeactivated(origin string, counterparties ...string) bool {
+	if d.settings.All || d.origins[origin] {
+		return true
+	}
+	for _, counterparty := range counterparties {
+		if d.counterparties[counterparty] {
+			return true
+		}
+	}
+	return false
+}
+
+// SetDeactivation replaces the deactivation settings, taking effect for
+// subsequent requests.  While deactivated, signing either fails with the
+// deactivated operation status or, if so configured, produces signatures
+// declaring the deactivated status, and verification short-circuits.
+func (s *LocalAuthenticatedConnectionsSignatory) SetDeactivation(settings *api.DeactivationSettings) {
+	s.deactivation.Store(newDeactivationState(settings))
+}
+
+// GetDeactivation returns a copy of the deactivation settings in effect.
+func (s *LocalAuthenticatedConnectionsSignatory) GetDeactivation() *api.DeactivationSettings {
+	return proto.Clone(s.getDeactivationState().settings).(*api.DeactivationSettings)
+}
+
+func (s *LocalAuthenticatedConnectionsSignatory) getDeactivationState() *deactivationState {
+	return s.deactivation.Load().(*deactivationState)
+}
@@ -0,0 +1,118 @@
+package signatory
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// signatureTimestampFormat is the layout of the timestamp attribute embedded
+// in signatures (YYMMDDTHHMMSS, UTC).
+const signatureTimestampFormat = "060102T150405"
+
+// defaultReplayCacheSize is used when replay protection is enabled without an
+// explicit cache size.
+const defaultReplayCacheSize = 100000
+
+// parseSignatureTimestamp parses the timestamp attribute of a signature.
+func parseSignatureTimestamp(timestamp string) (time.Time, error) {
+	return time.ParseInLocation(signatureTimestampFormat, timestamp, time.UTC)
+}
+
+// isWithinWindow reports whether the signature timestamp is no further than
+// window away from now, in either direction to tolerate clock skew.
+func isWithinWindow(signedAt time.Time, now time.Time, window time.Duration) bool {
+	delta := now.Sub(signedAt)
+	if delta < 0 {
+		delta = -delta
+	}
+	return delta <= window
+}
+
+type nonceKey struct {
+	from  string
+	nonce string
+}
+
+type nonceEntry struct {
+	key     nonceKey
+	expires time.Time
+}
+
+// nonceCheck is the outcome of checking a nonce against the nonceCache.
+type nonceCheck int
+
+const (
+	nonceFresh nonceCheck = iota
+	nonceReplayed
+	nonceCacheFull
+)
+
+// nonceCache remembers recently seen (from, nonce) pairs so that a captured
+// signature can't be presented a second time while it is still fresh.
+// Entries are dropped once they expire.  Evicting a live entry would let its
+// signature be replayed, so while the cache is full of live entries new
+// nonces are refused instead, trading availability for replay protection.
+type nonceCache struct {
+	capacity int
+
+	mutex   sync.Mutex
+	entries map[nonceKey]*list.Element
+	order   *list.List // of *nonceEntry, oldest first
+}
+
+func newNonceCache(capacity int) *nonceCache {
+	if capacity <= 0 {
+		capacity = defaultReplayCacheSize
+	}
+	return &nonceCache{
+		capacity: capacity,
+		entries:  map[nonceKey]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// checkAndRecord records the nonce for the sending party.  It returns
+// nonceReplayed when the pair was already present and has not yet expired,
+// and nonceCacheFull without recording it when the cache holds capacity live
+// entries.
+func (c *nonceCache) checkAndRecord(from string, nonce string, expires time.Time, now time.Time) nonceCheck {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.removeExpired(now)
+
+	key := nonceKey{from: from, nonce: nonce}
+	if _, seen := c.entries[key]; seen {
+		return nonceReplayed
+	}
+	if c.order.Len() >= c.capacity {
+		return nonceCacheFull
+	}
+	c.entries[key] = c.order.PushBack(&nonceEntry{key: key, expires: expires})
+	return nonceFresh
+}
+
+func (c *nonceCache) len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
+
+// removeExpired drops expired entries from the front of the list.  Entries are
+// appended roughly in expiry order, so this stops at the first live entry
+// rather than scanning the whole cache.  An entry is kept up to and including
+// its expiry time, since isWithinWindow still accepts its signature then.
+func (c *nonceCache) removeExpired(now time.Time) {
+	for e := c.order.Front(); e != nil; e = c.order.Front() {
+		if !e.Value.(*nonceEntry).expires.Before(now) {
+			return
+		}
+		c.remove(e)
+	}
+}
+
+func (c *nonceCache) remove(e *list.Element) {
+	delete(c.entries, e.Value.(*nonceEntry).key)
+	c.order.Remove(e)
+}
@@ -0,0 +1,189 @@
+package signatory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IABTechLab/adscert/internal/adscerterrors"
+	"github.com/IABTechLab/adscert/pkg/adscert/api"
+	"github.com/IABTechLab/adscert/pkg/adscert/metrics"
+	"github.com/benbjohnson/clock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestNonceCache(t *testing.T) {
+	now := time.Date(2022, 11, 8, 21, 2, 36, 0, time.UTC)
+	expires := now.Add(time.Minute)
+	cache := newNonceCache(2)
+
+	if got := cache.checkAndRecord("from.com", "nonce1", expires, now); got != nonceFresh {
+		t.Fatalf("checkAndRecord() first use of nonce1: got %v, want %v", got, nonceFresh)
+	}
+	if got := cache.checkAndRecord("from.com", "nonce1", expires, now); got != nonceReplayed {
+		t.Errorf("checkAndRecord() second use of nonce1: got %v, want %v", got, nonceReplayed)
+	}
+	if got := cache.checkAndRecord("other.com", "nonce1", expires, now); got != nonceFresh {
+		t.Errorf("checkAndRecord() same nonce from a different party: got %v, want %v", got, nonceFresh)
+	}
+
+	// Entries are kept up to their expiry, when the signature is still fresh.
+	if got := cache.checkAndRecord("from.com", "nonce1", expires, expires); got != nonceReplayed {
+		t.Errorf("checkAndRecord() nonce1 at its expiry: got %v, want %v", got, nonceReplayed)
+	}
+
+	// Entries are dropped once they expire.
+	later := expires.Add(time.Second)
+	if got := cache.checkAndRecord("from.com", "nonce1", later.Add(time.Minute), later); got != nonceFresh {
+		t.Errorf("checkAndRecord() expired nonce1: got %v, want %v", got, nonceFresh)
+	}
+	if got := cache.len(); got != 1 {
+		t.Errorf("len() after expiry: got %d, want 1", got)
+	}
+}
+
+func TestNonceCache_Full(t *testing.T) {
+	now := time.Date(2022, 11, 8, 21, 2, 36, 0, time.UTC)
+	expires := now.Add(time.Minute)
+	cache := newNonceCache(2)
+
+	for _, nonce := range []string{"nonce1", "nonce2"} {
+		if got := cache.checkAndRecord("from.com", nonce, expires, now); got != nonceFresh {
+			t.Fatalf("checkAndRecord() first use of %s: got %v, want %v", nonce, got, nonceFresh)
+		}
+	}
+
+	// Evicting a live entry would let its signature be replayed, so new nonces
+	// are refused while every entry is live.
+	if got := cache.checkAndRecord("from.com", "nonce3", expires, now); got != nonceCacheFull {
+		t.Errorf("checkAndRecord() new nonce at capacity: got %v, want %v", got, nonceCacheFull)
+	}
+	if got := cache.checkAndRecord("from.com", "nonce1", expires, now); got != nonceReplayed {
+		t.Errorf("checkAndRecord() replay at capacity: got %v, want %v", got, nonceReplayed)
+	}
+	if got := cache.len(); got != 2 {
+		t.Errorf("len() at capacity: got %d, want 2", got)
+	}
+
+	// Once entries expire there is room again.
+	later := expires.Add(time.Second)
+	if got := cache.checkAndRecord("from.com", "nonce3", later.Add(time.Minute), later); got != nonceFresh {
+		t.Errorf("checkAndRecord() new nonce after expiry: got %v, want %v", got, nonceFresh)
+	}
+}
+
+func TestIsWithinWindow(t *testing.T) {
+	now := time.Date(2022, 11, 8, 21, 2, 36, 0, time.UTC)
+	testCases := []struct {
+		desc     string
+		signedAt time.Time
+		want     bool
+	}{
+		{desc: "same time", signedAt: now, want: true},
+		{desc: "past edge of window", signedAt: now.Add(-time.Minute), want: true},
+		{desc: "future edge of window", signedAt: now.Add(time.Minute), want: true},
+		{desc: "too old", signedAt: now.Add(-time.Minute - time.Second), want: false},
+		{desc: "too far in the future", signedAt: now.Add(time.Minute + time.Second), want: false},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			if got := isWithinWindow(tC.signedAt, now, time.Minute); got != tC.want {
+				t.Errorf("isWithinWindow() %s: got %v, want %v", tC.desc, got, tC.want)
+			}
+		})
+	}
+}
+
+// A signature replayed exactly at the edge of the window is still fresh, so
+// its nonce must still be remembered.
+func TestNonceCache_WindowBoundary(t *testing.T) {
+	signedAt := time.Date(2022, 11, 8, 21, 2, 36, 0, time.UTC)
+	window := time.Minute
+	cache := newNonceCache(10)
+
+	if got := cache.checkAndRecord("from.com", "nonce", signedAt.Add(window), signedAt); got != nonceFresh {
+		t.Fatalf("checkAndRecord() first use of nonce: got %v, want %v", got, nonceFresh)
+	}
+	replayedAt := signedAt.Add(window)
+	if !isWithinWindow(signedAt, replayedAt, window) {
+		t.Fatalf("isWithinWindow() at the edge of the window: got false, want true")
+	}
+	if got := cache.checkAndRecord("from.com", "nonce", signedAt.Add(window), replayedAt); got != nonceReplayed {
+		t.Errorf("checkAndRecord() replay at the edge of the window: got %v, want %v", got, nonceReplayed)
+	}
+}
+
+func TestCheckSingleSignatureFreshness(t *testing.T) {
+	mockClock := clock.NewMock()
+	mockClock.Set(time.Date(2022, 11, 8, 21, 2, 36, 0, time.UTC))
+	s := &LocalAuthenticatedConnectionsSignatory{
+		clock:        mockClock,
+		replayWindow: time.Minute,
+		seenNonces:   newNonceCache(10),
+	}
+	requestInfo := &api.RequestInfo{InvokingDomain: "invoking.com"}
+
+	testCases := []struct {
+		desc      string
+		signature string
+		want      api.SignatureDecodeStatus
+	}{
+		{
+			desc:      "stale timestamp",
+			signature: "from=from.com&from_key=fromkey&invoking=invoking.com&nonce=numberusedonce&status=1&timestamp=221108T205000&to=to.com&to_key=tokey; sigb=YWJjZGVmZ2hp&sigu=QUJDREVGR0hJ",
+			want:      api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_STALE_SIGNATURE,
+		},
+		{
+			desc:      "missing timestamp",
+			signature: "from=from.com&from_key=fromkey&invoking=invoking.com&nonce=numberusedonce&status=1&to=to.com&to_key=tokey; sigb=YWJjZGVmZ2hp&sigu=QUJDREVGR0hJ",
+			want:      api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_SIGNATURE_MALFORMED,
+		},
+		{
+			desc:      "missing nonce",
+			signature: "from=from.com&from_key=fromkey&invoking=invoking.com&status=1&timestamp=221108T210236&to=to.com&to_key=tokey; sigb=YWJjZGVmZ2hp&sigu=QUJDREVGR0hJ",
+			want:      api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_SIGNATURE_MALFORMED,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			got, _, _ := s.checkSingleSignature(context.Background(), requestInfo, &api.SignatureInfo{SignatureMessage: tC.signature})
+			if got != tC.want {
+				t.Errorf("checkSingleSignature() %s: got %v, want %v", tC.desc, got, tC.want)
+			}
+		})
+	}
+}
+
+func TestVerifyReplayCacheFull(t *testing.T) {
+	resolver := newFakeDNSResolver("verifier.com", "counterparty.com")
+	verifier := newTestSignatory(resolver, "verifier.com", &LocalAuthenticatedConnectionsSignatoryOptions{
+		ReplayWindow:    time.Minute,
+		ReplayCacheSize: 1,
+	})
+	counterparty := newTestSignatory(resolver, "counterparty.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+
+	sign := func() *api.RequestInfo {
+		requestInfo := &api.RequestInfo{}
+		SetRequestInfo(requestInfo, "https://verifier.com/bid", []byte("body"))
+		response, _ := counterparty.SignAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionSignatureRequest{RequestInfo: requestInfo})
+		return response.RequestInfo
+	}
+	verify := func(requestInfo *api.RequestInfo) *api.SignatureVerificationResult {
+		response, _ := verifier.VerifyAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionVerificationRequest{RequestInfo: []*api.RequestInfo{requestInfo}})
+		return response.VerificationInfo[0].SignatureResults[0]
+	}
+
+	if got := verify(sign()); got.SignatureDecodeStatus != api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID {
+		t.Fatalf("VerifyAuthenticatedConnection() first signature: got %v, want valid", got.SignatureDecodeStatus)
+	}
+
+	before := testutil.ToFloat64(metrics.ReplayCacheFullCounter)
+	got := verify(sign())
+	if got.SignatureDecodeStatus != api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_REPLAYED_SIGNATURE || got.ErrorCode != adscerterrors.ErrVerifyReplayCacheFull.Code {
+		t.Errorf("VerifyAuthenticatedConnection() with a full replay cache: got %v (%s), want %v (%s)",
+			got.SignatureDecodeStatus, got.ErrorCode, api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_REPLAYED_SIGNATURE, adscerterrors.ErrVerifyReplayCacheFull.Code)
+	}
+	if delta := testutil.ToFloat64(metrics.ReplayCacheFullCounter) - before; delta != 1 {
+		t.Errorf("VerifyAuthenticatedConnection() with a full replay cache: got %v rejections counted, want 1", delta)
+	}
+}
@@ -0,0 +1,126 @@
+package signatory
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/IABTechLab/adscert/pkg/adscert/api"
+)
+
+// AuthHeader is the HTTP header carrying ads.cert Authenticated Connections
+// signatures, one value per signature.
+const AuthHeader = "X-Ads-Cert-Auth"
+
+// SigningFailureHandler decides what happens to an outbound request whose
+// signing failed: returning nil sends it without signatures, while returning
+// an error fails the request with it.  The response is nil if signing failed
+// before reaching the signatory.
+type SigningFailureHandler func(req *http.Request, response *api.AuthenticatedConnectionSignatureResponse, err error) error
+
+// PassThroughOnSigningFailure sends requests without signatures when signing
+// fails.
+func PassThroughOnSigningFailure(req *http.Request, response *api.AuthenticatedConnectionSignatureResponse, err error) error {
+	return nil
+}
+
+// BlockOnSigningFailure fails requests whose signing fails.
+func BlockOnSigningFailure(req *http.Request, response *api.AuthenticatedConnectionSignatureResponse, err error) error {
+	return fmt.Errorf("unable to sign request to %s: %v", req.URL, err)
+}
+
+// RoundTripper signs each outbound request with the Signatory before passing
+// it on to Transport, attaching the signatures in AuthHeader.  The request body
+// is buffered in memory so that it can be both hashed and sent.
+//
+// When the Signatory accepts a context, signing is bound to the request's
+// context, and so its deadline.
+type RoundTripper struct {
+	Signatory AuthenticatedConnectionsSignatory
+
+	// Transport sends the signed requests.  Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// OnSigningFailure decides whether requests which couldn't be signed are
+	// sent.  Defaults to PassThroughOnSigningFailure.
+	OnSigningFailure SigningFailureHandler
+}
+
+var _ http.RoundTripper = (*RoundTripper)(nil)
+
+// NewRoundTripper returns a RoundTripper signing requests with signatory before
+// sending them with transport, sending requests which couldn't be signed
+// without signatures.
+func NewRoundTripper(signatory AuthenticatedConnectionsSignatory, transport http.RoundTripper) *RoundTripper {
+	return &RoundTripper{Signatory: signatory, Transport: transport}
+}
+
+func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the request it is given.
+	signedReq := req.Clone(req.Context())
+
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error reading request body: %v", err)
+		}
+		signedReq.Body = io.NopCloser(bytes.NewReader(body))
+		signedReq.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	signatures, response, err := rt.sign(req.Context(), req.URL.String(), body)
+	if err != nil {
+		onSigningFailure := rt.OnSigningFailure
+		if onSigningFailure == nil {
+			onSigningFailure = PassThroughOnSigningFailure
+		}
+		if err := onSigningFailure(req, response, err); err != nil {
+			return nil, err
+		}
+	}
+	for _, signature := range signatures {
+		signedReq.Header.Add(AuthHeader, signature)
+	}
+
+	transport := rt.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return transport.RoundTrip(signedReq)
+}
+
+func (rt *RoundTripper) sign(ctx context.Context, url string, body []byte) ([]string, *api.AuthenticatedConnectionSignatureResponse, error) {
+	requestInfo := &api.RequestInfo{}
+	if err := SetRequestInfo(requestInfo, url, body); err != nil {
+		return nil, nil, err
+	}
+
+	request := &api.AuthenticatedConnectionSignatureRequest{RequestInfo: requestInfo}
+	var response *api.AuthenticatedConnectionSignatureResponse
+	var err error
+	if contextSignatory, ok := rt.Signatory.(ContextAuthenticatedConnectionsSignatory); ok {
+		response, err = contextSignatory.SignAuthenticatedConnectionContext(ctx, request)
+	} else {
+		response, err = rt.Signatory.SignAuthenticatedConnection(request)
+	}
+	if err != nil {
+		return nil, response, err
+	}
+	if response.GetSignatureOperationStatus() != api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK {
+		return nil, response, fmt.Errorf("signing failed with status %v", response.GetSignatureOperationStatus())
+	}
+
+	signatures := GetSignatures(response)
+	if len(signatures) == 0 {
+		return nil, response, errors.New("no signatures produced")
+	}
+	return signatures, response, nil
+}
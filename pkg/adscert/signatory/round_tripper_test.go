@@ -0,0 +1,105 @@
+package signatory
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/IABTechLab/adscert/pkg/adscert/api"
+)
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRoundTripper(t *testing.T) {
+	resolver := newFakeDNSResolver("verifier.com", "counterparty.com")
+	verifier := newTestSignatory(resolver, "verifier.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+	counterparty := newTestSignatory(resolver, "counterparty.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+
+	testCases := []struct {
+		desc             string
+		url              string
+		onSigningFailure SigningFailureHandler
+
+		wantSent      bool
+		wantSignature bool
+	}{
+		{
+			desc:          "signed",
+			url:           "https://verifier.com/bid",
+			wantSent:      true,
+			wantSignature: true,
+		},
+		{
+			desc:     "unsigned passed through",
+			url:      "https://unknown.com/bid",
+			wantSent: true,
+		},
+		{
+			desc:             "unsigned blocked",
+			url:              "https://unknown.com/bid",
+			onSigningFailure: BlockOnSigningFailure,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			body := []byte("bid request")
+			var sent *http.Request
+			var sentBody []byte
+			roundTripper := &RoundTripper{
+				Signatory: counterparty,
+				Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					sent = req
+					sentBody, _ = io.ReadAll(req.Body)
+					return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody, Request: req}, nil
+				}),
+				OnSigningFailure: tC.onSigningFailure,
+			}
+
+			req, _ := http.NewRequest(http.MethodPost, tC.url, bytes.NewReader(body))
+			_, err := roundTripper.RoundTrip(req)
+			if gotSent := sent != nil; gotSent != tC.wantSent {
+				t.Fatalf("RoundTrip() %s: got sent %v, want %v (err %v)", tC.desc, gotSent, tC.wantSent, err)
+			}
+			if !tC.wantSent {
+				if err == nil {
+					t.Errorf("RoundTrip() %s: got no error, want error", tC.desc)
+				}
+				return
+			}
+			if !bytes.Equal(sentBody, body) {
+				t.Errorf("RoundTrip() %s: got body %q, want %q", tC.desc, sentBody, body)
+			}
+			if len(req.Header.Values(AuthHeader)) != 0 {
+				t.Errorf("RoundTrip() %s: modified the original request's headers", tC.desc)
+			}
+
+			signatures := sent.Header.Values(AuthHeader)
+			if gotSignature := len(signatures) > 0; gotSignature != tC.wantSignature {
+				t.Fatalf("RoundTrip() %s: got signatures %v, want signed %v", tC.desc, signatures, tC.wantSignature)
+			}
+			if !tC.wantSignature {
+				return
+			}
+
+			requestInfo := &api.RequestInfo{}
+			SetRequestInfo(requestInfo, tC.url, body)
+			SetRequestSignatures(requestInfo, signatures)
+			verifyResponse, err := verifier.VerifyAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionVerificationRequest{
+				RequestInfo: []*api.RequestInfo{requestInfo},
+			})
+			if err != nil {
+				t.Fatalf("VerifyAuthenticatedConnectionContext() %s: unexpected error: %v", tC.desc, err)
+			}
+			want := api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID
+			if got := verifyResponse.VerificationInfo[0].SignatureDecodeStatus[0]; got != want {
+				t.Errorf("VerifyAuthenticatedConnectionContext() %s: got %v, want %v", tC.desc, got, want)
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package signatory
 
 import (
+	"context"
+
 	"github.com/IABTechLab/adscert/pkg/adscert/api"
 )
 
@@ -8,3 +10,24 @@ type AuthenticatedConnectionsSignatory interface {
 	SignAuthenticatedConnection(request *api.AuthenticatedConnectionSignatureRequest) (*api.AuthenticatedConnectionSignatureResponse, error)
 	VerifyAuthenticatedConnection(request *api.AuthenticatedConnectionVerificationRequest) (*api.AuthenticatedConnectionVerificationResponse, error)
 }
+
+// ContextAuthenticatedConnectionsSignatory accepts a context with each
+// operation, so that request deadlines, cancellation and tracing spans carry
+// through to counterparty lookups and remote calls.  The context-free methods
+// behave as if called with context.Background().
+type ContextAuthenticatedConnectionsSignatory interface {
+	AuthenticatedConnectionsSignatory
+
+	SignAuthenticatedConnectionContext(ctx context.Context, request *api.AuthenticatedConnectionSignatureRequest) (*api.AuthenticatedConnectionSignatureResponse, error)
+	VerifyAuthenticatedConnectionContext(ctx context.Context, request *api.AuthenticatedConnectionVerificationRequest) (*api.AuthenticatedConnectionVerificationResponse, error)
+}
+
+// BatchAuthenticatedConnectionsSignatory also signs many requests in one call,
+// sharing the work common to them, such as when fanning an auction out to
+// several bidders.
+type BatchAuthenticatedConnectionsSignatory interface {
+	ContextAuthenticatedConnectionsSignatory
+
+	SignAuthenticatedConnectionBatch(request *api.AuthenticatedConnectionBatchSignatureRequest) (*api.AuthenticatedConnectionBatchSignatureResponse, error)
+	SignAuthenticatedConnectionBatchContext(ctx context.Context, request *api.AuthenticatedConnectionBatchSignatureRequest) (*api.AuthenticatedConnectionBatchSignatureResponse, error)
+}
@@ -0,0 +1,90 @@
+package signatory
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/IABTechLab/adscert/pkg/adscert/api"
+	"github.com/IABTechLab/adscert/pkg/adscert/metrics"
+)
+
+// auditSigning records the outcome of a signing request with the audit sink,
+// if there is one.
+func (s *LocalAuthenticatedConnectionsSignatory) auditSigning(response *api.AuthenticatedConnectionSignatureResponse, startTime time.Time) {
+	requestInfo := response.GetRequestInfo()
+	if s.auditSink == nil || requestInfo.GetInvokingDomain() == "dryrun" {
+		return
+	}
+
+	newEvent := func() *AuditEvent {
+		return &AuditEvent{
+			Time:           startTime,
+			Operation:      AuditOperationSign,
+			InvokingDomain: requestInfo.GetInvokingDomain(),
+			Counterparty:   requestInfo.GetInvokingDomain(),
+			BodyHash:       base64.StdEncoding.EncodeToString(requestInfo.GetBodyHash()),
+			URLHash:        base64.StdEncoding.EncodeToString(requestInfo.GetUrlHash()),
+			Outcome:        response.SignatureOperationStatus.String(),
+			LatencyMicros:  s.clock.Since(startTime).Microseconds(),
+		}
+	}
+
+	if len(requestInfo.GetSignatureInfo()) == 0 {
+		s.audit(newEvent())
+		return
+	}
+	for _, signatureInfo := range requestInfo.SignatureInfo {
+		event := newEvent()
+		if signatureInfo.ToDomain != "" {
+			event.Counterparty = signatureInfo.ToDomain
+		}
+		event.FromDomain = signatureInfo.FromDomain
+		event.FromKey = signatureInfo.FromKey
+		event.ToDomain = signatureInfo.ToDomain
+		event.ToKey = signatureInfo.ToKey
+		event.Signature = signatureInfo.SignatureMessage
+		s.audit(event)
+	}
+}
+
+// auditVerification records the verification of one signature with the audit
+// sink, if there is one.
+func (s *LocalAuthenticatedConnectionsSignatory) auditVerification(requestInfo *api.RequestInfo, signatureInfo *api.SignatureInfo, result *api.SignatureVerificationResult, startTime time.Time) {
+	if s.auditSink == nil {
+		return
+	}
+
+	s.audit(&AuditEvent{
+		Time:           startTime,
+		Operation:      AuditOperationVerify,
+		InvokingDomain: requestInfo.InvokingDomain,
+		Counterparty:   result.FromDomain,
+		FromDomain:     result.FromDomain,
+		FromKey:        result.FromKey,
+		ToDomain:       result.ToDomain,
+		ToKey:          result.ToKey,
+		BodyHash:       base64.StdEncoding.EncodeToString(requestInfo.BodyHash),
+		URLHash:        base64.StdEncoding.EncodeToString(requestInfo.UrlHash),
+		Signature:      signatureInfo.SignatureMessage,
+		Outcome:        result.SignatureDecodeStatus.String(),
+		ErrorCode:      result.ErrorCode,
+		LatencyMicros:  s.clock.Since(startTime).Microseconds(),
+	})
+}
+
+// audit applies the sampling and redaction settings to an event before
+// passing it to the audit sink.
+func (s *LocalAuthenticatedConnectionsSignatory) audit(event *AuditEvent) {
+	keep := shouldAudit(s.auditSampler, event)
+	metrics.RecordAuditSampling(event.Operation, keep)
+	if !keep {
+		return
+	}
+	if s.auditRedactionKey != nil {
+		event.Signature = redactSignature(s.auditRedactionKey, event.Signature)
+	}
+	if s.auditOmitHashes {
+		event.BodyHash, event.URLHash = "", ""
+	}
+	s.auditSink.Audit(event)
+}
@@ -0,0 +1,111 @@
+package signatory
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/IABTechLab/adscert/internal/adscerterrors"
+	"github.com/IABTechLab/adscert/pkg/adscert/api"
+	"github.com/IABTechLab/adscert/pkg/adscert/discovery"
+	"github.com/IABTechLab/adscert/pkg/adscert/metrics"
+)
+
+func (s *LocalAuthenticatedConnectionsSignatory) SignAuthenticatedConnectionBatch(request *api.AuthenticatedConnectionBatchSignatureRequest) (*api.AuthenticatedConnectionBatchSignatureResponse, error) {
+	return s.SignAuthenticatedConnectionBatchContext(context.Background(), request)
+}
+
+// SignAuthenticatedConnectionBatchContext signs every request of the batch
+// with one timestamp, one read of secure randomness for the nonces and one
+// lookup per distinct invoking domain, computing the signatures in parallel.
+// Each request gets its own response and status, in order; the returned error
+// only reports failures affecting the whole batch.
+func (s *LocalAuthenticatedConnectionsSignatory) SignAuthenticatedConnectionBatchContext(ctx context.Context, request *api.AuthenticatedConnectionBatchSignatureRequest) (*api.AuthenticatedConnectionBatchSignatureResponse, error) {
+	response := &api.AuthenticatedConnectionBatchSignatureResponse{
+		Responses: make([]*api.AuthenticatedConnectionSignatureResponse, len(request.RequestInfo)),
+	}
+	if len(request.RequestInfo) == 0 {
+		return response, nil
+	}
+
+	timestamp := request.Timestamp
+	if timestamp == "" {
+		timestamp = s.clock.Now().UTC().Format(signatureTimestampFormat)
+	}
+	nonces, err := s.generateNonces(len(request.RequestInfo))
+	if err != nil {
+		for i, requestInfo := range request.RequestInfo {
+			metrics.RecordSigning(adscerterrors.ErrSigningGenerateNonce, requestInfo.GetInvokingDomain(), "")
+			response.Responses[i] = &api.AuthenticatedConnectionSignatureResponse{
+				SignatureOperationStatus: api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_SIGNATORY_INTERNAL_ERROR,
+				RequestInfo:              requestInfo,
+			}
+		}
+		return response, err
+	}
+
+	lookupIdentities := s.lookupBatchIdentities(ctx, request.RequestInfo)
+
+	work := make(chan int)
+	var wg sync.WaitGroup
+	workers := runtime.GOMAXPROCS(0)
+	if workers > len(request.RequestInfo) {
+		workers = len(request.RequestInfo)
+	}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				// Failures are reported through each response's status.
+				response.Responses[i], _ = s.signAuthenticatedConnection(ctx, &api.AuthenticatedConnectionSignatureRequest{
+					RequestInfo:   request.RequestInfo[i],
+					Timestamp:     timestamp,
+					Nonce:         nonces[i],
+					Origin:        request.Origin,
+					SigningStatus: request.SigningStatus,
+				}, lookupIdentities)
+			}
+		}()
+	}
+	for i := range request.RequestInfo {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	return response, nil
+}
+
+// lookupBatchIdentities looks up the distinct invoking domains of a batch
+// concurrently, returning a lookup served from the results.
+func (s *LocalAuthenticatedConnectionsSignatory) lookupBatchIdentities(ctx context.Context, requestInfos []*api.RequestInfo) identityLookup {
+	type lookupResult struct {
+		domainInfos []discovery.DomainInfo
+		err         error
+	}
+
+	results := map[string]*lookupResult{}
+	for _, requestInfo := range requestInfos {
+		if requestInfo != nil && requestInfo.InvokingDomain != "" && requestInfo.InvokingDomain != "dryrun" {
+			results[requestInfo.InvokingDomain] = &lookupResult{}
+		}
+	}
+
+	var wg sync.WaitGroup
+	for domain, result := range results {
+		wg.Add(1)
+		go func(domain string, result *lookupResult) {
+			defer wg.Done()
+			result.domainInfos, result.err = s.lookupIdentitiesForDomain(ctx, domain)
+		}(domain, result)
+	}
+	wg.Wait()
+
+	return func(ctx context.Context, domain string) ([]discovery.DomainInfo, error) {
+		if result, ok := results[domain]; ok {
+			return result.domainInfos, result.err
+		}
+		return s.lookupIdentitiesForDomain(ctx, domain)
+	}
+}
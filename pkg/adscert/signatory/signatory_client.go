@@ -8,42 +8,115 @@ import (
 	"google.golang.org/grpc"
 )
 
-func NewAuthenticatedConnectionsSignatoryClient(conn *grpc.ClientConn, options *AuthenticatedConnectionsSignatoryClientOptions) AuthenticatedConnectionsSignatory {
+func NewAuthenticatedConnectionsSignatoryClient(conn *grpc.ClientConn, options *AuthenticatedConnectionsSignatoryClientOptions) *AuthenticatedConnectionsSignatoryClient {
 
 	grpcClient := api.NewAdsCertSignatoryClient(conn)
 
-	return &AuthenticatedConnectionsSignatoryClient{
+	sc := &AuthenticatedConnectionsSignatoryClient{
 		grpcClient: grpcClient,
 		timeout:    options.Timeout,
 	}
+	if options.Streaming {
+		sc.signStream = newPipelinedStream(func(ctx context.Context) (streamConn, error) {
+			stream, err := grpcClient.SignStream(ctx)
+			return &signStreamConn{stream: stream}, err
+		})
+		sc.verifyStream = newPipelinedStream(func(ctx context.Context) (streamConn, error) {
+			stream, err := grpcClient.VerifyStream(ctx)
+			return &verifyStreamConn{stream: stream}, err
+		})
+	}
+	return sc
 }
 
 type AuthenticatedConnectionsSignatoryClientOptions struct {
 	Timeout time.Duration
+
+	// Streaming sends signing and verification requests over long-lived
+	// bidirectional streams, pipelining concurrent calls, instead of making
+	// a unary call for each.  Batch signing is always unary.
+	Streaming bool
 }
 
 type AuthenticatedConnectionsSignatoryClient struct {
 	grpcClient api.AdsCertSignatoryClient
 
 	timeout time.Duration
+
+	signStream   *pipelinedStream
+	verifyStream *pipelinedStream
+}
+
+var _ BatchAuthenticatedConnectionsSignatory = (*AuthenticatedConnectionsSignatoryClient)(nil)
+
+// Close ends the client's streams, if streaming, failing any calls still
+// waiting on them.  The gRPC connection is left open for its owner to close.
+func (sc *AuthenticatedConnectionsSignatoryClient) Close() error {
+	if sc.signStream == nil {
+		return nil
+	}
+	signErr := sc.signStream.close()
+	verifyErr := sc.verifyStream.close()
+	if signErr != nil {
+		return signErr
+	}
+	return verifyErr
 }
 
 func (sc *AuthenticatedConnectionsSignatoryClient) SignAuthenticatedConnection(request *api.AuthenticatedConnectionSignatureRequest) (*api.AuthenticatedConnectionSignatureResponse, error) {
+	return sc.SignAuthenticatedConnectionContext(context.Background(), request)
+}
+
+func (sc *AuthenticatedConnectionsSignatoryClient) SignAuthenticatedConnectionContext(ctx context.Context, request *api.AuthenticatedConnectionSignatureRequest) (*api.AuthenticatedConnectionSignatureResponse, error) {
 
 	// set network call context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), sc.timeout)
+	ctx, cancel := context.WithTimeout(ctx, sc.timeout)
 	defer cancel()
 
+	if sc.signStream != nil {
+		response, err := sc.signStream.call(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		return response.(*api.AuthenticatedConnectionSignatureResponse), nil
+	}
+
 	response, err := sc.grpcClient.SignAuthenticatedConnection(ctx, request)
 	return response, err
 }
 
+func (sc *AuthenticatedConnectionsSignatoryClient) SignAuthenticatedConnectionBatch(request *api.AuthenticatedConnectionBatchSignatureRequest) (*api.AuthenticatedConnectionBatchSignatureResponse, error) {
+	return sc.SignAuthenticatedConnectionBatchContext(context.Background(), request)
+}
+
+func (sc *AuthenticatedConnectionsSignatoryClient) SignAuthenticatedConnectionBatchContext(ctx context.Context, request *api.AuthenticatedConnectionBatchSignatureRequest) (*api.AuthenticatedConnectionBatchSignatureResponse, error) {
+
+	// set network call context with timeout
+	ctx, cancel := context.WithTimeout(ctx, sc.timeout)
+	defer cancel()
+
+	response, err := sc.grpcClient.SignAuthenticatedConnectionBatch(ctx, request)
+	return response, err
+}
+
 func (sc *AuthenticatedConnectionsSignatoryClient) VerifyAuthenticatedConnection(request *api.AuthenticatedConnectionVerificationRequest) (*api.AuthenticatedConnectionVerificationResponse, error) {
+	return sc.VerifyAuthenticatedConnectionContext(context.Background(), request)
+}
+
+func (sc *AuthenticatedConnectionsSignatoryClient) VerifyAuthenticatedConnectionContext(ctx context.Context, request *api.AuthenticatedConnectionVerificationRequest) (*api.AuthenticatedConnectionVerificationResponse, error) {
 
 	// set network call context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), sc.timeout)
+	ctx, cancel := context.WithTimeout(ctx, sc.timeout)
 	defer cancel()
 
+	if sc.verifyStream != nil {
+		response, err := sc.verifyStream.call(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		return response.(*api.AuthenticatedConnectionVerificationResponse), nil
+	}
+
 	response, err := sc.grpcClient.VerifyAuthenticatedConnection(ctx, request)
 	return response, err
 }
@@ -0,0 +1,212 @@
+package signatory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/IABTechLab/adscert/pkg/adscert/api"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// streamConn is one bidirectional stream carrying requests tagged with IDs.
+type streamConn interface {
+	send(requestID uint64, request interface{}) error
+	recv() (requestID uint64, response interface{}, errorMessage string, err error)
+	closeSend() error
+}
+
+// errStreamClosed fails calls made after the pipelined stream is closed.
+var errStreamClosed = status.Error(codes.Canceled, "signatory client is closed")
+
+// pipelinedStream multiplexes concurrent calls over one long-lived stream,
+// matching responses to callers by request ID so that they can arrive in any
+// order.  A stream which fails is reopened by the next call.
+type pipelinedStream struct {
+	open func(ctx context.Context) (streamConn, error)
+
+	// ctx outlives any one call, bounding the streams until close.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mutex      sync.Mutex
+	conn       streamConn
+	cancelConn context.CancelFunc
+	closed     bool
+	nextID     uint64
+	pending    map[uint64]chan streamResult
+
+	sendMutex sync.Mutex
+}
+
+type streamResult struct {
+	response interface{}
+	err      error
+}
+
+func newPipelinedStream(open func(ctx context.Context) (streamConn, error)) *pipelinedStream {
+	ps := &pipelinedStream{open: open}
+	ps.ctx, ps.cancel = context.WithCancel(context.Background())
+	return ps
+}
+
+// call sends request over the stream and waits for its response until ctx is
+// done.
+func (ps *pipelinedStream) call(ctx context.Context, request interface{}) (interface{}, error) {
+	result := make(chan streamResult, 1)
+
+	ps.mutex.Lock()
+	if ps.closed {
+		ps.mutex.Unlock()
+		return nil, errStreamClosed
+	}
+	if ps.conn == nil {
+		connCtx, cancelConn := context.WithCancel(ps.ctx)
+		conn, err := ps.open(connCtx)
+		if err != nil {
+			cancelConn()
+			ps.mutex.Unlock()
+			return nil, err
+		}
+		ps.conn, ps.cancelConn = conn, cancelConn
+		ps.pending = map[uint64]chan streamResult{}
+		go ps.receive(conn, ps.pending)
+	}
+	ps.nextID++
+	requestID := ps.nextID
+	conn, pending := ps.conn, ps.pending
+	pending[requestID] = result
+	ps.mutex.Unlock()
+
+	ps.sendMutex.Lock()
+	err := conn.send(requestID, request)
+	ps.sendMutex.Unlock()
+	if err != nil {
+		// A stream which fails to send is broken, so the next call opens a
+		// new one.
+		ps.mutex.Lock()
+		delete(pending, requestID)
+		ps.resetLocked(conn)
+		ps.mutex.Unlock()
+		return nil, err
+	}
+
+	select {
+	case r := <-result:
+		return r.response, r.err
+	case <-ctx.Done():
+		ps.forget(pending, requestID)
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+// close ends the stream, failing the calls waiting on it, and makes further
+// calls fail.
+func (ps *pipelinedStream) close() error {
+	ps.mutex.Lock()
+	if ps.closed {
+		ps.mutex.Unlock()
+		return nil
+	}
+	ps.closed = true
+	conn := ps.conn
+	ps.conn = nil
+	ps.mutex.Unlock()
+
+	var err error
+	if conn != nil {
+		ps.sendMutex.Lock()
+		err = conn.closeSend()
+		ps.sendMutex.Unlock()
+	}
+	ps.cancel()
+	return err
+}
+
+// resetLocked stops using conn, if it is still the current stream, so that
+// the next call opens a new one.  The caller holds ps.mutex.
+func (ps *pipelinedStream) resetLocked(conn streamConn) {
+	if ps.conn == conn {
+		ps.conn = nil
+		ps.cancelConn()
+	}
+}
+
+func (ps *pipelinedStream) forget(pending map[uint64]chan streamResult, requestID uint64) {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+	delete(pending, requestID)
+}
+
+// receive delivers responses to their callers until the stream fails, then
+// fails every call still waiting on it.
+func (ps *pipelinedStream) receive(conn streamConn, pending map[uint64]chan streamResult) {
+	for {
+		requestID, response, errorMessage, err := conn.recv()
+		if err != nil {
+			ps.mutex.Lock()
+			ps.resetLocked(conn)
+			for id, result := range pending {
+				result <- streamResult{err: err}
+				delete(pending, id)
+			}
+			ps.mutex.Unlock()
+			return
+		}
+
+		ps.mutex.Lock()
+		result, ok := pending[requestID]
+		delete(pending, requestID)
+		ps.mutex.Unlock()
+		if !ok {
+			// The caller has given up waiting.
+			continue
+		}
+
+		if errorMessage != "" {
+			result <- streamResult{err: status.Error(codes.Unknown, errorMessage)}
+		} else {
+			result <- streamResult{response: response}
+		}
+	}
+}
+
+type signStreamConn struct {
+	stream api.AdsCertSignatory_SignStreamClient
+}
+
+func (c *signStreamConn) send(requestID uint64, request interface{}) error {
+	return c.stream.Send(&api.SignStreamRequest{RequestId: requestID, Request: request.(*api.AuthenticatedConnectionSignatureRequest)})
+}
+
+func (c *signStreamConn) recv() (uint64, interface{}, string, error) {
+	streamResponse, err := c.stream.Recv()
+	if err != nil {
+		return 0, nil, "", err
+	}
+	return streamResponse.RequestId, streamResponse.Response, streamResponse.Error, nil
+}
+
+func (c *signStreamConn) closeSend() error {
+	return c.stream.CloseSend()
+}
+
+type verifyStreamConn struct {
+	stream api.AdsCertSignatory_VerifyStreamClient
+}
+
+func (c *verifyStreamConn) send(requestID uint64, request interface{}) error {
+	return c.stream.Send(&api.VerifyStreamRequest{RequestId: requestID, Request: request.(*api.AuthenticatedConnectionVerificationRequest)})
+}
+
+func (c *verifyStreamConn) recv() (uint64, interface{}, string, error) {
+	streamResponse, err := c.stream.Recv()
+	if err != nil {
+		return 0, nil, "", err
+	}
+	return streamResponse.RequestId, streamResponse.Response, streamResponse.Error, nil
+}
+
+func (c *verifyStreamConn) closeSend() error {
+	return c.stream.CloseSend()
+}
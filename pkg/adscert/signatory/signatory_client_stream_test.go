@@ -0,0 +1,119 @@
+package signatory
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeStreamConn echoes requests back as responses until its context is done.
+type fakeStreamConn struct {
+	ctx     context.Context
+	sendErr error
+
+	mutex       sync.Mutex
+	closedSends bool
+	responses   chan [2]interface{}
+}
+
+func (c *fakeStreamConn) send(requestID uint64, request interface{}) error {
+	if c.sendErr != nil {
+		return c.sendErr
+	}
+	c.responses <- [2]interface{}{requestID, request}
+	return nil
+}
+
+func (c *fakeStreamConn) recv() (uint64, interface{}, string, error) {
+	select {
+	case r := <-c.responses:
+		return r[0].(uint64), r[1], "", nil
+	case <-c.ctx.Done():
+		return 0, nil, "", status.FromContextError(c.ctx.Err()).Err()
+	}
+}
+
+func (c *fakeStreamConn) closeSend() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.closedSends = true
+	return nil
+}
+
+// fakeStreamOpener opens fakeStreamConns, failing sends on the first
+// failSends streams.
+type fakeStreamOpener struct {
+	failSends int
+
+	mutex sync.Mutex
+	conns []*fakeStreamConn
+}
+
+func (o *fakeStreamOpener) open(ctx context.Context) (streamConn, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+	conn := &fakeStreamConn{ctx: ctx, responses: make(chan [2]interface{}, 10)}
+	if len(o.conns) < o.failSends {
+		conn.sendErr = errors.New("stream broken")
+	}
+	o.conns = append(o.conns, conn)
+	return conn, nil
+}
+
+func TestPipelinedStream_SendErrorReopens(t *testing.T) {
+	opener := &fakeStreamOpener{failSends: 1}
+	ps := newPipelinedStream(opener.open)
+	defer ps.close()
+
+	if _, err := ps.call(context.Background(), "first"); err == nil {
+		t.Fatalf("call() on broken stream: got nil error, want error")
+	}
+	if err := opener.conns[0].ctx.Err(); err == nil {
+		t.Errorf("call() on broken stream: stream context not canceled")
+	}
+
+	response, err := ps.call(context.Background(), "second")
+	if err != nil || response != "second" {
+		t.Fatalf("call() after broken stream: got %v, %v, want %q", response, err, "second")
+	}
+	if len(opener.conns) != 2 {
+		t.Errorf("call() after broken stream: got %d streams opened, want 2", len(opener.conns))
+	}
+}
+
+func TestPipelinedStream_Close(t *testing.T) {
+	opener := &fakeStreamOpener{}
+	ps := newPipelinedStream(opener.open)
+
+	if response, err := ps.call(context.Background(), "request"); err != nil || response != "request" {
+		t.Fatalf("call(): got %v, %v, want %q", response, err, "request")
+	}
+
+	if err := ps.close(); err != nil {
+		t.Fatalf("close(): unexpected error: %v", err)
+	}
+	conn := opener.conns[0]
+	if conn.ctx.Err() == nil {
+		t.Errorf("close(): stream context not canceled")
+	}
+	conn.mutex.Lock()
+	closedSends := conn.closedSends
+	conn.mutex.Unlock()
+	if !closedSends {
+		t.Errorf("close(): CloseSend not called")
+	}
+
+	if _, err := ps.call(context.Background(), "request"); status.Code(err) != codes.Canceled {
+		t.Errorf("call() after close(): got %v, want %v", err, codes.Canceled)
+	}
+	if len(opener.conns) != 1 {
+		t.Errorf("call() after close(): got %d streams opened, want 1", len(opener.conns))
+	}
+	if err := ps.close(); err != nil {
+		t.Errorf("close() again: unexpected error: %v", err)
+	}
+}
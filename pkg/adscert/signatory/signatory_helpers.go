@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"net/url"
 
+	"github.com/IABTechLab/adscert/internal/adscerterrors"
 	"github.com/IABTechLab/adscert/internal/formats"
 	"github.com/IABTechLab/adscert/pkg/adscert/api"
+	"github.com/IABTechLab/adscert/pkg/adscert/metrics"
 	"golang.org/x/net/publicsuffix"
 )
 
@@ -42,11 +44,15 @@ func SetRequestSignatures(requestInfo *api.RequestInfo, signatures []string) {
 	}
 }
 
+// GetSignatures returns the signature messages produced by a signing request,
+// skipping any signature which couldn't be produced.
 func GetSignatures(response *api.AuthenticatedConnectionSignatureResponse) []string {
 	signatures := make([]string, 0)
 
-	for _, si := range response.RequestInfo.SignatureInfo {
-		signatures = append(signatures, si.SignatureMessage)
+	for _, si := range response.GetRequestInfo().GetSignatureInfo() {
+		if si.SignatureMessage != "" {
+			signatures = append(signatures, si.SignatureMessage)
+		}
 	}
 
 	return signatures
@@ -75,3 +81,53 @@ func setSignatureInfoFromAuthenticatedConnection(sigInfo *api.SignatureInfo, acs
 	sigInfo.SigningStatus = acs.GetAttributeStatusAsString()
 	sigInfo.SignatureMessage = acs.EncodeMessage()
 }
+
+// recordVerifyOutcome records the metrics for one verified signature.
+func recordVerifyOutcome(verifyErr adscerterrors.VerifyErrorCode, result *api.SignatureVerificationResult) {
+	bodyValid := result.SignatureDecodeStatus == api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID ||
+		result.SignatureDecodeStatus == api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_VALID
+	urlValid := result.SignatureDecodeStatus == api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID
+	signer, signerKey := authenticatedSigner(result)
+
+	metrics.RecordVerify(verifyErr, signer, signerKey)
+	metrics.RecordVerifyOutcome(metrics.VerifyOutcomeTypeBody, bodyValid)
+	metrics.RecordVerifyOutcome(metrics.VerifyOutcomeTypeUrl, urlValid)
+	metrics.RecordVerifyDecodeStatus(result.SignatureDecodeStatus.String(), signer, signerKey)
+}
+
+// authenticatedSigner returns the signer and key alias of a signature for use
+// as metric labels.  They are only returned once the signature's HMAC has
+// been checked, which also means the key alias is one the signer publishes;
+// the attributes of other signatures are whatever the sender chose, so they
+// are left empty to keep the number of label values bounded.
+func authenticatedSigner(result *api.SignatureVerificationResult) (string, string) {
+	switch result.SignatureDecodeStatus {
+	case api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID,
+		api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_VALID,
+		api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_REPLAYED_SIGNATURE:
+		return result.FromDomain, result.FromKey
+	}
+	return "", ""
+}
+
+// newSignatureVerificationResult describes the verification of one signature,
+// including the attributes of the decoded signature when there is one.
+func newSignatureVerificationResult(decodeStatus api.SignatureDecodeStatus, verifyErr adscerterrors.VerifyErrorCode, acs *formats.AuthenticatedConnectionSignature) *api.SignatureVerificationResult {
+	result := &api.SignatureVerificationResult{SignatureDecodeStatus: decodeStatus}
+	if verifyErr != nil {
+		result.ErrorCode = verifyErr.Code
+		result.Reason = verifyErr.Err.Error()
+	}
+	if acs != nil {
+		result.FromDomain = acs.GetAttributeFrom()
+		result.FromKey = acs.GetAttributeFromKey()
+		result.ToDomain = acs.GetAttributeTo()
+		result.ToKey = acs.GetAttributeToKey()
+		result.InvokingDomain = acs.GetAttributeInvoking()
+		result.Timestamp = acs.GetAttributeTimestamp()
+		result.Nonce = acs.GetAttributeNonce()
+		result.SigningStatus = acs.GetAttributeStatusAsString()
+		result.SigningStatusName = acs.GetAttributeStatus().Name()
+	}
+	return result
+}
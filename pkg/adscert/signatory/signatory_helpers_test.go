@@ -39,3 +39,31 @@ func TestSetSignatureInfoFromAuthenticatedConnection(t *testing.T) {
 		t.Errorf("setSignatureInfoFromAuthenticatedConnection() diff (-want +got):\n%s", diff)
 	}
 }
+
+func TestGetSignatures(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		response *api.AuthenticatedConnectionSignatureResponse
+		want     []string
+	}{
+		{
+			desc:     "no request info",
+			response: &api.AuthenticatedConnectionSignatureResponse{},
+			want:     []string{},
+		},
+		{
+			desc: "skips signatures not produced",
+			response: &api.AuthenticatedConnectionSignatureResponse{RequestInfo: &api.RequestInfo{SignatureInfo: []*api.SignatureInfo{
+				{SignatureMessage: "from=a.com; sigb=x&sigu=y"},
+				{SignatureMessage: ""},
+				{SignatureMessage: "from=b.com; sigb=x&sigu=y"},
+			}}},
+			want: []string{"from=a.com; sigb=x&sigu=y", "from=b.com; sigb=x&sigu=y"},
+		},
+	}
+	for _, tC := range testCases {
+		if diff := cmp.Diff(tC.want, GetSignatures(tC.response)); diff != "" {
+			t.Errorf("GetSignatures() %s diff (-want +got):\n%s", tC.desc, diff)
+		}
+	}
+}
@@ -1,17 +1,20 @@
 package signatory
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io"
+	"sync/atomic"
 	"time"
 
 	"github.com/IABTechLab/adscert/internal/adscerterrors"
 	"github.com/IABTechLab/adscert/internal/formats"
 	"github.com/IABTechLab/adscert/pkg/adscert/api"
 	"github.com/IABTechLab/adscert/pkg/adscert/discovery"
+	"github.com/IABTechLab/adscert/pkg/adscert/logger"
 	"github.com/IABTechLab/adscert/pkg/adscert/metrics"
 	"github.com/benbjohnson/clock"
 )
@@ -25,28 +28,221 @@ func NewLocalAuthenticatedConnectionsSignatory(
 	domainCheckInterval time.Duration,
 	domainRenewalInterval time.Duration,
 	base64PrivateKeys []string) *LocalAuthenticatedConnectionsSignatory {
-	return &LocalAuthenticatedConnectionsSignatory{
-		originCallsign:      originCallsign,
-		secureRandom:        secureRandom,
-		clock:               clock,
-		counterpartyManager: discovery.NewDefaultDomainIndexer(dnsResolver, domainStore, domainCheckInterval, domainRenewalInterval, base64PrivateKeys),
+	return NewLocalAuthenticatedConnectionsSignatoryWithOptions(
+		originCallsign,
+		secureRandom,
+		clock,
+		dnsResolver,
+		domainStore,
+		base64PrivateKeys,
+		&LocalAuthenticatedConnectionsSignatoryOptions{
+			DomainCheckInterval:   domainCheckInterval,
+			DomainRenewalInterval: domainRenewalInterval,
+		})
+}
+
+func NewLocalAuthenticatedConnectionsSignatoryWithOptions(
+	originCallsign string,
+	secureRandom io.Reader,
+	clock clock.Clock,
+	dnsResolver discovery.DNSResolver,
+	domainStore discovery.DomainStore,
+	base64PrivateKeys []string,
+	options *LocalAuthenticatedConnectionsSignatoryOptions) *LocalAuthenticatedConnectionsSignatory {
+	s := &LocalAuthenticatedConnectionsSignatory{
+		originCallsign: originCallsign,
+		secureRandom:   secureRandom,
+		clock:          clock,
+		counterpartyManager: discovery.NewDomainIndexerWithOptions(dnsResolver, domainStore, base64PrivateKeys, &discovery.DomainIndexerOptions{
+			DomainCheckInterval:      options.DomainCheckInterval,
+			DomainRenewalInterval:    options.DomainRenewalInterval,
+			MinRefreshInterval:       options.MinRefreshInterval,
+			MaxRefreshInterval:       options.MaxRefreshInterval,
+			NegativeCacheInterval:    options.NegativeCacheInterval,
+			MaxNegativeCacheInterval: options.MaxNegativeCacheInterval,
+			OriginPrivateKeys:        options.AdditionalOrigins,
+			PrivateKeyRoles:          options.PrivateKeyRoles,
+			SynchronousFirstLookup:   options.SynchronousLookupTimeout > 0,
+		}),
+		replayWindow:             options.ReplayWindow,
+		synchronousLookupTimeout: options.SynchronousLookupTimeout,
+		signingStatus:            formats.StatusOK,
+		signingStatusPolicy:      options.SigningStatusPolicy,
+		signWhenDeactivated:      options.SignWhenDeactivated,
+		auditSink:                options.AuditSink,
+		auditSampler:             options.AuditSampler,
+		auditRedactionKey:        options.AuditRedactionKey,
+		auditOmitHashes:          options.AuditOmitHashes,
+	}
+	s.SetDeactivation(options.Deactivation)
+	if options.SigningStatus != "" {
+		signingStatus, ok := parseSigningStatus(options.SigningStatus)
+		if !ok {
+			logger.Fatalf("Unknown signing status %s", options.SigningStatus)
+		}
+		s.signingStatus = signingStatus
+	}
+	if s.signingStatusPolicy == nil {
+		s.signingStatusPolicy = DefaultSigningStatusPolicy
+	}
+	if s.replayWindow > 0 {
+		s.seenNonces = newNonceCache(options.ReplayCacheSize)
+	}
+	s.recipientAliases = map[string]bool{}
+	for _, alias := range options.RecipientAliases {
+		s.recipientAliases[alias] = true
+	}
+	s.additionalOrigins = map[string]bool{}
+	s.originKeyIDs = map[string]map[string]bool{originCallsign: privateKeyIDs(originCallsign, base64PrivateKeys)}
+	for origin, originKeys := range options.AdditionalOrigins {
+		s.additionalOrigins[origin] = true
+		s.originKeyIDs[origin] = privateKeyIDs(origin, originKeys)
 	}
+	return s
+}
+
+func privateKeyIDs(origin string, base64PrivateKeys []string) map[string]bool {
+	aliases, err := discovery.PrivateKeyAliases(base64PrivateKeys)
+	if err != nil {
+		logger.Fatalf("Error parsing private keys for origin %s: %v", origin, err)
+	}
+	result := map[string]bool{}
+	for _, alias := range aliases {
+		result[alias] = true
+	}
+	return result
+}
+
+// LocalAuthenticatedConnectionsSignatoryOptions holds the optional settings for
+// a LocalAuthenticatedConnectionsSignatory.  Zero values select the defaults.
+type LocalAuthenticatedConnectionsSignatoryOptions struct {
+	DomainCheckInterval   time.Duration
+	DomainRenewalInterval time.Duration
+
+	// Refresh scheduling for counterparty records, see discovery.DomainIndexerOptions.
+	MinRefreshInterval       time.Duration
+	MaxRefreshInterval       time.Duration
+	NegativeCacheInterval    time.Duration
+	MaxNegativeCacheInterval time.Duration
+
+	// SynchronousLookupTimeout, when set, makes signing and verification
+	// look up a counterparty seen for the first time inline, waiting up to
+	// this long for its records.  Zero leaves the lookup to the next update
+	// sweep, failing the request meanwhile.
+	SynchronousLookupTimeout time.Duration
+
+	// AdditionalOrigins hosts further ads.cert Call Signs in this signatory,
+	// each with its own private keys, keyed by callsign.  Signing requests
+	// select one through their origin field, and verification selects one
+	// from the signature's "to" attribute.
+	AdditionalOrigins map[string][]string
+
+	// RecipientAliases lists invoking domains whose ads.cert policy record
+	// delegates to one of the hosted origins.  Verification accepts a "to"
+	// attribute naming one of them once the delegation has been looked up.
+	// Other domains are never looked up on behalf of the "to" attribute,
+	// which isn't authenticated until the signature is checked.
+	RecipientAliases []string
+
+	// PrivateKeyRoles marks which local private keys are primary, and so used
+	// for signing, keyed by key alias.  See discovery.DomainIndexerOptions.
+	PrivateKeyRoles map[string]discovery.PrivateKeyRole
+
+	// SigningStatus is the status declared in signatures, by name, when the
+	// signing request doesn't ask for one.  Defaults to "ok".
+	SigningStatus string
+
+	// SigningStatusPolicy decides how verification treats signatures given
+	// their declared status.  Defaults to DefaultSigningStatusPolicy.
+	SigningStatusPolicy SigningStatusPolicy
+
+	// Deactivation is the initial deactivation settings, which can be changed
+	// later through SetDeactivation.  Defaults to nothing deactivated.
+	Deactivation *api.DeactivationSettings
+
+	// SignWhenDeactivated makes deactivated signing produce signatures
+	// declaring the deactivated status, rather than failing without any.
+	SignWhenDeactivated bool
+
+	// AuditSink, when set, receives an AuditEvent for every signature signed
+	// or verified.
+	AuditSink AuditSink
+
+	// AuditSampler decides what fraction of events are audited, such as all
+	// failures but few successes.  Defaults to auditing every event.
+	AuditSampler AuditSampler
+
+	// AuditRedactionKey, when set, replaces signatures in audit events by
+	// their HMAC under this key, keeping the request hashes.  Redacted events
+	// can't be replayed by the offline verifier.
+	AuditRedactionKey []byte
+
+	// AuditOmitHashes leaves the body and URL hashes out of audit events,
+	// which then can't be replayed by the offline verifier either.
+	AuditOmitHashes bool
+
+	// ReplayWindow is the maximum allowed difference between a signature's
+	// timestamp and the verifier's clock.  Signatures outside the window are
+	// rejected as stale, and nonces seen within the window are rejected as
+	// replays.  Zero disables replay protection.
+	ReplayWindow time.Duration
+
+	// ReplayCacheSize bounds the number of (from, nonce) pairs remembered for
+	// replay detection.  Entries are only dropped once they expire, so while
+	// the cache is full, signatures with new nonces are rejected as replays
+	// rather than risking accepting a replay.  Size it above the expected
+	// number of signatures verified within twice the ReplayWindow.
+	ReplayCacheSize int
 }
 
 type LocalAuthenticatedConnectionsSignatory struct {
-	originCallsign string
-	secureRandom   io.Reader
-	clock          clock.Clock
+	originCallsign    string
+	additionalOrigins map[string]bool
+	recipientAliases  map[string]bool
+	originKeyIDs      map[string]map[string]bool // aliases of the private keys held for each origin
+	secureRandom      io.Reader
+	clock             clock.Clock
 
 	counterpartyManager discovery.DomainIndexer
+
+	replayWindow time.Duration
+	seenNonces   *nonceCache
+
+	signingStatus       formats.AuthenticatedConnectionProtocolStatus
+	signingStatusPolicy SigningStatusPolicy
+
+	deactivation        atomic.Value // *deactivationState
+	signWhenDeactivated bool
+
+	auditSink         AuditSink
+	auditSampler      AuditSampler
+	auditRedactionKey []byte
+	auditOmitHashes   bool
+
+	synchronousLookupTimeout time.Duration
 }
 
+var _ BatchAuthenticatedConnectionsSignatory = (*LocalAuthenticatedConnectionsSignatory)(nil)
+
 func (s *LocalAuthenticatedConnectionsSignatory) SignAuthenticatedConnection(request *api.AuthenticatedConnectionSignatureRequest) (*api.AuthenticatedConnectionSignatureResponse, error) {
+	return s.SignAuthenticatedConnectionContext(context.Background(), request)
+}
+
+func (s *LocalAuthenticatedConnectionsSignatory) SignAuthenticatedConnectionContext(ctx context.Context, request *api.AuthenticatedConnectionSignatureRequest) (*api.AuthenticatedConnectionSignatureResponse, error) {
+	return s.signAuthenticatedConnection(ctx, request, s.lookupIdentitiesForDomain)
+}
+
+// identityLookup returns the identity domains of a counterparty's invoking
+// domain, so that batches can share lookups between their requests.
+type identityLookup func(ctx context.Context, domain string) ([]discovery.DomainInfo, error)
+
+func (s *LocalAuthenticatedConnectionsSignatory) signAuthenticatedConnection(ctx context.Context, request *api.AuthenticatedConnectionSignatureRequest, lookupIdentities identityLookup) (*api.AuthenticatedConnectionSignatureResponse, error) {
 	var err error
 	startTime := s.clock.Now()
 	response := &api.AuthenticatedConnectionSignatureResponse{RequestInfo: request.RequestInfo}
+	defer s.auditSigning(response, startTime)
 
-	if request.RequestInfo.InvokingDomain == "dryrun" {
+	if request.RequestInfo != nil && request.RequestInfo.InvokingDomain == "dryrun" {
 		response.SignatureOperationStatus = api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK
 		return response, nil
 	}
@@ -56,46 +252,95 @@ func (s *LocalAuthenticatedConnectionsSignatory) SignAuthenticatedConnection(req
 		return response, errors.New("required parameters are missing")
 	}
 
+	// metrics name the recipient by invoking domain until its identity domain is known
+	counterparty := request.RequestInfo.InvokingDomain
+
 	// add nonce and timestamp if not already provided in the request
 	// this is the typical case to keep the client's usage simple
 	if request.Timestamp == "" {
-		request.Timestamp = s.clock.Now().UTC().Format("060102T150405")
+		request.Timestamp = s.clock.Now().UTC().Format(signatureTimestampFormat)
 	}
 	if request.Nonce == "" {
 		if request.Nonce, err = s.generateNonce(); err != nil {
-			metrics.RecordSigning(adscerterrors.ErrSigningGenerateNonce)
+			metrics.RecordSigning(adscerterrors.ErrSigningGenerateNonce, counterparty, "")
 			response.SignatureOperationStatus = api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_SIGNATORY_INTERNAL_ERROR
 			return response, err
 		}
 	}
 
-	domainInfos, err := s.counterpartyManager.LookupIdentitiesForDomain(request.RequestInfo.InvokingDomain)
+	origin := request.Origin
+	if origin == "" {
+		origin = s.originCallsign
+	}
+	if origin != s.originCallsign && !s.additionalOrigins[origin] {
+		metrics.RecordSigning(adscerterrors.ErrSigningUnknownOrigin, counterparty, "")
+		response.SignatureOperationStatus = api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_MALFORMED_REQUEST
+		return response, fmt.Errorf("origin %s is not hosted by this signatory", origin)
+	}
+
+	signingStatus := s.signingStatus
+	if request.SigningStatus != "" {
+		var ok bool
+		if signingStatus, ok = parseSigningStatus(request.SigningStatus); !ok {
+			metrics.RecordSigning(adscerterrors.ErrSigningUnknownStatus, counterparty, "")
+			response.SignatureOperationStatus = api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_MALFORMED_REQUEST
+			return response, fmt.Errorf("unknown signing status %s", request.SigningStatus)
+		}
+	}
+
+	deactivation := s.getDeactivationState()
+	if deactivation.isDeactivated(origin, request.RequestInfo.InvokingDomain) {
+		if !s.signWhenDeactivated {
+			metrics.RecordSigning(adscerterrors.ErrSigningDeactivated, counterparty, "")
+			response.SignatureOperationStatus = api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_SIGNATORY_DEACTIVATED
+			return response, nil
+		}
+		signingStatus = formats.StatusDeactivated
+	}
+
+	domainInfos, err := lookupIdentities(ctx, request.RequestInfo.InvokingDomain)
 	if err != nil || len(domainInfos) == 0 {
-		metrics.RecordSigning(adscerterrors.ErrSigningCounterpartyLookup)
+		metrics.RecordSigning(adscerterrors.ErrSigningCounterpartyLookup, counterparty, "")
 		response.SignatureOperationStatus = api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_SIGNATORY_INTERNAL_ERROR
 		return response, err
 	}
 
+	counterparty = domainInfos[0].GetAdsCertIdentityDomain()
+
+	// Counterparties may also be deactivated by their identity domain
+	var identityDomains []string
+	for _, domainInfo := range domainInfos {
+		identityDomains = append(identityDomains, domainInfo.GetAdsCertIdentityDomain())
+	}
+	if signingStatus != formats.StatusDeactivated && deactivation.isDeactivated(origin, identityDomains...) {
+		if !s.signWhenDeactivated {
+			metrics.RecordSigning(adscerterrors.ErrSigningDeactivated, counterparty, "")
+			response.SignatureOperationStatus = api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_SIGNATORY_DEACTIVATED
+			return response, nil
+		}
+		signingStatus = formats.StatusDeactivated
+	}
+
 	for _, domainInfo := range domainInfos {
-		signatureInfo, err := s.signSingleMessage(request, domainInfo)
+		signatureInfo, err := s.signSingleMessage(request, origin, signingStatus, domainInfo)
 		if err != nil {
-			metrics.RecordSigning(adscerterrors.ErrSigningEmbossMessage)
+			metrics.RecordSigning(adscerterrors.ErrSigningEmbossMessage, counterparty, signatureInfo.GetToKey())
 			response.SignatureOperationStatus = api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_SIGNATORY_INTERNAL_ERROR
 			return response, err
 		}
 		response.RequestInfo.SignatureInfo = append(response.RequestInfo.SignatureInfo, signatureInfo)
 	}
 
-	metrics.RecordSigning(nil)
+	metrics.RecordSigning(nil, counterparty, response.RequestInfo.SignatureInfo[0].ToKey)
 	metrics.RecordSigningTime(time.Since(startTime))
 	response.SignatureOperationStatus = api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK
 	return response, nil
 }
 
-func (s *LocalAuthenticatedConnectionsSignatory) signSingleMessage(request *api.AuthenticatedConnectionSignatureRequest, domainInfo discovery.DomainInfo) (*api.SignatureInfo, error) {
+func (s *LocalAuthenticatedConnectionsSignatory) signSingleMessage(request *api.AuthenticatedConnectionSignatureRequest, origin string, signingStatus formats.AuthenticatedConnectionProtocolStatus, domainInfo discovery.DomainInfo) (*api.SignatureInfo, error) {
 
 	sigInfo := &api.SignatureInfo{}
-	acs, err := formats.NewAuthenticatedConnectionSignature(formats.StatusOK, s.originCallsign, request.RequestInfo.InvokingDomain)
+	acs, err := formats.NewAuthenticatedConnectionSignature(formats.StatusOK, origin, request.RequestInfo.InvokingDomain)
 	if err != nil {
 		acs.SetStatus(formats.StatusErrorOnSignature)
 		setSignatureInfoFromAuthenticatedConnection(sigInfo, acs)
@@ -108,7 +353,7 @@ func (s *LocalAuthenticatedConnectionsSignatory) signSingleMessage(request *api.
 		return sigInfo, fmt.Errorf("domain info is not available: %v", err)
 	}
 
-	sharedSecret, hasSecret := domainInfo.GetSharedSecret()
+	sharedSecret, hasSecret := s.getSharedSecretForOrigin(domainInfo, origin)
 	if hasSecret {
 		err = acs.AddParametersForSignature(sharedSecret.LocalKeyID(), domainInfo.GetAdsCertIdentityDomain(), sharedSecret.RemoteKeyID(), request.Timestamp, request.Nonce)
 		if err != nil {
@@ -123,26 +368,42 @@ func (s *LocalAuthenticatedConnectionsSignatory) signSingleMessage(request *api.
 		return sigInfo, nil
 	}
 
-	acs.SetStatus(formats.StatusOK)
+	acs.SetStatus(signingStatus)
 	setSignatureInfoFromAuthenticatedConnection(sigInfo, acs)
 	message := acs.EncodeMessage()
-	bodyHMAC, urlHMAC := generateSignatures(domainInfo, []byte(message), request.RequestInfo.BodyHash[:], request.RequestInfo.UrlHash[:])
+	bodyHMAC, urlHMAC := generateSignatures(sharedSecret, []byte(message), request.RequestInfo.BodyHash[:], request.RequestInfo.UrlHash[:])
 	sigInfo.SignatureMessage = message + formats.EncodeSignatureSuffix(bodyHMAC, urlHMAC)
 
 	return sigInfo, nil
 }
 
 func (s *LocalAuthenticatedConnectionsSignatory) VerifyAuthenticatedConnection(request *api.AuthenticatedConnectionVerificationRequest) (*api.AuthenticatedConnectionVerificationResponse, error) {
+	return s.VerifyAuthenticatedConnectionContext(context.Background(), request)
+}
+
+func (s *LocalAuthenticatedConnectionsSignatory) VerifyAuthenticatedConnectionContext(ctx context.Context, request *api.AuthenticatedConnectionVerificationRequest) (*api.AuthenticatedConnectionVerificationResponse, error) {
 
 	startTime := s.clock.Now()
 	response := &api.AuthenticatedConnectionVerificationResponse{}
 
+	if s.getDeactivationState().settings.All {
+		response.VerificationOperationStatus = api.VerificationOperationStatus_VERIFICATION_OPERATION_STATUS_SIGNATORY_DEACTIVATED
+		return response, nil
+	}
+
 	for _, requestInfo := range request.RequestInfo {
 		verificationInfo := &api.RequestVerificationInfo{}
+		metrics.RecordVerifySignatureCount(len(requestInfo.SignatureInfo))
 
 		for _, signatureInfo := range requestInfo.SignatureInfo {
-			decodeStatus := s.checkSingleSignature(requestInfo, signatureInfo)
+			checkStartTime := s.clock.Now()
+			decodeStatus, verifyErr, acs := s.checkSingleSignature(ctx, requestInfo, signatureInfo)
 			verificationInfo.SignatureDecodeStatus = append(verificationInfo.SignatureDecodeStatus, decodeStatus)
+			result := newSignatureVerificationResult(decodeStatus, verifyErr, acs)
+			recordVerifyOutcome(verifyErr, result)
+			result.Flagged = verifyErr == nil && s.signingStatusPolicy(result.SigningStatusName) == SigningStatusFlag
+			verificationInfo.SignatureResults = append(verificationInfo.SignatureResults, result)
+			s.auditVerification(requestInfo, signatureInfo, result, checkStartTime)
 		}
 
 		response.VerificationInfo = append(response.VerificationInfo, verificationInfo)
@@ -153,54 +414,196 @@ func (s *LocalAuthenticatedConnectionsSignatory) VerifyAuthenticatedConnection(r
 	return response, nil
 }
 
-func (s *LocalAuthenticatedConnectionsSignatory) checkSingleSignature(requestInfo *api.RequestInfo, signatureInfo *api.SignatureInfo) api.SignatureDecodeStatus {
+// checkSingleSignature verifies one signature of a request.  Along with the
+// decode status it returns the error code explaining a failure, nil when the
+// signature is valid, and the decoded signature unless it couldn't be decoded.
+func (s *LocalAuthenticatedConnectionsSignatory) checkSingleSignature(ctx context.Context, requestInfo *api.RequestInfo, signatureInfo *api.SignatureInfo) (api.SignatureDecodeStatus, adscerterrors.VerifyErrorCode, *formats.AuthenticatedConnectionSignature) {
 
 	acs, err := formats.DecodeAuthenticatedConnectionSignature(signatureInfo.SignatureMessage)
 	if err != nil {
-		metrics.RecordVerify(adscerterrors.ErrVerifyDecodeSignature)
-		return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_SIGNATURE_MALFORMED
+		return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_SIGNATURE_MALFORMED, adscerterrors.ErrVerifyDecodeSignature, nil
 	}
 
 	// Validate invocation hostname matches request
 	if acs.GetAttributeInvoking() != requestInfo.InvokingDomain {
-		metrics.RecordVerify(adscerterrors.ErrVerifySignatureRequestHostMismatch)
-		return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_UNRELATED_SIGNATURE
+		return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_UNRELATED_SIGNATURE, adscerterrors.ErrVerifySignatureRequestHostMismatch, acs
 	}
 
-	domainInfos, err := s.counterpartyManager.LookupIdentitiesForDomain(acs.GetAttributeFrom())
+	// Reject signatures outside the freshness window before doing any lookups
+	var signatureExpiry time.Time
+	if s.seenNonces != nil {
+		signedAt, err := parseSignatureTimestamp(acs.GetAttributeTimestamp())
+		if err != nil || acs.GetAttributeNonce() == "" {
+			return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_SIGNATURE_MALFORMED, adscerterrors.ErrVerifyDecodeSignature, acs
+		}
+		if !isWithinWindow(signedAt, s.clock.Now(), s.replayWindow) {
+			return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_STALE_SIGNATURE, adscerterrors.ErrVerifyStaleSignature, acs
+		}
+		signatureExpiry = signedAt.Add(s.replayWindow)
+	}
+
+	// Reject signatures whose declared status the policy doesn't accept,
+	// such as those of a deactivated signer
+	if s.signingStatusPolicy(acs.GetAttributeStatus().Name()) == SigningStatusReject {
+		return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_REJECTED_SIGNING_STATUS, adscerterrors.ErrVerifyRejectedSigningStatus, acs
+	}
+
+	// Reject signatures made for someone else, which could otherwise be
+	// replayed to us by their recipient
+	localOrigin, isRecipient := s.recipientOrigin(ctx, acs.GetAttributeTo())
+	if !isRecipient {
+		return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_WRONG_RECIPIENT, adscerterrors.ErrVerifyWrongRecipient, acs
+	}
+
+	deactivation := s.getDeactivationState()
+	if deactivation.isDeactivated(localOrigin, acs.GetAttributeFrom()) {
+		return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_DEACTIVATED, adscerterrors.ErrVerifyDeactivated, acs
+	}
+
+	domainInfos, err := s.lookupIdentitiesForDomain(ctx, acs.GetAttributeFrom())
 	if err != nil || len(domainInfos) == 0 {
-		metrics.RecordVerify(adscerterrors.ErrVerifyCounterpartyLookup)
-		return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_COUNTERPARTY_LOOKUP_ERROR
+		return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_COUNTERPARTY_LOOKUP_ERROR, adscerterrors.ErrVerifyCounterpartyLookup, acs
 	}
 
+	var usableDomain, checkedSignature, keyNotPublished bool
 	for _, domainInfo := range domainInfos {
-		if _, hasSecret := domainInfo.GetSharedSecret(); !hasSecret {
-			metrics.RecordVerify(adscerterrors.ErrVerifyMissingSharedSecret)
-			return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_NO_SHARED_SECRET_AVAILABLE
+		// As when signing, keys of a domain whose records failed to refresh
+		// cleanly, such as failing DNSSEC validation, aren't trusted.
+		if domainInfo.GetStatus() != discovery.DomainStatusOK {
+			continue
 		}
+		usableDomain = true
 
-		bodyHMAC, urlHMAC := generateSignatures(domainInfo, []byte(acs.EncodeMessage()), requestInfo.BodyHash[:], requestInfo.UrlHash[:])
+		sharedSecret, hasSecret, keyPublished := s.getSharedSecretForSignature(domainInfo, acs, localOrigin)
+		if !hasSecret {
+			keyNotPublished = keyNotPublished || !keyPublished
+			continue
+		}
+		checkedSignature = true
+
+		bodyHMAC, urlHMAC := generateSignatures(sharedSecret, []byte(acs.EncodeMessage()), requestInfo.BodyHash[:], requestInfo.UrlHash[:])
 		bodyValid, urlValid := acs.CompareSignatures(bodyHMAC, urlHMAC)
+
+		// Nonces are only recorded once the signature is authenticated so that
+		// forged signatures can't be used to poison the cache.
+		if bodyValid && s.seenNonces != nil {
+			switch s.seenNonces.checkAndRecord(acs.GetAttributeFrom(), acs.GetAttributeNonce(), signatureExpiry, s.clock.Now()) {
+			case nonceReplayed:
+				return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_REPLAYED_SIGNATURE, adscerterrors.ErrVerifyReplayedSignature, acs
+			case nonceCacheFull:
+				metrics.RecordReplayCacheFull()
+				return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_REPLAYED_SIGNATURE, adscerterrors.ErrVerifyReplayCacheFull, acs
+			}
+		}
+
 		if bodyValid && urlValid {
-			metrics.RecordVerify(nil)
-			return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID
+			return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID, nil, acs
 		} else if bodyValid {
-			metrics.RecordVerify(nil)
-			return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_VALID
+			return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_VALID, nil, acs
+		}
+	}
+
+	switch {
+	case !usableDomain:
+		return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_COUNTERPARTY_LOOKUP_ERROR, adscerterrors.ErrVerifyCounterpartyLookup, acs
+	case checkedSignature:
+		return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_INVALID_SIGNATURE, adscerterrors.ErrVerifyInvalidSignature, acs
+	case keyNotPublished:
+		return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_KEY_NOT_PUBLISHED, adscerterrors.ErrVerifyKeyNotPublished, acs
+	default:
+		return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_NO_SHARED_SECRET_AVAILABLE, adscerterrors.ErrVerifyMissingSharedSecret, acs
+	}
+}
+
+// recipientOrigin returns which of the origins hosted by this signatory a
+// signature's "to" attribute names.  Besides the origins themselves, the
+// configured recipient aliases are accepted when their ads.cert policy record
+// delegates to one of them.  Since "to" isn't authenticated yet, any other
+// value is rejected without a lookup.
+func (s *LocalAuthenticatedConnectionsSignatory) recipientOrigin(ctx context.Context, to string) (string, bool) {
+	if to == "" {
+		return "", false
+	}
+	if s.isOrigin(to) {
+		return to, true
+	}
+	if !s.recipientAliases[to] {
+		return "", false
+	}
+
+	domainInfos, err := s.lookupIdentitiesForDomain(ctx, to)
+	if err != nil {
+		return "", false
+	}
+	for _, domainInfo := range domainInfos {
+		if identityDomain := domainInfo.GetAdsCertIdentityDomain(); s.isOrigin(identityDomain) {
+			return identityDomain, true
 		}
 	}
+	return "", false
+}
 
-	metrics.RecordVerify(adscerterrors.ErrVerifyInvalidSignature)
-	return api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_INVALID_SIGNATURE
+func (s *LocalAuthenticatedConnectionsSignatory) isOrigin(domain string) bool {
+	return domain == s.originCallsign || s.additionalOrigins[domain]
+}
+
+// getSharedSecretForSignature returns the shared secret for the key pair named
+// by the signature's to_key and from_key attributes, so that signatures made
+// with any published key verify during a key rotation.  The to_key must be
+// one of the keys held for localOrigin, so that a signature addressed to one
+// origin can't be verified with another origin's keys.  Signatures without
+// key attributes are checked against the origin's current shared secret.
+// When no secret is found, keyPublished reports false if that is because the
+// signature names a key which isn't published (or held by us) any longer.
+func (s *LocalAuthenticatedConnectionsSignatory) getSharedSecretForSignature(domainInfo discovery.DomainInfo, acs *formats.AuthenticatedConnectionSignature, localOrigin string) (sharedSecret discovery.SharedSecret, ok bool, keyPublished bool) {
+	localKeyID, remoteKeyID := acs.GetAttributeToKey(), acs.GetAttributeFromKey()
+	if localKeyID == "" && remoteKeyID == "" {
+		sharedSecret, ok = s.getSharedSecretForOrigin(domainInfo, localOrigin)
+		return sharedSecret, ok, true
+	}
+	if !s.originKeyIDs[localOrigin][localKeyID] {
+		return nil, false, false
+	}
+
+	if sharedSecret, ok = domainInfo.GetSharedSecretForKeyPair(localKeyID, remoteKeyID); ok {
+		return sharedSecret, true, true
+	}
+
+	// A domain with no shared secrets at all hasn't been resolved yet, rather
+	// than having stopped publishing the key.
+	if _, hasAnySecret := s.getSharedSecretForOrigin(domainInfo, localOrigin); !hasAnySecret {
+		return nil, false, true
+	}
+	return nil, false, domainInfo.HasPublicKey(remoteKeyID)
+}
+
+// lookupIdentitiesForDomain waits up to the synchronous lookup timeout for a
+// counterparty which hasn't been looked up before, or until ctx is done if
+// that is sooner.
+func (s *LocalAuthenticatedConnectionsSignatory) lookupIdentitiesForDomain(ctx context.Context, domain string) ([]discovery.DomainInfo, error) {
+	if s.synchronousLookupTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.synchronousLookupTimeout)
+		defer cancel()
+	}
+	return s.counterpartyManager.LookupIdentitiesForDomainContext(ctx, domain)
 }
 
 func (s *LocalAuthenticatedConnectionsSignatory) IsHealthy() bool {
 	return time.Since(s.counterpartyManager.GetLastRun()) <= 5*time.Minute
 }
 
-func generateSignatures(domainInfo discovery.DomainInfo, message []byte, bodyHash []byte, urlHash []byte) ([]byte, []byte) {
+// getSharedSecretForOrigin returns the current shared secret between the
+// counterparty and one of the origins hosted by this signatory.
+func (s *LocalAuthenticatedConnectionsSignatory) getSharedSecretForOrigin(domainInfo discovery.DomainInfo, origin string) (discovery.SharedSecret, bool) {
+	if origin == s.originCallsign {
+		return domainInfo.GetSharedSecret()
+	}
+	return domainInfo.GetSharedSecretForOrigin(origin)
+}
+
+func generateSignatures(sharedSecret discovery.SharedSecret, message []byte, bodyHash []byte, urlHash []byte) ([]byte, []byte) {
 
-	sharedSecret, _ := domainInfo.GetSharedSecret()
 	h := hmac.New(sha256.New, sharedSecret.Secret()[:])
 
 	h.Write([]byte(message))
@@ -214,13 +617,27 @@ func generateSignatures(domainInfo discovery.DomainInfo, message []byte, bodyHas
 }
 
 func (s *LocalAuthenticatedConnectionsSignatory) generateNonce() (string, error) {
-	var nonce [32]byte
-	n, err := io.ReadFull(s.secureRandom, nonce[:])
+	nonces, err := s.generateNonces(1)
+	if err != nil {
+		return "", err
+	}
+	return nonces[0], nil
+}
+
+// generateNonces returns count nonces from a single read of secure randomness.
+func (s *LocalAuthenticatedConnectionsSignatory) generateNonces(count int) ([]string, error) {
+	const nonceSize = 32
+	random := make([]byte, nonceSize*count)
+	n, err := io.ReadFull(s.secureRandom, random)
 	if err != nil {
-		return "", fmt.Errorf("error generating random: %v", err)
+		return nil, fmt.Errorf("error generating random: %v", err)
+	}
+	if n != len(random) {
+		return nil, fmt.Errorf("unexpected number of random values: %d", n)
 	}
-	if n != 32 {
-		return "", fmt.Errorf("unexpected number of random values: %d", n)
+	nonces := make([]string, count)
+	for i := range nonces {
+		nonces[i] = formats.B64truncate(random[i*nonceSize:(i+1)*nonceSize], 12)
 	}
-	return formats.B64truncate(nonce[:], 12), nil
+	return nonces, nil
 }
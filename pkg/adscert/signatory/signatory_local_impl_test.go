@@ -0,0 +1,873 @@
+package signatory
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IABTechLab/adscert/internal/adscerterrors"
+	"github.com/IABTechLab/adscert/internal/formats"
+	"github.com/IABTechLab/adscert/pkg/adscert/api"
+	"github.com/IABTechLab/adscert/pkg/adscert/discovery"
+	"github.com/IABTechLab/adscert/pkg/adscert/metrics"
+	"github.com/benbjohnson/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeDNSResolver serves ads.cert key records for a fixed set of callsigns,
+// failing lookups of the names in failures.
+type fakeDNSResolver struct {
+	records  map[string][]string
+	failures map[string]error
+}
+
+func newFakeDNSResolver(callsigns ...string) *fakeDNSResolver {
+	r := &fakeDNSResolver{records: map[string][]string{}}
+	for _, callsign := range callsigns {
+		r.publishKeys(callsign, callsign)
+	}
+	return r
+}
+
+// publishKeys publishes the public keys generated from each seed as the keys
+// record for callsign, with the first listed as current.
+func (r *fakeDNSResolver) publishKeys(callsign string, seeds ...string) {
+	record := "v=adcrtd k=x25519 h=sha256"
+	for _, seed := range seeds {
+		publicKey, _ := GenerateFakeKeyPairFromDomainNameForTesting(seed)
+		record += fmt.Sprintf(" p=%s", base64.RawURLEncoding.EncodeToString(publicKey[:]))
+	}
+	r.records["_delivery._adscert."+callsign] = []string{record}
+}
+
+func (r *fakeDNSResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	records, _, err := r.LookupTXTWithTTL(ctx, name)
+	return records, err
+}
+
+func (r *fakeDNSResolver) LookupTXTWithTTL(ctx context.Context, name string) ([]string, time.Duration, error) {
+	if err, ok := r.failures[name]; ok {
+		return nil, 0, err
+	}
+	if records, ok := r.records[name]; ok {
+		return records, time.Minute, nil
+	}
+	return nil, 0, discovery.ErrDNSRecordNotFound
+}
+
+// recordingDNSResolver records the names looked up through it.
+type recordingDNSResolver struct {
+	discovery.DNSResolver
+
+	mutex sync.Mutex
+	names map[string]bool
+}
+
+func (r *recordingDNSResolver) LookupTXTWithTTL(ctx context.Context, name string) ([]string, time.Duration, error) {
+	r.mutex.Lock()
+	r.names[name] = true
+	r.mutex.Unlock()
+	return r.DNSResolver.LookupTXTWithTTL(ctx, name)
+}
+
+func (r *recordingDNSResolver) lookedUp(name string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.names[name]
+}
+
+func fakePrivateKeys(callsign string) []string {
+	_, privateKey := GenerateFakeKeyPairFromDomainNameForTesting(callsign)
+	return []string{base64.RawURLEncoding.EncodeToString(privateKey[:])}
+}
+
+func newTestSignatory(resolver discovery.DNSResolver, origin string, options *LocalAuthenticatedConnectionsSignatoryOptions) *LocalAuthenticatedConnectionsSignatory {
+	options.SynchronousLookupTimeout = time.Second
+	return NewLocalAuthenticatedConnectionsSignatoryWithOptions(
+		origin, rand.Reader, clock.New(), resolver, discovery.NewDefaultDomainStore(), fakePrivateKeys(origin), options)
+}
+
+func TestMultiOriginSignatory(t *testing.T) {
+	resolver := newFakeDNSResolver("default-origin.com", "second-origin.com", "counterparty.com")
+	multiOrigin := newTestSignatory(resolver, "default-origin.com", &LocalAuthenticatedConnectionsSignatoryOptions{
+		AdditionalOrigins: map[string][]string{"second-origin.com": fakePrivateKeys("second-origin.com")},
+	})
+	counterparty := newTestSignatory(resolver, "counterparty.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+
+	testCases := []struct {
+		desc   string
+		origin string
+
+		wantFrom   string
+		wantStatus api.SignatureOperationStatus
+	}{
+		{
+			desc:       "default origin",
+			wantFrom:   "default-origin.com",
+			wantStatus: api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK,
+		},
+		{
+			desc:       "additional origin",
+			origin:     "second-origin.com",
+			wantFrom:   "second-origin.com",
+			wantStatus: api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK,
+		},
+		{
+			desc:       "unknown origin",
+			origin:     "unknown-origin.com",
+			wantStatus: api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_MALFORMED_REQUEST,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			requestInfo := &api.RequestInfo{}
+			SetRequestInfo(requestInfo, "https://counterparty.com/bid", []byte("body"))
+
+			response, _ := multiOrigin.SignAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionSignatureRequest{
+				RequestInfo: requestInfo,
+				Origin:      tC.origin,
+			})
+			if response.SignatureOperationStatus != tC.wantStatus {
+				t.Fatalf("SignAuthenticatedConnectionContext() %s: got status %v, want %v", tC.desc, response.SignatureOperationStatus, tC.wantStatus)
+			}
+			if tC.wantStatus != api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK {
+				return
+			}
+			if got := response.RequestInfo.SignatureInfo[0].FromDomain; got != tC.wantFrom {
+				t.Errorf("SignAuthenticatedConnectionContext() %s: got from %s, want %s", tC.desc, got, tC.wantFrom)
+			}
+
+			// The counterparty verifies the signature from whichever origin signed it.
+			verifyResponse, err := counterparty.VerifyAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionVerificationRequest{
+				RequestInfo: []*api.RequestInfo{response.RequestInfo},
+			})
+			if err != nil {
+				t.Fatalf("VerifyAuthenticatedConnectionContext() %s: unexpected error: %v", tC.desc, err)
+			}
+			if got := verifyResponse.VerificationInfo[0].SignatureDecodeStatus[0]; got != api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID {
+				t.Errorf("VerifyAuthenticatedConnectionContext() %s: got %v, want %v", tC.desc, got, api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID)
+			}
+
+			// Signatures addressed to the origin are verified with that origin's keys.
+			requestInfo = &api.RequestInfo{}
+			SetRequestInfo(requestInfo, "https://"+tC.wantFrom+"/bid", []byte("body"))
+			reply, _ := counterparty.SignAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionSignatureRequest{RequestInfo: requestInfo})
+			verifyResponse, err = multiOrigin.VerifyAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionVerificationRequest{
+				RequestInfo: []*api.RequestInfo{reply.RequestInfo},
+			})
+			if err != nil {
+				t.Fatalf("VerifyAuthenticatedConnectionContext() %s reply: unexpected error: %v", tC.desc, err)
+			}
+			if got := verifyResponse.VerificationInfo[0].SignatureDecodeStatus[0]; got != api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID {
+				t.Errorf("VerifyAuthenticatedConnectionContext() %s reply: got %v, want %v", tC.desc, got, api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID)
+			}
+		})
+	}
+}
+
+func TestVerifyDuringKeyRotation(t *testing.T) {
+	const rotatedKeySeed = "counterparty.com/rotated"
+
+	// The counterparty still signs with its original key, having published a
+	// rotated key as current.
+	counterparty := newTestSignatory(newFakeDNSResolver("verifier.com"), "counterparty.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+
+	testCases := []struct {
+		desc      string
+		published []string
+
+		wantStatus    api.SignatureDecodeStatus
+		wantErrorCode string
+	}{
+		{
+			desc:       "original key still published",
+			published:  []string{rotatedKeySeed, "counterparty.com"},
+			wantStatus: api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID,
+		},
+		{
+			desc:          "original key no longer published",
+			published:     []string{rotatedKeySeed},
+			wantStatus:    api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_KEY_NOT_PUBLISHED,
+			wantErrorCode: "key_not_published",
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			resolver := newFakeDNSResolver("verifier.com")
+			resolver.publishKeys("counterparty.com", tC.published...)
+			verifier := newTestSignatory(resolver, "verifier.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+
+			requestInfo := &api.RequestInfo{}
+			SetRequestInfo(requestInfo, "https://verifier.com/bid", []byte("body"))
+			response, _ := counterparty.SignAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionSignatureRequest{RequestInfo: requestInfo})
+			if response.SignatureOperationStatus != api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK {
+				t.Fatalf("SignAuthenticatedConnectionContext() %s: got status %v", tC.desc, response.SignatureOperationStatus)
+			}
+
+			verifyResponse, err := verifier.VerifyAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionVerificationRequest{
+				RequestInfo: []*api.RequestInfo{response.RequestInfo},
+			})
+			if err != nil {
+				t.Fatalf("VerifyAuthenticatedConnectionContext() %s: unexpected error: %v", tC.desc, err)
+			}
+			if got := verifyResponse.VerificationInfo[0].SignatureDecodeStatus[0]; got != tC.wantStatus {
+				t.Errorf("VerifyAuthenticatedConnectionContext() %s: got %v, want %v", tC.desc, got, tC.wantStatus)
+			}
+
+			// The result reports the decoded signature and why it failed.
+			signed := response.RequestInfo.SignatureInfo[0]
+			result := verifyResponse.VerificationInfo[0].SignatureResults[0]
+			if result.SignatureDecodeStatus != tC.wantStatus || result.ErrorCode != tC.wantErrorCode {
+				t.Errorf("VerifyAuthenticatedConnectionContext() %s result: got %v (%q), want %v (%q)", tC.desc, result.SignatureDecodeStatus, result.ErrorCode, tC.wantStatus, tC.wantErrorCode)
+			}
+			if (result.Reason == "") != (tC.wantErrorCode == "") {
+				t.Errorf("VerifyAuthenticatedConnectionContext() %s result: got reason %q for error code %q", tC.desc, result.Reason, result.ErrorCode)
+			}
+			if result.FromDomain != signed.FromDomain || result.FromKey != signed.FromKey || result.ToDomain != signed.ToDomain || result.ToKey != signed.ToKey {
+				t.Errorf("VerifyAuthenticatedConnectionContext() %s result: got %v, want attributes of %v", tC.desc, result, signed)
+			}
+			if result.Timestamp == "" || result.Nonce == "" {
+				t.Errorf("VerifyAuthenticatedConnectionContext() %s result: got timestamp %q, nonce %q, want both set", tC.desc, result.Timestamp, result.Nonce)
+			}
+		})
+	}
+}
+
+func TestVerifyKeyPairOfOtherOrigin(t *testing.T) {
+	resolver := newFakeDNSResolver("default-origin.com", "second-origin.com", "counterparty.com")
+	verifier := newTestSignatory(resolver, "default-origin.com", &LocalAuthenticatedConnectionsSignatoryOptions{
+		AdditionalOrigins: map[string][]string{"second-origin.com": fakePrivateKeys("second-origin.com")},
+	})
+
+	testCases := []struct {
+		desc            string
+		defaultOriginAs string // seed of the key the counterparty believes default-origin.com publishes
+
+		wantStatus api.SignatureDecodeStatus
+	}{
+		{
+			desc:            "key of the addressed origin",
+			defaultOriginAs: "default-origin.com",
+			wantStatus:      api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID,
+		},
+		{
+			desc:            "key of another origin",
+			defaultOriginAs: "second-origin.com",
+			wantStatus:      api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_KEY_NOT_PUBLISHED,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			// The counterparty signs a request to default-origin.com with the
+			// key pair of whichever origin it was told about.
+			counterpartyResolver := newFakeDNSResolver()
+			counterpartyResolver.publishKeys("default-origin.com", tC.defaultOriginAs)
+			counterparty := newTestSignatory(counterpartyResolver, "counterparty.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+
+			requestInfo := &api.RequestInfo{}
+			SetRequestInfo(requestInfo, "https://default-origin.com/bid", []byte("body"))
+			response, _ := counterparty.SignAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionSignatureRequest{RequestInfo: requestInfo})
+			if response.SignatureOperationStatus != api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK {
+				t.Fatalf("SignAuthenticatedConnectionContext() %s: got status %v", tC.desc, response.SignatureOperationStatus)
+			}
+
+			verifyResponse, err := verifier.VerifyAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionVerificationRequest{
+				RequestInfo: []*api.RequestInfo{response.RequestInfo},
+			})
+			if err != nil {
+				t.Fatalf("VerifyAuthenticatedConnectionContext() %s: unexpected error: %v", tC.desc, err)
+			}
+			if got := verifyResponse.VerificationInfo[0].SignatureDecodeStatus[0]; got != tC.wantStatus {
+				t.Errorf("VerifyAuthenticatedConnectionContext() %s: got %v, want %v", tC.desc, got, tC.wantStatus)
+			}
+		})
+	}
+}
+
+func TestVerifyRecipient(t *testing.T) {
+	resolver := newFakeDNSResolver("verifier.com", "counterparty.com", "other.com")
+	resolver.records["_adscert.alias.com"] = []string{"v=adpf a=verifier.com"}
+	resolver.records["_adscert.unlisted-alias.com"] = []string{"v=adpf a=verifier.com"}
+	verifierResolver := &recordingDNSResolver{DNSResolver: resolver, names: map[string]bool{}}
+	verifier := newTestSignatory(verifierResolver, "verifier.com", &LocalAuthenticatedConnectionsSignatoryOptions{
+		RecipientAliases: []string{"alias.com"},
+	})
+	counterparty := newTestSignatory(resolver, "counterparty.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+
+	testCases := []struct {
+		desc string
+		url  string
+
+		wantStatus api.SignatureDecodeStatus
+	}{
+		{
+			desc:       "addressed to us",
+			url:        "https://verifier.com/bid",
+			wantStatus: api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID,
+		},
+		{
+			desc:       "addressed to us through an invoking alias",
+			url:        "https://alias.com/bid",
+			wantStatus: api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID,
+		},
+		{
+			desc:       "addressed to someone else",
+			url:        "https://other.com/bid",
+			wantStatus: api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_WRONG_RECIPIENT,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			requestInfo := &api.RequestInfo{}
+			SetRequestInfo(requestInfo, tC.url, []byte("body"))
+			response, _ := counterparty.SignAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionSignatureRequest{RequestInfo: requestInfo})
+			if response.SignatureOperationStatus != api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK {
+				t.Fatalf("SignAuthenticatedConnectionContext() %s: got status %v", tC.desc, response.SignatureOperationStatus)
+			}
+
+			verifyResponse, err := verifier.VerifyAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionVerificationRequest{
+				RequestInfo: []*api.RequestInfo{response.RequestInfo},
+			})
+			if err != nil {
+				t.Fatalf("VerifyAuthenticatedConnectionContext() %s: unexpected error: %v", tC.desc, err)
+			}
+			if got := verifyResponse.VerificationInfo[0].SignatureDecodeStatus[0]; got != tC.wantStatus {
+				t.Errorf("VerifyAuthenticatedConnectionContext() %s: got %v, want %v", tC.desc, got, tC.wantStatus)
+			}
+		})
+	}
+
+	// A "to" attribute naming a configured invoking alias rather than the
+	// callsign is accepted once the alias's policy record is known.
+	if origin, ok := verifier.recipientOrigin(context.Background(), "alias.com"); !ok || origin != "verifier.com" {
+		t.Errorf("recipientOrigin() alias: got %q, %v, want %q, true", origin, ok, "verifier.com")
+	}
+	if origin, ok := verifier.recipientOrigin(context.Background(), ""); ok {
+		t.Errorf("recipientOrigin() empty: got %q, want none", origin)
+	}
+
+	// Other "to" values are never looked up.
+	for _, to := range []string{"unlisted-alias.com", "other.com", "never-seen.com"} {
+		if origin, ok := verifier.recipientOrigin(context.Background(), to); ok {
+			t.Errorf("recipientOrigin() %s: got %q, want none", to, origin)
+		}
+		if verifierResolver.lookedUp("_adscert." + to) {
+			t.Errorf("recipientOrigin() %s: looked up the unauthenticated recipient", to)
+		}
+	}
+}
+
+func TestSigningStatus(t *testing.T) {
+	resolver := newFakeDNSResolver("verifier.com", "counterparty.com")
+	verifier := newTestSignatory(resolver, "verifier.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+	counterparty := newTestSignatory(resolver, "counterparty.com", &LocalAuthenticatedConnectionsSignatoryOptions{SigningStatus: SigningStatusTesting})
+
+	testCases := []struct {
+		desc          string
+		signingStatus string
+
+		wantSignStatus   api.SignatureOperationStatus
+		wantDecodeStatus api.SignatureDecodeStatus
+		wantFlagged      bool
+		wantStatusName   string
+	}{
+		{
+			desc:             "signatory default status",
+			wantSignStatus:   api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK,
+			wantDecodeStatus: api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID,
+			wantFlagged:      true,
+			wantStatusName:   SigningStatusTesting,
+		},
+		{
+			desc:             "ok",
+			signingStatus:    SigningStatusOK,
+			wantSignStatus:   api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK,
+			wantDecodeStatus: api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID,
+			wantStatusName:   SigningStatusOK,
+		},
+		{
+			desc:             "advisory only",
+			signingStatus:    SigningStatusAdvisoryOnly,
+			wantSignStatus:   api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK,
+			wantDecodeStatus: api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID,
+			wantFlagged:      true,
+			wantStatusName:   SigningStatusAdvisoryOnly,
+		},
+		{
+			desc:             "deactivated",
+			signingStatus:    SigningStatusDeactivated,
+			wantSignStatus:   api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK,
+			wantDecodeStatus: api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_REJECTED_SIGNING_STATUS,
+			wantStatusName:   SigningStatusDeactivated,
+		},
+		{
+			desc:           "unknown status",
+			signingStatus:  "not_a_status",
+			wantSignStatus: api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_MALFORMED_REQUEST,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			requestInfo := &api.RequestInfo{}
+			SetRequestInfo(requestInfo, "https://verifier.com/bid", []byte("body"))
+			response, _ := counterparty.SignAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionSignatureRequest{
+				RequestInfo:   requestInfo,
+				SigningStatus: tC.signingStatus,
+			})
+			if response.SignatureOperationStatus != tC.wantSignStatus {
+				t.Fatalf("SignAuthenticatedConnectionContext() %s: got status %v, want %v", tC.desc, response.SignatureOperationStatus, tC.wantSignStatus)
+			}
+			if tC.wantSignStatus != api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK {
+				return
+			}
+
+			verifyResponse, err := verifier.VerifyAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionVerificationRequest{
+				RequestInfo: []*api.RequestInfo{response.RequestInfo},
+			})
+			if err != nil {
+				t.Fatalf("VerifyAuthenticatedConnectionContext() %s: unexpected error: %v", tC.desc, err)
+			}
+			result := verifyResponse.VerificationInfo[0].SignatureResults[0]
+			if result.SignatureDecodeStatus != tC.wantDecodeStatus {
+				t.Errorf("VerifyAuthenticatedConnectionContext() %s: got %v, want %v", tC.desc, result.SignatureDecodeStatus, tC.wantDecodeStatus)
+			}
+			if result.Flagged != tC.wantFlagged {
+				t.Errorf("VerifyAuthenticatedConnectionContext() %s: got flagged %v, want %v", tC.desc, result.Flagged, tC.wantFlagged)
+			}
+			if result.SigningStatusName != tC.wantStatusName {
+				t.Errorf("VerifyAuthenticatedConnectionContext() %s: got status name %q, want %q", tC.desc, result.SigningStatusName, tC.wantStatusName)
+			}
+		})
+	}
+}
+
+func TestDeactivation(t *testing.T) {
+	resolver := newFakeDNSResolver("verifier.com", "counterparty.com")
+	verifier := newTestSignatory(resolver, "verifier.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+	counterparty := newTestSignatory(resolver, "counterparty.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+	signsWhenDeactivated := newTestSignatory(resolver, "counterparty.com", &LocalAuthenticatedConnectionsSignatoryOptions{SignWhenDeactivated: true})
+
+	testCases := []struct {
+		desc                 string
+		signer               *LocalAuthenticatedConnectionsSignatory
+		signerDeactivation   *api.DeactivationSettings
+		verifierDeactivation *api.DeactivationSettings
+
+		wantSignStatus   api.SignatureOperationStatus
+		wantVerifyStatus api.VerificationOperationStatus
+		wantDecodeStatus api.SignatureDecodeStatus
+	}{
+		{
+			desc:             "nothing deactivated",
+			signer:           counterparty,
+			wantSignStatus:   api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK,
+			wantVerifyStatus: api.VerificationOperationStatus_VERIFICATION_OPERATION_STATUS_OK,
+			wantDecodeStatus: api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID,
+		},
+		{
+			desc:               "signer deactivated",
+			signer:             counterparty,
+			signerDeactivation: &api.DeactivationSettings{All: true},
+			wantSignStatus:     api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_SIGNATORY_DEACTIVATED,
+		},
+		{
+			desc:               "signer origin deactivated",
+			signer:             counterparty,
+			signerDeactivation: &api.DeactivationSettings{Origins: []string{"counterparty.com"}},
+			wantSignStatus:     api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_SIGNATORY_DEACTIVATED,
+		},
+		{
+			desc:               "recipient deactivated by signer",
+			signer:             counterparty,
+			signerDeactivation: &api.DeactivationSettings{Counterparties: []string{"verifier.com"}},
+			wantSignStatus:     api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_SIGNATORY_DEACTIVATED,
+		},
+		{
+			desc:               "other counterparty deactivated by signer",
+			signer:             counterparty,
+			signerDeactivation: &api.DeactivationSettings{Counterparties: []string{"other.com"}},
+			wantSignStatus:     api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK,
+			wantVerifyStatus:   api.VerificationOperationStatus_VERIFICATION_OPERATION_STATUS_OK,
+			wantDecodeStatus:   api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID,
+		},
+		{
+			desc:               "signer deactivated signing with deactivated status",
+			signer:             signsWhenDeactivated,
+			signerDeactivation: &api.DeactivationSettings{All: true},
+			wantSignStatus:     api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK,
+			wantVerifyStatus:   api.VerificationOperationStatus_VERIFICATION_OPERATION_STATUS_OK,
+			wantDecodeStatus:   api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_REJECTED_SIGNING_STATUS,
+		},
+		{
+			desc:                 "verifier deactivated",
+			signer:               counterparty,
+			verifierDeactivation: &api.DeactivationSettings{All: true},
+			wantSignStatus:       api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK,
+			wantVerifyStatus:     api.VerificationOperationStatus_VERIFICATION_OPERATION_STATUS_SIGNATORY_DEACTIVATED,
+		},
+		{
+			desc:                 "signer deactivated by verifier",
+			signer:               counterparty,
+			verifierDeactivation: &api.DeactivationSettings{Counterparties: []string{"counterparty.com"}},
+			wantSignStatus:       api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK,
+			wantVerifyStatus:     api.VerificationOperationStatus_VERIFICATION_OPERATION_STATUS_OK,
+			wantDecodeStatus:     api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_DEACTIVATED,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			tC.signer.SetDeactivation(tC.signerDeactivation)
+			verifier.SetDeactivation(tC.verifierDeactivation)
+
+			requestInfo := &api.RequestInfo{}
+			SetRequestInfo(requestInfo, "https://verifier.com/bid", []byte("body"))
+			response, err := tC.signer.SignAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionSignatureRequest{RequestInfo: requestInfo})
+			if err != nil {
+				t.Fatalf("SignAuthenticatedConnectionContext() %s: unexpected error: %v", tC.desc, err)
+			}
+			if response.SignatureOperationStatus != tC.wantSignStatus {
+				t.Fatalf("SignAuthenticatedConnectionContext() %s: got status %v, want %v", tC.desc, response.SignatureOperationStatus, tC.wantSignStatus)
+			}
+			if tC.wantSignStatus != api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK {
+				return
+			}
+
+			verifyResponse, err := verifier.VerifyAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionVerificationRequest{
+				RequestInfo: []*api.RequestInfo{response.RequestInfo},
+			})
+			if err != nil {
+				t.Fatalf("VerifyAuthenticatedConnectionContext() %s: unexpected error: %v", tC.desc, err)
+			}
+			if verifyResponse.VerificationOperationStatus != tC.wantVerifyStatus {
+				t.Fatalf("VerifyAuthenticatedConnectionContext() %s: got status %v, want %v", tC.desc, verifyResponse.VerificationOperationStatus, tC.wantVerifyStatus)
+			}
+			if tC.wantVerifyStatus != api.VerificationOperationStatus_VERIFICATION_OPERATION_STATUS_OK {
+				return
+			}
+			if got := verifyResponse.VerificationInfo[0].SignatureResults[0].SignatureDecodeStatus; got != tC.wantDecodeStatus {
+				t.Errorf("VerifyAuthenticatedConnectionContext() %s: got %v, want %v", tC.desc, got, tC.wantDecodeStatus)
+			}
+		})
+	}
+
+	verifier.SetDeactivation(&api.DeactivationSettings{Origins: []string{"verifier.com"}})
+	if got := verifier.GetDeactivation(); len(got.Origins) != 1 || got.Origins[0] != "verifier.com" {
+		t.Errorf("GetDeactivation(): got %v, want origins [verifier.com]", got)
+	}
+}
+
+func TestSignBatch(t *testing.T) {
+	resolver := newFakeDNSResolver("verifier.com", "second-verifier.com", "counterparty.com")
+	verifier := newTestSignatory(resolver, "verifier.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+	counterparty := newTestSignatory(resolver, "counterparty.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+
+	urls := []string{
+		"https://verifier.com/bid",
+		"https://second-verifier.com/bid",
+		"https://unknown.com/bid",
+		"https://verifier.com/other-bid",
+	}
+	wantStatuses := []api.SignatureOperationStatus{
+		api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK,
+		api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK,
+		api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_SIGNATORY_INTERNAL_ERROR,
+		api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK,
+	}
+
+	request := &api.AuthenticatedConnectionBatchSignatureRequest{}
+	for _, url := range urls {
+		requestInfo := &api.RequestInfo{}
+		SetRequestInfo(requestInfo, url, []byte("body"))
+		request.RequestInfo = append(request.RequestInfo, requestInfo)
+	}
+
+	response, err := counterparty.SignAuthenticatedConnectionBatchContext(context.Background(), request)
+	if err != nil {
+		t.Fatalf("SignAuthenticatedConnectionBatchContext() unexpected error: %v", err)
+	}
+	if len(response.Responses) != len(urls) {
+		t.Fatalf("SignAuthenticatedConnectionBatchContext() responses: got %d, want %d", len(response.Responses), len(urls))
+	}
+
+	timestamps := map[string]bool{}
+	nonces := map[string]bool{}
+	for i, itemResponse := range response.Responses {
+		if itemResponse.SignatureOperationStatus != wantStatuses[i] {
+			t.Errorf("SignAuthenticatedConnectionBatchContext() %s: got status %v, want %v", urls[i], itemResponse.SignatureOperationStatus, wantStatuses[i])
+			continue
+		}
+		if wantStatuses[i] != api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK {
+			continue
+		}
+		acs, err := formats.DecodeAuthenticatedConnectionSignature(itemResponse.RequestInfo.SignatureInfo[0].SignatureMessage)
+		if err != nil {
+			t.Fatalf("DecodeAuthenticatedConnectionSignature() %s: unexpected error: %v", urls[i], err)
+		}
+		timestamps[acs.GetAttributeTimestamp()] = true
+		nonces[acs.GetAttributeNonce()] = true
+
+		if urls[i] == "https://second-verifier.com/bid" {
+			continue
+		}
+		verifyResponse, err := verifier.VerifyAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionVerificationRequest{
+			RequestInfo: []*api.RequestInfo{itemResponse.RequestInfo},
+		})
+		if err != nil {
+			t.Fatalf("VerifyAuthenticatedConnectionContext() %s: unexpected error: %v", urls[i], err)
+		}
+		want := api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID
+		if got := verifyResponse.VerificationInfo[0].SignatureDecodeStatus[0]; got != want {
+			t.Errorf("VerifyAuthenticatedConnectionContext() %s: got %v, want %v", urls[i], got, want)
+		}
+	}
+	if len(timestamps) != 1 {
+		t.Errorf("SignAuthenticatedConnectionBatchContext() timestamps: got %d distinct, want 1", len(timestamps))
+	}
+	if len(nonces) != 3 {
+		t.Errorf("SignAuthenticatedConnectionBatchContext() nonces: got %d distinct, want 3", len(nonces))
+	}
+}
+
+type recordingAuditSink struct {
+	mutex  sync.Mutex
+	events []*AuditEvent
+}
+
+func (s *recordingAuditSink) Audit(event *AuditEvent) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.events = append(s.events, event)
+}
+
+func TestAuditSink(t *testing.T) {
+	resolver := newFakeDNSResolver("verifier.com", "counterparty.com")
+	verifierAudit, counterpartyAudit := &recordingAuditSink{}, &recordingAuditSink{}
+	verifier := newTestSignatory(resolver, "verifier.com", &LocalAuthenticatedConnectionsSignatoryOptions{AuditSink: verifierAudit})
+	counterparty := newTestSignatory(resolver, "counterparty.com", &LocalAuthenticatedConnectionsSignatoryOptions{AuditSink: counterpartyAudit})
+
+	requestInfo := &api.RequestInfo{}
+	SetRequestInfo(requestInfo, "https://verifier.com/bid", []byte("body"))
+	response, _ := counterparty.SignAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionSignatureRequest{RequestInfo: requestInfo})
+	verifier.VerifyAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionVerificationRequest{
+		RequestInfo: []*api.RequestInfo{response.RequestInfo},
+	})
+
+	if len(counterpartyAudit.events) != 1 || len(verifierAudit.events) != 1 {
+		t.Fatalf("Audit(): got %d sign and %d verify events, want 1 each", len(counterpartyAudit.events), len(verifierAudit.events))
+	}
+	signEvent, verifyEvent := counterpartyAudit.events[0], verifierAudit.events[0]
+	testCases := []struct {
+		desc string
+		got  string
+		want string
+	}{
+		{"sign operation", signEvent.Operation, AuditOperationSign},
+		{"sign counterparty", signEvent.Counterparty, "verifier.com"},
+		{"sign outcome", signEvent.Outcome, api.SignatureOperationStatus_SIGNATURE_OPERATION_STATUS_OK.String()},
+		{"verify operation", verifyEvent.Operation, AuditOperationVerify},
+		{"verify counterparty", verifyEvent.Counterparty, "counterparty.com"},
+		{"verify outcome", verifyEvent.Outcome, api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID.String()},
+		{"verify signature", verifyEvent.Signature, signEvent.Signature},
+		{"verify body hash", verifyEvent.BodyHash, base64.StdEncoding.EncodeToString(requestInfo.BodyHash)},
+		{"verify from key", verifyEvent.FromKey, signEvent.FromKey},
+	}
+	for _, tC := range testCases {
+		if tC.got != tC.want {
+			t.Errorf("Audit() %s: got %q, want %q", tC.desc, tC.got, tC.want)
+		}
+	}
+}
+
+func TestAuditSink_SamplingAndRedaction(t *testing.T) {
+	resolver := newFakeDNSResolver("verifier.com", "counterparty.com")
+	verifierAudit, counterpartyAudit := &recordingAuditSink{}, &recordingAuditSink{}
+	verifier := newTestSignatory(resolver, "verifier.com", &LocalAuthenticatedConnectionsSignatoryOptions{
+		AuditSink:    verifierAudit,
+		AuditSampler: NewAuditSampler([]AuditSamplingRule{{Outcome: AuditOutcomeSuccess, Rate: 0}}, 1),
+	})
+	counterparty := newTestSignatory(resolver, "counterparty.com", &LocalAuthenticatedConnectionsSignatoryOptions{
+		AuditSink:         counterpartyAudit,
+		AuditRedactionKey: []byte("redaction key"),
+	})
+
+	requestInfo := &api.RequestInfo{}
+	SetRequestInfo(requestInfo, "https://verifier.com/bid", []byte("body"))
+	response, _ := counterparty.SignAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionSignatureRequest{RequestInfo: requestInfo})
+	verifier.VerifyAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionVerificationRequest{
+		RequestInfo: []*api.RequestInfo{response.RequestInfo},
+	})
+
+	if len(verifierAudit.events) != 0 {
+		t.Errorf("Audit(): got %d verify events for a sampled out success, want 0", len(verifierAudit.events))
+	}
+	if len(counterpartyAudit.events) != 1 {
+		t.Fatalf("Audit(): got %d sign events, want 1", len(counterpartyAudit.events))
+	}
+	signEvent := counterpartyAudit.events[0]
+	signature := response.RequestInfo.SignatureInfo[0].SignatureMessage
+	if want := redactSignature([]byte("redaction key"), signature); signEvent.Signature != want {
+		t.Errorf("Audit() redacted signature: got %q, want %q", signEvent.Signature, want)
+	}
+	if want := base64.StdEncoding.EncodeToString(requestInfo.BodyHash); signEvent.BodyHash != want {
+		t.Errorf("Audit() body hash: got %q, want %q", signEvent.BodyHash, want)
+	}
+}
+
+func TestVerifyMetrics(t *testing.T) {
+	resolver := newFakeDNSResolver("verifier.com", "counterparty.com")
+	verifier := newTestSignatory(resolver, "verifier.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+	counterparty := newTestSignatory(resolver, "counterparty.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+
+	signedRequest := &api.RequestInfo{}
+	SetRequestInfo(signedRequest, "https://verifier.com/bid", []byte("body"))
+	response, _ := counterparty.SignAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionSignatureRequest{RequestInfo: signedRequest})
+	signedRequest = response.RequestInfo
+	urlMismatch := &api.RequestInfo{}
+	SetRequestInfo(urlMismatch, "https://verifier.com/other", []byte("body"))
+	urlMismatch.SignatureInfo = signedRequest.SignatureInfo
+	unsignedRequest := &api.RequestInfo{}
+	SetRequestInfo(unsignedRequest, "https://verifier.com/bid", []byte("body"))
+
+	bodyAndURLValid := api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID.String()
+	bodyValid := api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_VALID.String()
+	counters := []struct {
+		desc      string
+		collector prometheus.Collector
+		wantDelta float64
+	}{
+		{"body valid", metrics.VerifyOutcomeCounter.WithLabelValues(string(metrics.VerifyOutcomeTypeBody), "true"), 2},
+		{"url valid", metrics.VerifyOutcomeCounter.WithLabelValues(string(metrics.VerifyOutcomeTypeUrl), "true"), 1},
+		{"url invalid", metrics.VerifyOutcomeCounter.WithLabelValues(string(metrics.VerifyOutcomeTypeUrl), "false"), 1},
+		{"body and url valid status", metrics.VerifyDecodeStatusCounter.WithLabelValues(bodyAndURLValid, "", ""), 1},
+		{"body valid status", metrics.VerifyDecodeStatusCounter.WithLabelValues(bodyValid, "", ""), 1},
+		{"unsigned requests", metrics.VerifyUnsignedCounter, 1},
+	}
+	before := make([]float64, len(counters))
+	for i, c := range counters {
+		before[i] = testutil.ToFloat64(c.collector)
+	}
+
+	verifier.VerifyAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionVerificationRequest{
+		RequestInfo: []*api.RequestInfo{signedRequest, urlMismatch, unsignedRequest},
+	})
+
+	for i, c := range counters {
+		if got := testutil.ToFloat64(c.collector) - before[i]; got != c.wantDelta {
+			t.Errorf("VerifyAuthenticatedConnection() %s metric: got increase of %v, want %v", c.desc, got, c.wantDelta)
+		}
+	}
+}
+
+func TestVerifyMetrics_CounterpartyLabels(t *testing.T) {
+	metrics.SetLabelOptions(metrics.LabelOptions{Counterparty: true, KeyAlias: true, MaxCounterparties: -1})
+	defer metrics.SetLabelOptions(metrics.LabelOptions{})
+
+	resolver := newFakeDNSResolver("verifier.com", "counterparty.com")
+	verifier := newTestSignatory(resolver, "verifier.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+	counterparty := newTestSignatory(resolver, "counterparty.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+
+	signedRequest := &api.RequestInfo{}
+	SetRequestInfo(signedRequest, "https://verifier.com/bid", []byte("body"))
+	response, _ := counterparty.SignAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionSignatureRequest{RequestInfo: signedRequest})
+	signedRequest = response.RequestInfo
+	signatureInfo := signedRequest.SignatureInfo[0]
+
+	// Signatures claiming other signers or keys, or failing to verify, must
+	// not get their claimed attributes as label values.
+	forge := func(old string, new string) *api.RequestInfo {
+		forged := proto.Clone(signedRequest).(*api.RequestInfo)
+		forged.SignatureInfo[0].SignatureMessage = strings.Replace(signatureInfo.SignatureMessage, old, new, 1)
+		return forged
+	}
+	forgedSigner := forge("from=counterparty.com", "from=forged.com")
+	forgedKey := forge("from_key="+signatureInfo.FromKey, "from_key=forged")
+	bodyMismatch := proto.Clone(signedRequest).(*api.RequestInfo)
+	SetRequestInfo(bodyMismatch, "https://verifier.com/bid", []byte("other body"))
+
+	counters := []struct {
+		desc      string
+		collector prometheus.Collector
+		wantDelta float64
+	}{
+		{"verified", metrics.VerifyCounter.WithLabelValues("", "counterparty.com", signatureInfo.FromKey), 1},
+		{"unknown signer", metrics.VerifyCounter.WithLabelValues(adscerterrors.ErrVerifyCounterpartyLookup.Code, "", ""), 1},
+		{"unpublished key", metrics.VerifyCounter.WithLabelValues(adscerterrors.ErrVerifyKeyNotPublished.Code, "", ""), 1},
+		{"invalid signature", metrics.VerifyCounter.WithLabelValues(adscerterrors.ErrVerifyInvalidSignature.Code, "", ""), 1},
+		{"verified status", metrics.VerifyDecodeStatusCounter.WithLabelValues(api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID.String(), "counterparty.com", signatureInfo.FromKey), 1},
+		{"unknown signer status", metrics.VerifyDecodeStatusCounter.WithLabelValues(api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_COUNTERPARTY_LOOKUP_ERROR.String(), "", ""), 1},
+		{"unpublished key status", metrics.VerifyDecodeStatusCounter.WithLabelValues(api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_KEY_NOT_PUBLISHED.String(), "", ""), 1},
+		{"invalid signature status", metrics.VerifyDecodeStatusCounter.WithLabelValues(api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_INVALID_SIGNATURE.String(), "", ""), 1},
+	}
+	before := make([]float64, len(counters))
+	for i, c := range counters {
+		before[i] = testutil.ToFloat64(c.collector)
+	}
+
+	verifier.VerifyAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionVerificationRequest{
+		RequestInfo: []*api.RequestInfo{signedRequest, forgedSigner, forgedKey, bodyMismatch},
+	})
+
+	for i, c := range counters {
+		if got := testutil.ToFloat64(c.collector) - before[i]; got != c.wantDelta {
+			t.Errorf("VerifyAuthenticatedConnection() %s metric: got increase of %v, want %v", c.desc, got, c.wantDelta)
+		}
+	}
+}
+
+func TestVerifyDNSSECFailure(t *testing.T) {
+	signerResolver := newFakeDNSResolver("verifier.com", "counterparty.com")
+	counterparty := newTestSignatory(signerResolver, "counterparty.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+
+	requestInfo := &api.RequestInfo{}
+	SetRequestInfo(requestInfo, "https://verifier.com/bid", []byte("body"))
+	response, _ := counterparty.SignAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionSignatureRequest{RequestInfo: requestInfo})
+
+	testCases := []struct {
+		desc     string
+		failures map[string]error
+		want     api.SignatureDecodeStatus
+	}{
+		{
+			desc: "validated",
+			want: api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID,
+		},
+		{
+			// The keys record itself validates, but the policy record
+			// doesn't, so the counterparty's records aren't trusted.
+			desc: "policy record fails validation",
+			failures: map[string]error{
+				"_adscert.counterparty.com": &discovery.DNSSECValidationError{Name: "_adscert.counterparty.com", Err: discovery.ErrDNSSECNoSignature},
+			},
+			want: api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_COUNTERPARTY_LOOKUP_ERROR,
+		},
+		{
+			desc: "keys record fails validation",
+			failures: map[string]error{
+				"_delivery._adscert.counterparty.com": &discovery.DNSSECValidationError{Name: "_delivery._adscert.counterparty.com", Err: discovery.ErrDNSSECNoSignature},
+			},
+			want: api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_COUNTERPARTY_LOOKUP_ERROR,
+		},
+	}
+	for _, tC := range testCases {
+		resolver := newFakeDNSResolver("verifier.com", "counterparty.com")
+		resolver.failures = tC.failures
+		verifier := newTestSignatory(resolver, "verifier.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+
+		verifyResponse, err := verifier.VerifyAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionVerificationRequest{
+			RequestInfo: []*api.RequestInfo{response.RequestInfo},
+		})
+		if err != nil {
+			t.Fatalf("VerifyAuthenticatedConnection() %s: unexpected error: %v", tC.desc, err)
+		}
+		if got := verifyResponse.VerificationInfo[0].SignatureDecodeStatus[0]; got != tC.want {
+			t.Errorf("VerifyAuthenticatedConnection() %s: got %v, want %v", tC.desc, got, tC.want)
+		}
+	}
+}
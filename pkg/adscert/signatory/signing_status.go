@@ -0,0 +1,53 @@
+package signatory
+
+import "github.com/IABTechLab/adscert/internal/formats"
+
+// Names of the signing statuses a signer declares in a signature's status
+// attribute which are most relevant to verifiers.  Other statuses are named as
+// in the ads.cert specification, in lower case with underscores.
+const (
+	SigningStatusOK           = "ok"
+	SigningStatusTesting      = "testing"
+	SigningStatusAdvisoryOnly = "advisory_only"
+	SigningStatusDeactivated  = "deactivated"
+	SigningStatusSuppressed   = "suppressed"
+)
+
+// SigningStatusAction is what verification does with a signature given the
+// status its signer declared.
+type SigningStatusAction int
+
+const (
+	// SigningStatusAccept verifies the signature normally.
+	SigningStatusAccept SigningStatusAction = iota
+
+	// SigningStatusFlag verifies the signature normally, but flags valid
+	// results so that callers can log them without acting on them.
+	SigningStatusFlag
+
+	// SigningStatusReject rejects the signature regardless of its validity.
+	SigningStatusReject
+)
+
+// SigningStatusPolicy decides how verification treats a signature given the
+// name of its declared signing status.
+type SigningStatusPolicy func(signingStatus string) SigningStatusAction
+
+// DefaultSigningStatusPolicy flags testing and advisory-only signatures,
+// rejects deactivated ones and accepts any other status.
+func DefaultSigningStatusPolicy(signingStatus string) SigningStatusAction {
+	switch signingStatus {
+	case SigningStatusTesting, SigningStatusAdvisoryOnly:
+		return SigningStatusFlag
+	case SigningStatusDeactivated:
+		return SigningStatusReject
+	}
+	return SigningStatusAccept
+}
+
+// parseSigningStatus returns the status with the given name.  The unspecified
+// status can't be declared in a signature.
+func parseSigningStatus(name string) (formats.AuthenticatedConnectionProtocolStatus, bool) {
+	status, ok := formats.StatusFromName(name)
+	return status, ok && status != formats.StatusUnspecified
+}
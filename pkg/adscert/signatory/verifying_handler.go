@@ -0,0 +1,247 @@
+package signatory
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/IABTechLab/adscert/pkg/adscert/api"
+	"github.com/IABTechLab/adscert/pkg/adscert/logger"
+)
+
+// VerificationMode selects what a verifying handler does with the requests it
+// receives.
+type VerificationMode int
+
+const (
+	// VerificationModeOff passes requests through without verifying them.
+	VerificationModeOff VerificationMode = iota
+
+	// VerificationModeObserve verifies requests and records the result in the
+	// request context, but passes every request through.
+	VerificationModeObserve
+
+	// VerificationModeEnforce verifies requests and rejects those without a
+	// signature authenticating their body.  Requests are passed through while
+	// the signatory is deactivated, since it then verifies nothing.
+	VerificationModeEnforce
+)
+
+// URLReconstructor returns the URL a request was sent to, as its signer saw it.
+type URLReconstructor func(req *http.Request) string
+
+// ForwardedURLReconstructor returns a URLReconstructor which takes the scheme
+// and host from the X-Forwarded-Proto and X-Forwarded-Host headers when the
+// request comes directly from one of the trusted proxies, and otherwise from
+// the connection and Host header.  Forwarding headers from other peers are
+// ignored, since any client could set them.  When proxies append to the
+// headers, the value appended by the trusted proxy, the last one, is used:
+// earlier values came from further upstream, which could be the client.
+func ForwardedURLReconstructor(trustedProxies ...*net.IPNet) URLReconstructor {
+	return func(req *http.Request) string {
+		reconstructedURL := *req.URL
+		reconstructedURL.Scheme = "http"
+		if req.TLS != nil {
+			reconstructedURL.Scheme = "https"
+		}
+		reconstructedURL.Host = req.Host
+
+		if isTrustedProxy(req.RemoteAddr, trustedProxies) {
+			if proto := lastForwardedValue(req.Header.Values("X-Forwarded-Proto")); proto != "" {
+				reconstructedURL.Scheme = proto
+			}
+			if host := lastForwardedValue(req.Header.Values("X-Forwarded-Host")); host != "" {
+				reconstructedURL.Host = host
+			}
+		}
+		return reconstructedURL.String()
+	}
+}
+
+func isTrustedProxy(remoteAddr string, trustedProxies []*net.IPNet) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, trustedProxy := range trustedProxies {
+		if trustedProxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// lastForwardedValue returns the value appended by the proxy nearest us when
+// several proxies appended to a forwarding header, across all of its lines.
+func lastForwardedValue(headers []string) string {
+	if len(headers) == 0 {
+		return ""
+	}
+	values := strings.Split(headers[len(headers)-1], ",")
+	return strings.TrimSpace(values[len(values)-1])
+}
+
+// VerificationResult is the outcome of verifying a received request.
+type VerificationResult struct {
+	// URL is the reconstructed URL the signatures were verified against.
+	URL string
+
+	// Response is the signatory's response, nil if verification failed
+	// outright, in which case Err explains why.
+	Response *api.AuthenticatedConnectionVerificationResponse
+	Err      error
+
+	// OperationStatus is the signatory's verification operation status, which
+	// reports in particular whether the signatory is deactivated.
+	OperationStatus api.VerificationOperationStatus
+
+	// BodyValid and URLValid report whether any signature authenticates the
+	// request body, and the body along with the URL.
+	BodyValid bool
+	URLValid  bool
+}
+
+type verificationResultKey struct{}
+
+// VerificationResultFromContext returns the result of verifying the request
+// whose context this is, if it passed through a verifying handler.
+func VerificationResultFromContext(ctx context.Context) (*VerificationResult, bool) {
+	result, ok := ctx.Value(verificationResultKey{}).(*VerificationResult)
+	return result, ok
+}
+
+// VerifyingHandlerOptions holds the optional settings for a verifying handler.
+type VerifyingHandlerOptions struct {
+	// Mode defaults to VerificationModeOff.
+	Mode VerificationMode
+
+	// ReconstructURL defaults to ForwardedURLReconstructor with no trusted
+	// proxies.
+	ReconstructURL URLReconstructor
+
+	// Rejected serves requests rejected in VerificationModeEnforce.  Defaults
+	// to responding 401 Unauthorized.
+	Rejected http.Handler
+
+	// MaxBodyBytes bounds the size of request bodies read for verification.
+	// Larger requests are answered 413 Request Entity Too Large.  Zero
+	// defaults to DefaultMaxBodyBytes; a negative value leaves bodies
+	// unbounded.
+	MaxBodyBytes int64
+}
+
+// DefaultMaxBodyBytes is the largest request body a verifying handler reads
+// unless VerifyingHandlerOptions.MaxBodyBytes says otherwise.
+const DefaultMaxBodyBytes = 10 << 20
+
+// NewVerifyingHandler returns an http.Handler which verifies the ads.cert
+// signatures of each request before passing it to next.  The request body is
+// read in full for verification and restored for next, and the outcome is
+// available from the request context through VerificationResultFromContext.
+// Nil options select the defaults.
+func NewVerifyingHandler(signatory ContextAuthenticatedConnectionsSignatory, next http.Handler, options *VerifyingHandlerOptions) http.Handler {
+	if options == nil {
+		options = &VerifyingHandlerOptions{}
+	}
+	h := &verifyingHandler{
+		signatory:      signatory,
+		next:           next,
+		mode:           options.Mode,
+		reconstructURL: options.ReconstructURL,
+		rejected:       options.Rejected,
+		maxBodyBytes:   options.MaxBodyBytes,
+	}
+	if h.maxBodyBytes == 0 {
+		h.maxBodyBytes = DefaultMaxBodyBytes
+	}
+	if h.reconstructURL == nil {
+		h.reconstructURL = ForwardedURLReconstructor()
+	}
+	if h.rejected == nil {
+		h.rejected = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			http.Error(w, "ads.cert signature verification failed", http.StatusUnauthorized)
+		})
+	}
+	return h
+}
+
+type verifyingHandler struct {
+	signatory      ContextAuthenticatedConnectionsSignatory
+	next           http.Handler
+	mode           VerificationMode
+	reconstructURL URLReconstructor
+	rejected       http.Handler
+	maxBodyBytes   int64
+}
+
+func (h *verifyingHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h.mode == VerificationModeOff {
+		h.next.ServeHTTP(w, req)
+		return
+	}
+
+	reqBody := req.Body
+	if h.maxBodyBytes > 0 {
+		reqBody = http.MaxBytesReader(w, req.Body, h.maxBodyBytes)
+	}
+	body, err := io.ReadAll(reqBody)
+	reqBody.Close()
+	if err != nil {
+		if h.maxBodyBytes > 0 && int64(len(body)) >= h.maxBodyBytes {
+			http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		} else {
+			http.Error(w, "unable to read request body", http.StatusBadRequest)
+		}
+		return
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	result := h.verify(req, body)
+	req = req.WithContext(context.WithValue(req.Context(), verificationResultKey{}, result))
+
+	deactivated := result.OperationStatus == api.VerificationOperationStatus_VERIFICATION_OPERATION_STATUS_SIGNATORY_DEACTIVATED
+	if h.mode == VerificationModeEnforce && !result.BodyValid && !deactivated {
+		h.rejected.ServeHTTP(w, req)
+		return
+	}
+	h.next.ServeHTTP(w, req)
+}
+
+func (h *verifyingHandler) verify(req *http.Request, body []byte) *VerificationResult {
+	result := &VerificationResult{URL: h.reconstructURL(req)}
+
+	requestInfo := &api.RequestInfo{}
+	if result.Err = SetRequestInfo(requestInfo, result.URL, body); result.Err != nil {
+		return result
+	}
+	SetRequestSignatures(requestInfo, req.Header.Values(AuthHeader))
+
+	result.Response, result.Err = h.signatory.VerifyAuthenticatedConnectionContext(req.Context(), &api.AuthenticatedConnectionVerificationRequest{
+		RequestInfo: []*api.RequestInfo{requestInfo},
+	})
+	if result.Err != nil {
+		logger.Errorf("unable to verify request to %s: %v", result.URL, result.Err)
+		return result
+	}
+	result.OperationStatus = result.Response.GetVerificationOperationStatus()
+
+	for _, verificationInfo := range result.Response.GetVerificationInfo() {
+		for _, decodeStatus := range verificationInfo.SignatureDecodeStatus {
+			switch decodeStatus {
+			case api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_AND_URL_VALID:
+				result.BodyValid = true
+				result.URLValid = true
+			case api.SignatureDecodeStatus_SIGNATURE_DECODE_STATUS_BODY_VALID:
+				result.BodyValid = true
+			}
+		}
+	}
+	return result
+}
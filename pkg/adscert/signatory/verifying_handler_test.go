@@ -0,0 +1,213 @@
+package signatory
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IABTechLab/adscert/pkg/adscert/api"
+)
+
+func TestVerifyingHandler(t *testing.T) {
+	resolver := newFakeDNSResolver("verifier.com", "counterparty.com")
+	verifier := newTestSignatory(resolver, "verifier.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+	counterparty := newTestSignatory(resolver, "counterparty.com", &LocalAuthenticatedConnectionsSignatoryOptions{})
+	_, trustedProxies, _ := net.ParseCIDR("192.0.2.0/24")
+
+	testCases := []struct {
+		desc           string
+		mode           VerificationMode
+		unsigned       bool
+		viaProxy       bool
+		forwardedHost  []string // X-Forwarded-Host lines, defaulting to verifier.com
+		reconstructURL URLReconstructor
+		maxBodyBytes   int64
+		deactivated    bool
+
+		wantStatusCode int
+		wantResult     bool
+		wantValid      bool
+	}{
+		{
+			desc:           "off",
+			mode:           VerificationModeOff,
+			wantStatusCode: http.StatusNoContent,
+		},
+		{
+			desc:           "observe signed",
+			mode:           VerificationModeObserve,
+			wantStatusCode: http.StatusNoContent,
+			wantResult:     true,
+			wantValid:      true,
+		},
+		{
+			desc:           "observe unsigned",
+			mode:           VerificationModeObserve,
+			unsigned:       true,
+			wantStatusCode: http.StatusNoContent,
+			wantResult:     true,
+		},
+		{
+			desc:           "enforce signed",
+			mode:           VerificationModeEnforce,
+			wantStatusCode: http.StatusNoContent,
+			wantResult:     true,
+			wantValid:      true,
+		},
+		{
+			desc:           "enforce unsigned",
+			mode:           VerificationModeEnforce,
+			unsigned:       true,
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:           "enforce via trusted proxy",
+			mode:           VerificationModeEnforce,
+			viaProxy:       true,
+			reconstructURL: ForwardedURLReconstructor(trustedProxies),
+			wantStatusCode: http.StatusNoContent,
+			wantResult:     true,
+			wantValid:      true,
+		},
+		{
+			desc:           "enforce via trusted proxy behind a spoofing client",
+			mode:           VerificationModeEnforce,
+			viaProxy:       true,
+			forwardedHost:  []string{"spoofed.com, verifier.com"},
+			reconstructURL: ForwardedURLReconstructor(trustedProxies),
+			wantStatusCode: http.StatusNoContent,
+			wantResult:     true,
+			wantValid:      true,
+		},
+		{
+			desc:           "enforce via trusted proxy appending a header line",
+			mode:           VerificationModeEnforce,
+			viaProxy:       true,
+			forwardedHost:  []string{"spoofed.com", "verifier.com"},
+			reconstructURL: ForwardedURLReconstructor(trustedProxies),
+			wantStatusCode: http.StatusNoContent,
+			wantResult:     true,
+			wantValid:      true,
+		},
+		{
+			desc:           "enforce via trusted proxy forwarding another host",
+			mode:           VerificationModeEnforce,
+			viaProxy:       true,
+			forwardedHost:  []string{"verifier.com, other.com"},
+			reconstructURL: ForwardedURLReconstructor(trustedProxies),
+			wantStatusCode: http.StatusUnauthorized,
+		},
+		{
+			desc:           "enforce body within limit",
+			mode:           VerificationModeEnforce,
+			maxBodyBytes:   int64(len("bid request")),
+			wantStatusCode: http.StatusNoContent,
+			wantResult:     true,
+			wantValid:      true,
+		},
+		{
+			desc:           "observe body over limit",
+			mode:           VerificationModeObserve,
+			maxBodyBytes:   int64(len("bid request")) - 1,
+			wantStatusCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			desc:           "enforce unbounded body",
+			mode:           VerificationModeEnforce,
+			maxBodyBytes:   -1,
+			wantStatusCode: http.StatusNoContent,
+			wantResult:     true,
+			wantValid:      true,
+		},
+		{
+			desc:           "enforce while deactivated",
+			mode:           VerificationModeEnforce,
+			unsigned:       true,
+			deactivated:    true,
+			wantStatusCode: http.StatusNoContent,
+			wantResult:     true,
+		},
+		{
+			desc:           "enforce via untrusted proxy",
+			mode:           VerificationModeEnforce,
+			viaProxy:       true,
+			wantStatusCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tC := range testCases {
+		t.Run(tC.desc, func(t *testing.T) {
+			const signedURL = "https://verifier.com/bid?id=1"
+			body := []byte("bid request")
+			verifier.SetDeactivation(&api.DeactivationSettings{All: tC.deactivated})
+
+			var gotBody []byte
+			var gotResult *VerificationResult
+			handler := NewVerifyingHandler(verifier, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				gotBody, _ = io.ReadAll(req.Body)
+				gotResult, _ = VerificationResultFromContext(req.Context())
+				w.WriteHeader(http.StatusNoContent)
+			}), &VerifyingHandlerOptions{Mode: tC.mode, ReconstructURL: tC.reconstructURL, MaxBodyBytes: tC.maxBodyBytes})
+
+			receivedURL := signedURL
+			if tC.viaProxy {
+				receivedURL = "http://backend:8080/bid?id=1"
+			}
+			req := httptest.NewRequest(http.MethodPost, receivedURL, bytes.NewReader(body))
+			if tC.viaProxy {
+				req.Header.Set("X-Forwarded-Proto", "https")
+				forwardedHost := tC.forwardedHost
+				if forwardedHost == nil {
+					forwardedHost = []string{"verifier.com"}
+				}
+				for _, host := range forwardedHost {
+					req.Header.Add("X-Forwarded-Host", host)
+				}
+			}
+			if !tC.unsigned {
+				requestInfo := &api.RequestInfo{}
+				SetRequestInfo(requestInfo, signedURL, body)
+				response, _ := counterparty.SignAuthenticatedConnectionContext(context.Background(), &api.AuthenticatedConnectionSignatureRequest{RequestInfo: requestInfo})
+				for _, signature := range GetSignatures(response) {
+					req.Header.Add(AuthHeader, signature)
+				}
+			}
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			if recorder.Code != tC.wantStatusCode {
+				t.Fatalf("ServeHTTP() %s: got status code %d, want %d", tC.desc, recorder.Code, tC.wantStatusCode)
+			}
+			if tC.wantStatusCode != http.StatusNoContent {
+				return
+			}
+			if !bytes.Equal(gotBody, body) {
+				t.Errorf("ServeHTTP() %s: got body %q, want %q", tC.desc, gotBody, body)
+			}
+			if gotResult != nil != tC.wantResult {
+				t.Fatalf("VerificationResultFromContext() %s: got result %v, want %v", tC.desc, gotResult != nil, tC.wantResult)
+			}
+			if gotResult != nil && gotResult.BodyValid != tC.wantValid {
+				t.Errorf("VerificationResultFromContext() %s: got body valid %v, want %v", tC.desc, gotResult.BodyValid, tC.wantValid)
+			}
+			if gotResult != nil && (gotResult.OperationStatus == api.VerificationOperationStatus_VERIFICATION_OPERATION_STATUS_SIGNATORY_DEACTIVATED) != tC.deactivated {
+				t.Errorf("VerificationResultFromContext() %s: got operation status %v, want deactivated %v", tC.desc, gotResult.OperationStatus, tC.deactivated)
+			}
+		})
+	}
+}
+
+func TestNewVerifyingHandler_NilOptions(t *testing.T) {
+	called := false
+	handler := NewVerifyingHandler(nil, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		called = true
+	}), nil)
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "https://verifier.com/bid", nil))
+	if !called {
+		t.Errorf("ServeHTTP() with nil options: request not passed through")
+	}
+}